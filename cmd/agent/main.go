@@ -3,7 +3,10 @@ package main
 
 import (
 	"context"
+	"errors"
 	"log"
+	"net"
+	"net/http"
 	"os"
 	"os/signal"
 	"runtime"
@@ -12,11 +15,19 @@ import (
 	"time"
 
 	"github.com/frolmr/metrics/internal/agent/config"
+	"github.com/frolmr/metrics/internal/agent/hostip"
 	"github.com/frolmr/metrics/internal/agent/metrics"
+	"github.com/frolmr/metrics/internal/agent/push"
 	"github.com/frolmr/metrics/internal/agent/reporter"
 	"github.com/frolmr/metrics/pkg/buildinfo"
 )
 
+const (
+	hostIPRefreshInterval = 30 * time.Second
+	pushReadHeaderTimeout = 5 * time.Second
+	pushShutdownTimeout   = 5 * time.Second
+)
+
 var (
 	buildVersion string
 	buildDate    string
@@ -34,22 +45,41 @@ func main() {
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM, syscall.SIGQUIT)
 	defer stop()
 
-	mtrcs := metrics.NewMetricsCollection()
+	poller, err := metrics.NewPoller(cfg)
+	if err != nil {
+		log.Panic(err)
+	}
+	go poller.Run(ctx)
 
-	var metricsReporter metrics.MetricsReporter
-	switch cfg.Scheme {
-	case "http", "https":
-		metricsReporter = reporter.NewHTTPReporter(cfg)
-	case "grpc":
-		var err error
-		metricsReporter, err = reporter.NewGRPCReporter(cfg)
+	hostIP := hostip.NewResolver(cfg.HostIP, cfg.HostInterface, cfg.HTTPAddress)
+	if err := hostIP.Resolve(); err != nil {
+		log.Println("failed to resolve host IP: ", err)
+	}
+	go hostIP.Watch(ctx, hostIPRefreshInterval)
+
+	metricsReporter, err := reporter.NewReporter(cfg, hostIP)
+	if err != nil {
+		log.Panic(err)
+	}
+	defer metricsReporter.Close()
+
+	pushed := push.NewBuffer(nil)
+	var pushServer *http.Server
+	if cfg.PushAddress != "" {
+		listen, err := net.Listen("tcp", cfg.PushAddress)
 		if err != nil {
 			log.Panic(err)
 		}
-	default:
-		log.Panic("invalid protocol")
+		pushServer = &http.Server{
+			Handler:           push.NewHandler(pushed),
+			ReadHeaderTimeout: pushReadHeaderTimeout,
+		}
+		go func() {
+			if err := pushServer.Serve(listen); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				log.Println("push endpoint failure: ", err)
+			}
+		}()
 	}
-	defer metricsReporter.Close()
 
 	jobsCh := make(chan metrics.MetricsCollection, runtime.GOMAXPROCS(0))
 
@@ -67,22 +97,30 @@ func main() {
 	doneCh := make(chan struct{})
 	defer close(doneCh)
 
-	pollTicker := time.NewTicker(cfg.PollInterval)
-	defer pollTicker.Stop()
-
 	reportTicker := time.NewTicker(cfg.ReportInterval)
 	defer reportTicker.Stop()
 
 	for {
 		select {
-		case <-pollTicker.C:
-			go mtrcs.CollectMetrics()
-			go mtrcs.CollectAdditionalMetrics()
 		case <-reportTicker.C:
-			jobsCh <- *mtrcs
+			jobsCh <- poller.Snapshot().Merge(pushed.Drain())
 		case <-ctx.Done():
+			if pushServer != nil {
+				shutdownCtx, cancel := context.WithTimeout(context.Background(), pushShutdownTimeout)
+				if err := pushServer.Shutdown(shutdownCtx); err != nil {
+					log.Println("push endpoint shutdown failure: ", err)
+				}
+				cancel()
+			}
+			// NOTE: pushed counter deltas exist nowhere else, so they are reported before exit
+			if ms := pushed.Drain(); len(ms.GaugeMetrics)+len(ms.CounterMetrics) != 0 {
+				jobsCh <- ms
+			}
 			close(jobsCh)
 			wg.Wait()
+			for _, s := range poller.Stats() {
+				log.Printf("collector %s: %d polls, %d failed, last took %s", s.Name, s.Runs, s.Failures, s.LastDuration)
+			}
 			log.Println("Agent shutdown gracefully")
 			return
 		}
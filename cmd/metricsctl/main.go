@@ -0,0 +1,45 @@
+// Metricsctl inspects and updates metrics on the server over HTTP or gRPC.
+//
+// Usage:
+//
+//	metricsctl get gauge Alloc
+//	metricsctl list -type counter -prefix Poll
+//	metricsctl push counter PollCount 5
+//	metricsctl -s grpc -a localhost:3200 watch -prefix Heap
+//	metricsctl export -format csv > metrics.csv
+//	metricsctl import -counters overwrite metrics.json
+//	metricsctl ping
+//
+// Server, key and crypto key are taken from -s, -a, -k and -crypto-key flags or from SCHEME, ADDRESS, KEY
+// and CRYPTO_KEY env variables, the same as for the agent, so the tool works against server which checks
+// signature, decrypts requests and checks trusted subnet.
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/frolmr/metrics/internal/metricsctl"
+)
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("metricsctl: ")
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM, syscall.SIGQUIT)
+	defer stop()
+
+	err := metricsctl.Run(ctx, os.Args[1:], os.Stdout, os.Stderr)
+	if err == nil {
+		return
+	}
+	stop()
+	if errors.Is(err, metricsctl.ErrUsage) {
+		log.Fatal("bad usage, see metricsctl -h")
+	}
+	log.Fatal(err)
+}
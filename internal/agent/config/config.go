@@ -3,14 +3,16 @@ package config
 
 import (
 	"crypto/rsa"
-	"crypto/x509"
-	"encoding/pem"
 	"errors"
 	"flag"
+	"fmt"
+	"net"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
+	"github.com/frolmr/metrics/pkg/encryptor"
 	"github.com/frolmr/metrics/pkg/fileconfig"
 	"github.com/frolmr/metrics/pkg/formatter"
 )
@@ -25,12 +27,18 @@ const (
 	keyEnv                = "KEY"
 	rateLimitEnvName      = "RATE_LIMIT"
 	cryptoKeyEnvName      = "CRYPTO_KEY"
+	hostIPEnvName         = "HOST_IP"
+	hostInterfaceEnvName  = "HOST_INTERFACE"
+	grpcStreamEnvName     = "GRPC_STREAM"
+	pushAddressEnvName    = "PUSH_ADDRESS"
+	collectorsEnvName     = "COLLECTORS"
 
 	defaultScheme            = "http"
 	defaultAddress           = "localhost:8080"
 	defaultReportIntervalSec = 10
 	defaultPollIntervalSec   = 2
 	defaultRateLimit         = 5
+	defaultGRPCStream        = false
 )
 
 // Config atructure to store agents configuration.
@@ -46,6 +54,23 @@ type Config struct {
 	RateLimit int
 
 	CryptoKey *rsa.PublicKey
+
+	HostIP        string
+	HostInterface string
+
+	GRPCStream bool
+
+	PushAddress string
+
+	// Collectors holds settings of collectors mentioned in config, the rest are enabled and polled every PollInterval.
+	Collectors map[string]CollectorConfig
+}
+
+// CollectorConfig is per-collector setting.
+type CollectorConfig struct {
+	Disabled bool
+	// PollInterval overrides agent poll interval if not zero.
+	PollInterval time.Duration
 }
 
 // NewConfig setups agents config: read flags and env variables.
@@ -61,6 +86,15 @@ func NewConfig() (*Config, error) {
 	keyValues := make([]string, 0, maxParamCount)
 	cryptoKeyValues := make([]string, 0, maxParamCount)
 
+	hostIPValues := make([]string, 0, maxParamCount)
+	hostInterfaceValues := make([]string, 0, maxParamCount)
+
+	grpcStreamValues := make([]bool, 0, maxParamCount)
+
+	pushAddressValues := make([]string, 0, maxParamCount)
+
+	collectorsValues := make([]string, 0, maxParamCount)
+
 	var (
 		serverScheme      string
 		serverHTTPAddress string
@@ -70,6 +104,11 @@ func NewConfig() (*Config, error) {
 		rateLimit         int
 		cryptoKeyPath     string
 		configFile        string
+		hostIP            string
+		hostInterface     string
+		grpcStream        bool
+		pushAddress       string
+		collectors        string
 	)
 
 	schemeValues = append(schemeValues, defaultScheme)
@@ -77,6 +116,7 @@ func NewConfig() (*Config, error) {
 	reportIntervalValues = append(reportIntervalValues, defaultReportIntervalSec)
 	pollIntervalValues = append(pollIntervalValues, defaultPollIntervalSec)
 	rateLimitValues = append(rateLimitValues, defaultRateLimit)
+	grpcStreamValues = append(grpcStreamValues, defaultGRPCStream)
 
 	flag.StringVar(&serverScheme, "s", "", "server scheme: http or https")
 	flag.StringVar(&serverHTTPAddress, "a", "", "address and port of the server")
@@ -86,6 +126,11 @@ func NewConfig() (*Config, error) {
 	flag.StringVar(&key, "k", "", "encryption key")
 	flag.StringVar(&cryptoKeyPath, "crypto-key", "", "public crypto key path")
 	flag.StringVar(&configFile, "config", "", "path to config file")
+	flag.StringVar(&hostIP, "host-ip", "", "host IP reported to server")
+	flag.StringVar(&hostInterface, "host-iface", "", "network interface to take host IP from")
+	flag.BoolVar(&grpcStream, "grpc-stream", false, "report metrics over one gRPC stream")
+	flag.StringVar(&pushAddress, "push-address", "", "local address to accept metrics pushed by applications, disabled if empty")
+	flag.StringVar(&collectors, "collectors", "", "collectors settings: comma separated name=off or name=interval, e.g. system=off,runtime=1s")
 	flag.Parse()
 
 	if configFile != "" {
@@ -115,6 +160,21 @@ func NewConfig() (*Config, error) {
 			if fileCfg.CryptoKey != "" {
 				cryptoKeyValues = append(cryptoKeyValues, fileCfg.CryptoKey)
 			}
+			if fileCfg.HostIP != "" {
+				hostIPValues = append(hostIPValues, fileCfg.HostIP)
+			}
+			if fileCfg.HostInterface != "" {
+				hostInterfaceValues = append(hostInterfaceValues, fileCfg.HostInterface)
+			}
+			if fileCfg.GRPCStream {
+				grpcStreamValues = append(grpcStreamValues, fileCfg.GRPCStream)
+			}
+			if fileCfg.PushAddress != "" {
+				pushAddressValues = append(pushAddressValues, fileCfg.PushAddress)
+			}
+			if fileCfg.Collectors != "" {
+				collectorsValues = append(collectorsValues, fileCfg.Collectors)
+			}
 		}
 	}
 
@@ -146,6 +206,26 @@ func NewConfig() (*Config, error) {
 		cryptoKeyValues = append(cryptoKeyValues, cryptoKeyPath)
 	}
 
+	if hostIP != "" {
+		hostIPValues = append(hostIPValues, hostIP)
+	}
+
+	if hostInterface != "" {
+		hostInterfaceValues = append(hostInterfaceValues, hostInterface)
+	}
+
+	if grpcStream {
+		grpcStreamValues = append(grpcStreamValues, grpcStream)
+	}
+
+	if pushAddress != "" {
+		pushAddressValues = append(pushAddressValues, pushAddress)
+	}
+
+	if collectors != "" {
+		collectorsValues = append(collectorsValues, collectors)
+	}
+
 	if serverSchemeEnv := os.Getenv(schemeEnvName); serverSchemeEnv != "" {
 		schemeValues = append(schemeValues, serverSchemeEnv)
 	}
@@ -178,6 +258,26 @@ func NewConfig() (*Config, error) {
 		cryptoKeyValues = append(cryptoKeyValues, cryptoKeyEnv)
 	}
 
+	if hostIPEnv := os.Getenv(hostIPEnvName); hostIPEnv != "" {
+		hostIPValues = append(hostIPValues, hostIPEnv)
+	}
+
+	if hostInterfaceEnv := os.Getenv(hostInterfaceEnvName); hostInterfaceEnv != "" {
+		hostInterfaceValues = append(hostInterfaceValues, hostInterfaceEnv)
+	}
+
+	if grpcStreamEnv, err := strconv.ParseBool(os.Getenv(grpcStreamEnvName)); err == nil {
+		grpcStreamValues = append(grpcStreamValues, grpcStreamEnv)
+	}
+
+	if pushAddressEnv := os.Getenv(pushAddressEnvName); pushAddressEnv != "" {
+		pushAddressValues = append(pushAddressValues, pushAddressEnv)
+	}
+
+	if collectorsEnv := os.Getenv(collectorsEnvName); collectorsEnv != "" {
+		collectorsValues = append(collectorsValues, collectorsEnv)
+	}
+
 	schemeConfig := schemeValues[len(schemeValues)-1]
 	if err := formatter.CheckSchemeFormat(schemeConfig); err != nil {
 		return nil, err
@@ -207,6 +307,34 @@ func NewConfig() (*Config, error) {
 		return nil, err
 	}
 
+	var hostIPConfig string
+	if len(hostIPValues) != 0 {
+		hostIPConfig = hostIPValues[len(hostIPValues)-1]
+		if net.ParseIP(hostIPConfig) == nil {
+			return nil, errors.New("bad host IP format")
+		}
+	}
+
+	var hostInterfaceConfig string
+	if len(hostInterfaceValues) != 0 {
+		hostInterfaceConfig = hostInterfaceValues[len(hostInterfaceValues)-1]
+	}
+
+	var pushAddressConfig string
+	if len(pushAddressValues) != 0 {
+		pushAddressConfig = pushAddressValues[len(pushAddressValues)-1]
+		if err := formatter.CheckAddrFormat(pushAddressConfig); err != nil {
+			return nil, err
+		}
+	}
+
+	var collectorsConfig map[string]CollectorConfig
+	if len(collectorsValues) != 0 {
+		if collectorsConfig, err = parseCollectors(collectorsValues[len(collectorsValues)-1]); err != nil {
+			return nil, err
+		}
+	}
+
 	return &Config{
 		Scheme:         schemeConfig,
 		HTTPAddress:    addressConfig,
@@ -215,28 +343,55 @@ func NewConfig() (*Config, error) {
 		Key:            keyConfig,
 		RateLimit:      rateLimitConfig,
 		CryptoKey:      cryptoKey,
+		HostIP:         hostIPConfig,
+		HostInterface:  hostInterfaceConfig,
+		GRPCStream:     grpcStreamValues[len(grpcStreamValues)-1],
+		PushAddress:    pushAddressConfig,
+		Collectors:     collectorsConfig,
 	}, nil
 }
 
-func loadPublicKey(publicKeyPath string) (*rsa.PublicKey, error) {
-	if publicKeyPath == "" {
-		return nil, nil
-	}
+// parseCollectors parses comma separated name=off, name=on or name=interval settings, interval is in seconds
+// or has unit like 500ms.
+func parseCollectors(spec string) (map[string]CollectorConfig, error) {
+	collectors := make(map[string]CollectorConfig)
 
-	keyBytes, err := os.ReadFile(publicKeyPath)
-	if err != nil {
-		return nil, err
-	}
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		name, value, ok := strings.Cut(item, "=")
+		if name == "" || !ok {
+			return nil, fmt.Errorf("bad collector setting %q, want name=off or name=interval", item)
+		}
 
-	block, _ := pem.Decode(keyBytes)
-	if block == nil {
-		return nil, errors.New("failed to parse PEM block containing the public key")
+		var cc CollectorConfig
+		switch value {
+		case "off":
+			cc.Disabled = true
+		case "on":
+		default:
+			interval, err := parseInterval(value)
+			if err != nil || interval <= 0 {
+				return nil, fmt.Errorf("bad poll interval of collector %s: %q", name, value)
+			}
+			cc.PollInterval = interval
+		}
+		collectors[name] = cc
 	}
 
-	pub, err := x509.ParsePKIXPublicKey(block.Bytes)
-	if err != nil {
-		return nil, err
+	return collectors, nil
+}
+
+func parseInterval(value string) (time.Duration, error) {
+	if sec, err := strconv.Atoi(value); err == nil {
+		return time.Duration(sec) * time.Second, nil
 	}
+	return time.ParseDuration(value)
+}
 
-	return pub.(*rsa.PublicKey), nil
+func loadPublicKey(publicKeyPath string) (*rsa.PublicKey, error) {
+	return encryptor.LoadPublicKey(publicKeyPath)
 }
@@ -562,3 +562,181 @@ func TestAgentConfigPriority(t *testing.T) {
 		})
 	}
 }
+
+func TestParseHostIPFlags(t *testing.T) {
+	tests := []struct {
+		name          string
+		args          []string
+		envName       string
+		envValue      string
+		wantIP        string
+		wantInterface string
+		wantErr       bool
+	}{
+		{
+			name: "defaults",
+			args: []string{},
+		},
+		{
+			name:   "explicit IP from flag",
+			args:   []string{"-host-ip", "10.0.0.5"},
+			wantIP: "10.0.0.5",
+		},
+		{
+			name:     "env overrides flag",
+			args:     []string{"-host-ip", "10.0.0.5"},
+			envName:  "HOST_IP",
+			envValue: "10.0.0.6",
+			wantIP:   "10.0.0.6",
+		},
+		{
+			name:          "interface from env",
+			args:          []string{},
+			envName:       "HOST_INTERFACE",
+			envValue:      "eth0",
+			wantInterface: "eth0",
+		},
+		{
+			name:    "invalid IP",
+			args:    []string{"-host-ip", "not.an.ip"},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if test.envName != "" {
+				os.Setenv(test.envName, test.envValue)
+				defer os.Unsetenv(test.envName)
+			}
+
+			os.Args = append([]string{"cmd"}, test.args...)
+			flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+
+			config, err := NewConfig()
+			if test.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, test.wantIP, config.HostIP)
+			assert.Equal(t, test.wantInterface, config.HostInterface)
+		})
+	}
+}
+
+func TestParsePushAddressFlag(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		envValue string
+		want     string
+		wantErr  bool
+	}{
+		{
+			name: "disabled by default",
+			args: []string{},
+		},
+		{
+			name: "address from flag",
+			args: []string{"-push-address", "localhost:8125"},
+			want: "localhost:8125",
+		},
+		{
+			name:     "env overrides flag",
+			args:     []string{"-push-address", "localhost:8125"},
+			envValue: "127.0.0.1:9000",
+			want:     "127.0.0.1:9000",
+		},
+		{
+			name:    "invalid address",
+			args:    []string{"-push-address", "localhost"},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if test.envValue != "" {
+				os.Setenv("PUSH_ADDRESS", test.envValue)
+				defer os.Unsetenv("PUSH_ADDRESS")
+			}
+
+			os.Args = append([]string{"cmd"}, test.args...)
+			flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+
+			config, err := NewConfig()
+			if test.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, test.want, config.PushAddress)
+		})
+	}
+}
+
+func TestParseCollectorsFlag(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		envValue string
+		want     map[string]CollectorConfig
+		wantErr  bool
+	}{
+		{
+			name: "all enabled by default",
+			args: []string{},
+		},
+		{
+			name: "disabled and custom interval",
+			args: []string{"-collectors", "system=off, runtime=500ms"},
+			want: map[string]CollectorConfig{
+				"system":  {Disabled: true},
+				"runtime": {PollInterval: 500 * time.Millisecond},
+			},
+		},
+		{
+			name:     "env overrides flag, interval in seconds",
+			args:     []string{"-collectors", "system=off"},
+			envValue: "system=on,runtime=10",
+			want: map[string]CollectorConfig{
+				"system":  {},
+				"runtime": {PollInterval: 10 * time.Second},
+			},
+		},
+		{
+			name:    "missing setting",
+			args:    []string{"-collectors", "system"},
+			wantErr: true,
+		},
+		{
+			name:    "bad interval",
+			args:    []string{"-collectors", "system=-1s"},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if test.envValue != "" {
+				os.Setenv("COLLECTORS", test.envValue)
+				defer os.Unsetenv("COLLECTORS")
+			}
+
+			os.Args = append([]string{"cmd"}, test.args...)
+			flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+
+			config, err := NewConfig()
+			if test.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, test.want, config.Collectors)
+		})
+	}
+}
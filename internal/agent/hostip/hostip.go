@@ -0,0 +1,226 @@
+// Package hostip resolves the agent host IP that is reported to the server.
+package hostip
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// UnknownIP is reported when host IP could not be resolved.
+	UnknownIP = "unknown"
+)
+
+// Strategy defines how the host IP is resolved.
+type Strategy string
+
+const (
+	// StrategyExplicit uses IP given in config as is.
+	StrategyExplicit Strategy = "explicit"
+	// StrategyInterface takes first IPv4 (or IPv6 if no IPv4) address of the named interface.
+	StrategyInterface Strategy = "interface"
+	// StrategyRoute takes local address of the route to the server, no packets are sent.
+	StrategyRoute Strategy = "route"
+)
+
+// NOTE: special for test
+var (
+	interfaceByName = net.InterfaceByName
+	interfaceAddrs  = net.InterfaceAddrs
+)
+
+// Resolver holds resolved host IP and refreshes it on network interfaces change.
+type Resolver struct {
+	strategy      Strategy
+	explicitIP    string
+	interfaceName string
+	serverAddress string
+
+	mu          sync.RWMutex
+	ip          string
+	fingerprint string
+}
+
+// NewResolver creates resolver choosing strategy by settings given:
+// explicit IP first, then interface name, then route to the server address.
+func NewResolver(explicitIP, interfaceName, serverAddress string) *Resolver {
+	r := &Resolver{
+		explicitIP:    explicitIP,
+		interfaceName: interfaceName,
+		serverAddress: serverAddress,
+		ip:            UnknownIP,
+	}
+
+	switch {
+	case explicitIP != "":
+		r.strategy = StrategyExplicit
+	case interfaceName != "":
+		r.strategy = StrategyInterface
+	default:
+		r.strategy = StrategyRoute
+	}
+
+	return r
+}
+
+// Strategy returns strategy chosen by resolver.
+func (r *Resolver) Strategy() Strategy {
+	return r.strategy
+}
+
+// IP returns last resolved host IP or UnknownIP.
+func (r *Resolver) IP() string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.ip
+}
+
+// Resolve resolves host IP with configured strategy and stores it.
+func (r *Resolver) Resolve() error {
+	fingerprint, _ := addrsFingerprint()
+
+	ip, err := r.resolve()
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	r.ip = ip
+	r.fingerprint = fingerprint
+	r.mu.Unlock()
+
+	return nil
+}
+
+// Watch re-resolves host IP every time set of interface addresses changes.
+// It blocks until ctx is done, explicit IP is never refreshed.
+func (r *Resolver) Watch(ctx context.Context, interval time.Duration) {
+	if r.strategy == StrategyExplicit {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			r.refresh()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (r *Resolver) refresh() {
+	fingerprint, err := addrsFingerprint()
+	if err != nil {
+		log.Println("failed to list interface addresses: ", err)
+		return
+	}
+
+	r.mu.RLock()
+	changed := fingerprint != r.fingerprint
+	r.mu.RUnlock()
+
+	if !changed {
+		return
+	}
+
+	if err := r.Resolve(); err != nil {
+		log.Println("failed to refresh host IP: ", err)
+		return
+	}
+	log.Println("host IP refreshed: ", r.IP())
+}
+
+func (r *Resolver) resolve() (string, error) {
+	switch r.strategy {
+	case StrategyExplicit:
+		return resolveExplicit(r.explicitIP)
+	case StrategyInterface:
+		return resolveInterface(r.interfaceName)
+	default:
+		return resolveRoute(r.serverAddress)
+	}
+}
+
+func resolveExplicit(explicitIP string) (string, error) {
+	ip := net.ParseIP(explicitIP)
+	if ip == nil {
+		return "", fmt.Errorf("invalid host IP: %s", explicitIP)
+	}
+	return ip.String(), nil
+}
+
+func resolveInterface(name string) (string, error) {
+	iface, err := interfaceByName(name)
+	if err != nil {
+		return "", err
+	}
+
+	addrs, err := iface.Addrs()
+	if err != nil {
+		return "", err
+	}
+
+	var fallback net.IP
+	for _, addr := range addrs {
+		ipNet, ok := addr.(*net.IPNet)
+		if !ok {
+			continue
+		}
+		if ipNet.IP.To4() != nil {
+			return ipNet.IP.String(), nil
+		}
+		if fallback == nil {
+			fallback = ipNet.IP
+		}
+	}
+
+	if fallback == nil {
+		return "", fmt.Errorf("no IP address on interface %s", name)
+	}
+	return fallback.String(), nil
+}
+
+func resolveRoute(serverAddress string) (string, error) {
+	if serverAddress == "" {
+		return "", errors.New("no server address to route to")
+	}
+
+	// NOTE: dialing UDP only selects route and local address, nothing is sent
+	conn, err := net.Dial("udp", serverAddress)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+
+	localAddr, ok := conn.LocalAddr().(*net.UDPAddr)
+	if !ok {
+		return "", errors.New("unexpected local address type")
+	}
+	return localAddr.IP.String(), nil
+}
+
+func addrsFingerprint() (string, error) {
+	addrs, err := interfaceAddrs()
+	if err != nil {
+		return "", err
+	}
+
+	parts := make([]string, 0, len(addrs))
+	for _, addr := range addrs {
+		parts = append(parts, addr.String())
+	}
+	sort.Strings(parts)
+
+	return strings.Join(parts, ","), nil
+}
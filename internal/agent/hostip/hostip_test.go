@@ -0,0 +1,121 @@
+package hostip
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewResolverStrategy(t *testing.T) {
+	tests := []struct {
+		name          string
+		explicitIP    string
+		interfaceName string
+		want          Strategy
+	}{
+		{name: "explicit wins", explicitIP: "10.0.0.1", interfaceName: "eth0", want: StrategyExplicit},
+		{name: "interface", interfaceName: "eth0", want: StrategyInterface},
+		{name: "route by default", want: StrategyRoute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewResolver(tt.explicitIP, tt.interfaceName, "localhost:8080")
+			assert.Equal(t, tt.want, r.Strategy())
+			assert.Equal(t, UnknownIP, r.IP())
+		})
+	}
+}
+
+func TestResolveExplicit(t *testing.T) {
+	r := NewResolver("192.168.1.10", "", "")
+	require.NoError(t, r.Resolve())
+	assert.Equal(t, "192.168.1.10", r.IP())
+
+	r = NewResolver("not.an.ip", "", "")
+	require.Error(t, r.Resolve())
+	assert.Equal(t, UnknownIP, r.IP())
+}
+
+func TestResolveRoute(t *testing.T) {
+	r := NewResolver("", "", "127.0.0.1:8080")
+	require.NoError(t, r.Resolve())
+	assert.Equal(t, "127.0.0.1", r.IP())
+}
+
+func TestResolveInterface(t *testing.T) {
+	ifaces, err := net.Interfaces()
+	require.NoError(t, err)
+
+	var loopback string
+	for _, iface := range ifaces {
+		if iface.Flags&net.FlagLoopback != 0 {
+			loopback = iface.Name
+			break
+		}
+	}
+	if loopback == "" {
+		t.Skip("no loopback interface")
+	}
+
+	r := NewResolver("", loopback, "")
+	require.NoError(t, r.Resolve())
+	assert.True(t, net.ParseIP(r.IP()).IsLoopback())
+
+	r = NewResolver("", "no-such-interface0", "")
+	require.Error(t, r.Resolve())
+}
+
+func TestWatchRefreshesOnChange(t *testing.T) {
+	origInterfaceAddrs := interfaceAddrs
+	defer func() { interfaceAddrs = origInterfaceAddrs }()
+
+	addrs := []net.Addr{&net.IPNet{IP: net.ParseIP("10.0.0.1"), Mask: net.CIDRMask(8, 32)}}
+	interfaceAddrs = func() ([]net.Addr, error) {
+		return addrs, nil
+	}
+
+	r := NewResolver("", "", "127.0.0.1:8080")
+	require.NoError(t, r.Resolve())
+
+	r.mu.Lock()
+	r.ip = "stale"
+	r.mu.Unlock()
+
+	r.refresh()
+	assert.Equal(t, "stale", r.IP(), "no change in addresses - no re-resolve")
+
+	addrs = append(addrs, &net.IPNet{IP: net.ParseIP("10.0.0.2"), Mask: net.CIDRMask(8, 32)})
+	r.refresh()
+	assert.Equal(t, "127.0.0.1", r.IP())
+
+	interfaceAddrs = func() ([]net.Addr, error) {
+		return nil, errors.New("mock error")
+	}
+	r.refresh()
+	assert.Equal(t, "127.0.0.1", r.IP())
+}
+
+func TestWatchStopsOnContextDone(t *testing.T) {
+	r := NewResolver("", "", "127.0.0.1:8080")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		r.Watch(ctx, time.Millisecond)
+		close(done)
+	}()
+
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("watch did not stop")
+	}
+}
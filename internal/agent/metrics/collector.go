@@ -2,10 +2,10 @@
 package metrics
 
 import (
+	"context"
 	"crypto/rand"
 	"errors"
 	"fmt"
-	"log"
 	"math"
 	"math/big"
 	"runtime"
@@ -14,12 +14,26 @@ import (
 	"github.com/shirou/gopsutil/mem"
 )
 
-type MetricsCollector interface {
-	CollectMetrics() (map[string]int64, map[string]float64)
+// Names of collectors built into agent.
+const (
+	RuntimeCollectorName = "runtime"
+	SystemCollectorName  = "system"
+)
+
+func init() {
+	Register(runtimeCollector{})
+	Register(systemCollector{})
+}
+
+// runtimeCollector polls Go runtime memory stats of the agent together with PollCount and RandomValue.
+type runtimeCollector struct{}
+
+func (runtimeCollector) Name() string {
+	return RuntimeCollectorName
 }
 
-// CollectMetrics functions collects metrics from host.
-func (mc *MetricsCollection) CollectMetrics() (counterMetrics map[string]int64, gaugeMetrics map[string]float64) {
+func (runtimeCollector) Collect(ctx context.Context) (MetricsCollection, error) {
+	mc := NewMetricsCollection()
 	pollCount++
 
 	var ms runtime.MemStats
@@ -56,7 +70,7 @@ func (mc *MetricsCollection) CollectMetrics() (counterMetrics map[string]int64,
 	mc.GaugeMetrics["Sys"] = float64(ms.Sys)
 	mc.GaugeMetrics["TotalAlloc"] = float64(ms.TotalAlloc)
 
-	return mc.CounterMetrics, mc.GaugeMetrics
+	return *mc, nil
 }
 
 // NOTE: special for test! dunno if it's ok
@@ -65,25 +79,38 @@ var (
 	cpuPercent       = cpu.Percent
 )
 
-// CollectAdditionalMetrics functions collects additional metrics from host.
-func (mc *MetricsCollection) CollectAdditionalMetrics() {
+// systemCollector polls memory and CPU utilization of the host, metrics that could be read are returned
+// together with joined failures.
+type systemCollector struct{}
+
+func (systemCollector) Name() string {
+	return SystemCollectorName
+}
+
+func (systemCollector) Collect(ctx context.Context) (MetricsCollection, error) {
+	ms := *NewMetricsCollection()
+
+	var errs []error
+
 	m, err := memVirtualMemory()
 	if err != nil {
-		log.Println("cant get RAM metric")
+		errs = append(errs, fmt.Errorf("cant get RAM metric: %w", err))
 	} else {
-		mc.GaugeMetrics["TotalMemory"] = float64(m.Total)
-		mc.GaugeMetrics["FreeMemory"] = float64(m.Free)
+		ms.GaugeMetrics["TotalMemory"] = float64(m.Total)
+		ms.GaugeMetrics["FreeMemory"] = float64(m.Free)
 	}
 
 	c, err := cpuPercent(0, true)
 	if err != nil {
-		log.Println("cant get cpu metric")
+		errs = append(errs, fmt.Errorf("cant get cpu metric: %w", err))
 	} else {
 		for i, val := range c {
 			key := fmt.Sprintf("CPUutilization%d", i)
-			mc.GaugeMetrics[key] = float64(val)
+			ms.GaugeMetrics[key] = float64(val)
 		}
 	}
+
+	return ms, errors.Join(errs...)
 }
 
 func randomFloat64() (float64, error) {
@@ -1,22 +1,22 @@
 package metrics
 
 import (
+	"context"
 	"testing"
 	"time"
 
 	"github.com/shirou/gopsutil/mem"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestMetricsCollection(t *testing.T) {
-	t.Run("CollectMetrics", func(t *testing.T) {
-		mc := &MetricsCollection{
-			CounterMetrics: make(map[string]int64),
-			GaugeMetrics:   make(map[string]float64),
-		}
+	t.Run("runtime", func(t *testing.T) {
 		pollCount = 0
 
-		counter, gauge := mc.CollectMetrics()
+		mc, err := runtimeCollector{}.Collect(context.Background())
+		require.NoError(t, err)
+		counter, gauge := mc.CounterMetrics, mc.GaugeMetrics
 
 		assert.Equal(t, int64(1), counter["PollCount"])
 		assert.Contains(t, gauge, "Alloc")
@@ -49,12 +49,7 @@ func TestMetricsCollection(t *testing.T) {
 		assert.NotContains(t, gauge, "SomeOtherMetric")
 	})
 
-	t.Run("CollectAdditionalMetrics", func(t *testing.T) {
-		mc := &MetricsCollection{
-			CounterMetrics: make(map[string]int64),
-			GaugeMetrics:   make(map[string]float64),
-		}
-
+	t.Run("system", func(t *testing.T) {
 		origMemVirtualMemory := memVirtualMemory
 		origCPUPercent := cpuPercent
 
@@ -73,7 +68,8 @@ func TestMetricsCollection(t *testing.T) {
 
 		defer func() { cpuPercent = origCPUPercent }()
 
-		mc.CollectAdditionalMetrics()
+		mc, err := systemCollector{}.Collect(context.Background())
+		require.NoError(t, err)
 
 		assert.Equal(t, float64(100), mc.GaugeMetrics["TotalMemory"])
 		assert.Equal(t, float64(50), mc.GaugeMetrics["FreeMemory"])
@@ -82,12 +78,7 @@ func TestMetricsCollection(t *testing.T) {
 		assert.Equal(t, 30.7, mc.GaugeMetrics["CPUutilization2"])
 	})
 
-	t.Run("CollectAdditionalMetrics_ErrorHandling", func(t *testing.T) {
-		mc := &MetricsCollection{
-			CounterMetrics: make(map[string]int64),
-			GaugeMetrics:   make(map[string]float64),
-		}
-
+	t.Run("system error handling", func(t *testing.T) {
 		origMemVirtualMemory := memVirtualMemory
 		origCPUPercent := cpuPercent
 
@@ -97,11 +88,13 @@ func TestMetricsCollection(t *testing.T) {
 		defer func() { memVirtualMemory = origMemVirtualMemory }()
 
 		cpuPercent = func(interval time.Duration, percpu bool) ([]float64, error) {
-			return nil, assert.AnError
+			return []float64{10.5}, nil
 		}
 		defer func() { cpuPercent = origCPUPercent }()
 
-		mc.CollectAdditionalMetrics()
+		mc, err := systemCollector{}.Collect(context.Background())
+		require.ErrorIs(t, err, assert.AnError)
+		assert.Equal(t, 10.5, mc.GaugeMetrics["CPUutilization0"], "metrics read before failure are kept")
 
 		_, ok := mc.GaugeMetrics["TotalMemory"]
 		assert.False(t, ok)
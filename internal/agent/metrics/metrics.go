@@ -16,3 +16,26 @@ func NewMetricsCollection() *MetricsCollection {
 		GaugeMetrics:   make(map[string]float64),
 	}
 }
+
+// Merge returns copy of the collection with pushed gauges set and pushed counter deltas added.
+func (mc MetricsCollection) Merge(pushed MetricsCollection) MetricsCollection {
+	merged := MetricsCollection{
+		CounterMetrics: make(map[string]int64, len(mc.CounterMetrics)+len(pushed.CounterMetrics)),
+		GaugeMetrics:   make(map[string]float64, len(mc.GaugeMetrics)+len(pushed.GaugeMetrics)),
+	}
+
+	for name, value := range mc.GaugeMetrics {
+		merged.GaugeMetrics[name] = value
+	}
+	for name, value := range pushed.GaugeMetrics {
+		merged.GaugeMetrics[name] = value
+	}
+	for name, delta := range mc.CounterMetrics {
+		merged.CounterMetrics[name] = delta
+	}
+	for name, delta := range pushed.CounterMetrics {
+		merged.CounterMetrics[name] += delta
+	}
+
+	return merged
+}
@@ -36,3 +36,21 @@ func TestNewMetricsCollection(t *testing.T) {
 		})
 	}
 }
+
+func TestMerge(t *testing.T) {
+	collected := MetricsCollection{
+		CounterMetrics: map[string]int64{"PollCount": 5},
+		GaugeMetrics:   map[string]float64{"Alloc": 1, "queue": 2},
+	}
+	pushed := MetricsCollection{
+		CounterMetrics: map[string]int64{"PollCount": 1, "orders": 3},
+		GaugeMetrics:   map[string]float64{"queue": 7},
+	}
+
+	merged := collected.Merge(pushed)
+
+	assert.Equal(t, map[string]int64{"PollCount": 6, "orders": 3}, merged.CounterMetrics)
+	assert.Equal(t, map[string]float64{"Alloc": 1, "queue": 7}, merged.GaugeMetrics)
+	assert.Equal(t, int64(5), collected.CounterMetrics["PollCount"], "collection itself is not changed")
+	assert.Equal(t, 2.0, collected.GaugeMetrics["queue"])
+}
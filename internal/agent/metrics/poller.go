@@ -0,0 +1,167 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/frolmr/metrics/internal/agent/config"
+)
+
+// CollectorStats describes polls of one collector.
+type CollectorStats struct {
+	Name         string
+	Interval     time.Duration
+	Runs         int64
+	Failures     int64
+	LastDuration time.Duration
+	LastError    error
+}
+
+type pollJob struct {
+	collector Collector
+	interval  time.Duration
+}
+
+// Poller polls enabled collectors each with its own interval and keeps the latest metrics of all of them.
+type Poller struct {
+	jobs []pollJob
+
+	mu      sync.Mutex
+	metrics MetricsCollection
+	stats   map[string]*CollectorStats
+}
+
+// NewPoller function is constructor for poller of registered collectors configured with cfg.
+func NewPoller(cfg *config.Config) (*Poller, error) {
+	return newPoller(Registered(), cfg.PollInterval, cfg.Collectors)
+}
+
+func newPoller(collectors []Collector, pollInterval time.Duration, settings map[string]config.CollectorConfig) (*Poller, error) {
+	known := make(map[string]bool, len(collectors))
+	for _, c := range collectors {
+		known[c.Name()] = true
+	}
+	for name := range settings {
+		if !known[name] {
+			return nil, fmt.Errorf("unknown collector %s", name)
+		}
+	}
+
+	p := &Poller{
+		metrics: *NewMetricsCollection(),
+		stats:   make(map[string]*CollectorStats),
+	}
+	for _, c := range collectors {
+		cc := settings[c.Name()]
+		if cc.Disabled {
+			continue
+		}
+
+		interval := pollInterval
+		if cc.PollInterval != 0 {
+			interval = cc.PollInterval
+		}
+		p.jobs = append(p.jobs, pollJob{collector: c, interval: interval})
+		p.stats[c.Name()] = &CollectorStats{Name: c.Name(), Interval: interval}
+	}
+
+	return p, nil
+}
+
+// Run polls collectors until ctx is done.
+func (p *Poller) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, job := range p.jobs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p.runJob(ctx, job)
+		}()
+	}
+	wg.Wait()
+}
+
+func (p *Poller) runJob(ctx context.Context, job pollJob) {
+	ticker := time.NewTicker(job.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			p.poll(ctx, job)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// poll runs collector once, its failure or panic affects neither other collectors nor metrics polled before.
+func (p *Poller) poll(ctx context.Context, job pollJob) {
+	name := job.collector.Name()
+
+	pollCtx, cancel := context.WithTimeout(ctx, job.interval)
+	defer cancel()
+
+	start := time.Now()
+	ms, err := collect(pollCtx, job.collector)
+	duration := time.Since(start)
+
+	if err != nil {
+		log.Printf("collector %s failed: %v", name, err)
+	}
+	if duration > job.interval {
+		log.Printf("collector %s took %s, longer than poll interval %s", name, duration, job.interval)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for key, value := range ms.GaugeMetrics {
+		p.metrics.GaugeMetrics[key] = value
+	}
+	for key, value := range ms.CounterMetrics {
+		p.metrics.CounterMetrics[key] = value
+	}
+
+	stats := p.stats[name]
+	stats.Runs++
+	stats.LastDuration = duration
+	stats.LastError = err
+	if err != nil {
+		stats.Failures++
+	}
+}
+
+func collect(ctx context.Context, c Collector) (ms MetricsCollection, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			ms, err = MetricsCollection{}, fmt.Errorf("collector panic: %v", r)
+		}
+	}()
+
+	return c.Collect(ctx)
+}
+
+// Snapshot returns copy of the latest metrics polled.
+func (p *Poller) Snapshot() MetricsCollection {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.metrics.Merge(MetricsCollection{})
+}
+
+// Stats returns polls statistics of enabled collectors sorted by name.
+func (p *Poller) Stats() []CollectorStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	stats := make([]CollectorStats, 0, len(p.jobs))
+	for _, job := range p.jobs {
+		stats = append(stats, *p.stats[job.collector.Name()])
+	}
+
+	return stats
+}
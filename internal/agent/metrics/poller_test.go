@@ -0,0 +1,138 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/frolmr/metrics/internal/agent/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeCollector struct {
+	name    string
+	collect func(ctx context.Context) (MetricsCollection, error)
+	runs    atomic.Int64
+}
+
+func (c *fakeCollector) Name() string {
+	return c.name
+}
+
+func (c *fakeCollector) Collect(ctx context.Context) (MetricsCollection, error) {
+	c.runs.Add(1)
+	return c.collect(ctx)
+}
+
+func gaugeCollector(name string, value float64) *fakeCollector {
+	return &fakeCollector{name: name, collect: func(context.Context) (MetricsCollection, error) {
+		return MetricsCollection{GaugeMetrics: map[string]float64{name: value}}, nil
+	}}
+}
+
+func TestRegistered(t *testing.T) {
+	var names []string
+	for _, c := range Registered() {
+		names = append(names, c.Name())
+	}
+	assert.Equal(t, []string{RuntimeCollectorName, SystemCollectorName}, names)
+
+	assert.Panics(t, func() { Register(runtimeCollector{}) }, "name is taken")
+}
+
+func TestPollerIsolatesFailures(t *testing.T) {
+	good := gaugeCollector("good", 1)
+	partial := &fakeCollector{name: "partial", collect: func(context.Context) (MetricsCollection, error) {
+		return MetricsCollection{GaugeMetrics: map[string]float64{"partial": 2}}, errors.New("no CPU metric")
+	}}
+	panicking := &fakeCollector{name: "panicking", collect: func(context.Context) (MetricsCollection, error) {
+		panic("boom")
+	}}
+
+	p, err := newPoller([]Collector{good, panicking, partial}, 5*time.Millisecond, nil)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		p.Run(ctx)
+		close(done)
+	}()
+
+	require.Eventually(t, func() bool {
+		for _, s := range p.Stats() {
+			if s.Runs < 2 {
+				return false
+			}
+		}
+		return true
+	}, time.Second, 5*time.Millisecond)
+	cancel()
+	<-done
+
+	assert.Equal(t, map[string]float64{"good": 1, "partial": 2}, p.Snapshot().GaugeMetrics,
+		"metrics of partial failure are kept, panic stops nothing")
+
+	for _, s := range p.Stats() {
+		switch s.Name {
+		case "good":
+			assert.Zero(t, s.Failures)
+			assert.NoError(t, s.LastError)
+		default:
+			assert.Equal(t, s.Runs, s.Failures, s.Name)
+			assert.Error(t, s.LastError, s.Name)
+		}
+	}
+}
+
+func TestPollerSettings(t *testing.T) {
+	fast := gaugeCollector("fast", 1)
+	slow := gaugeCollector("slow", 2)
+	disabled := gaugeCollector("disabled", 3)
+
+	p, err := newPoller([]Collector{disabled, fast, slow}, time.Hour, map[string]config.CollectorConfig{
+		"fast":     {PollInterval: 5 * time.Millisecond},
+		"disabled": {Disabled: true},
+	})
+	require.NoError(t, err)
+
+	stats := p.Stats()
+	require.Len(t, stats, 2, "disabled collector is not polled")
+	assert.Equal(t, CollectorStats{Name: "fast", Interval: 5 * time.Millisecond}, stats[0])
+	assert.Equal(t, CollectorStats{Name: "slow", Interval: time.Hour}, stats[1], "agent poll interval is the default")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go p.Run(ctx)
+
+	require.Eventually(t, func() bool { return fast.runs.Load() >= 2 }, time.Second, 5*time.Millisecond)
+	assert.Zero(t, slow.runs.Load())
+	assert.Zero(t, disabled.runs.Load())
+	assert.Equal(t, map[string]float64{"fast": 1}, p.Snapshot().GaugeMetrics)
+}
+
+func TestPollerUnknownCollector(t *testing.T) {
+	_, err := newPoller([]Collector{gaugeCollector("fast", 1)}, time.Second, map[string]config.CollectorConfig{
+		"gpu": {Disabled: true},
+	})
+	assert.Error(t, err)
+}
+
+func TestPollerTimeout(t *testing.T) {
+	blocking := &fakeCollector{name: "blocking", collect: func(ctx context.Context) (MetricsCollection, error) {
+		<-ctx.Done()
+		return MetricsCollection{}, ctx.Err()
+	}}
+
+	p, err := newPoller([]Collector{blocking}, 5*time.Millisecond, nil)
+	require.NoError(t, err)
+
+	p.poll(context.Background(), p.jobs[0])
+
+	stats := p.Stats()[0]
+	assert.ErrorIs(t, stats.LastError, context.DeadlineExceeded, "collector run is limited by its interval")
+	assert.GreaterOrEqual(t, stats.LastDuration, 5*time.Millisecond)
+}
@@ -0,0 +1,52 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Collector polls one group of metrics, it is registered with Register and polled by Poller.
+type Collector interface {
+	// Name is unique name used in collectors config.
+	Name() string
+	// Collect returns metrics polled, metrics collected before failure may be returned together with error.
+	Collect(ctx context.Context) (MetricsCollection, error)
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]Collector)
+)
+
+// Register makes collector polled by agent, it is meant to be called from init of the package
+// providing collector and panics if the name is taken.
+func Register(c Collector) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if c == nil {
+		panic("metrics: Register collector is nil")
+	}
+	if _, dup := registry[c.Name()]; dup {
+		panic(fmt.Sprintf("metrics: Register called twice for collector %s", c.Name()))
+	}
+	registry[c.Name()] = c
+}
+
+// Registered returns collectors registered sorted by name.
+func Registered() []Collector {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	collectors := make([]Collector, 0, len(registry))
+	for _, c := range registry {
+		collectors = append(collectors, c)
+	}
+	sort.Slice(collectors, func(i, j int) bool {
+		return collectors[i].Name() < collectors[j].Name()
+	})
+
+	return collectors
+}
@@ -1,6 +0,0 @@
-package metrics
-
-type MetricsReporter interface {
-	ReportMetrics(MetricsCollection)
-	Close() error
-}
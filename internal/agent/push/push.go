@@ -0,0 +1,173 @@
+// Package push lets local applications push metrics to agent, which forwards them to the server
+// with the regular report cycle, so applications need neither access to the server nor keys.
+//
+// Endpoints accept the same requests as the server does, JSON body may be compressed with gzip:
+//
+//	POST /update/{type}/{name}/{value}
+//	POST /update/  single domain.Metrics JSON
+//	POST /updates/ JSON array of domain.Metrics
+package push
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/frolmr/metrics/internal/agent/metrics"
+	"github.com/frolmr/metrics/internal/domain"
+	"github.com/frolmr/metrics/pkg/formatter"
+	"github.com/go-chi/chi/v5"
+)
+
+// maxBodySize limits pushed request body.
+const maxBodySize = 1 << 20
+
+// Buffer keeps metrics pushed since the last report, the last gauge value wins and counter deltas add up.
+type Buffer struct {
+	validator *domain.Validator
+
+	mu       sync.Mutex
+	gauges   map[string]float64
+	counters map[string]int64
+}
+
+// NewBuffer function is constructor for push buffer, nil validator means default rules.
+func NewBuffer(validator *domain.Validator) *Buffer {
+	if validator == nil {
+		validator = domain.DefaultValidator()
+	}
+
+	return &Buffer{
+		validator: validator,
+		gauges:    make(map[string]float64),
+		counters:  make(map[string]int64),
+	}
+}
+
+// Add validates metrics as the server would and keeps the valid ones.
+func (b *Buffer) Add(ms []domain.Metrics) *domain.UpdateResult {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	valid, result := b.validator.ValidateBatch(ms, func(name string) (int64, error) {
+		return b.counters[name], nil
+	})
+	for _, m := range valid {
+		switch m.MType {
+		case domain.GaugeType:
+			b.gauges[m.ID] = *m.Value
+		case domain.CounterType:
+			b.counters[m.ID] += *m.Delta
+		}
+	}
+
+	return result
+}
+
+// Drain returns metrics pushed since the previous drain.
+func (b *Buffer) Drain() metrics.MetricsCollection {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ms := metrics.MetricsCollection{
+		GaugeMetrics:   b.gauges,
+		CounterMetrics: b.counters,
+	}
+	b.gauges = make(map[string]float64)
+	b.counters = make(map[string]int64)
+
+	return ms
+}
+
+// NewHandler returns router of push endpoints adding metrics to buf.
+func NewHandler(buf *Buffer) http.Handler {
+	r := chi.NewRouter()
+
+	r.Post("/update/{type}/{name}/{value}", func(res http.ResponseWriter, req *http.Request) {
+		m := domain.Metrics{ID: chi.URLParam(req, "name"), MType: chi.URLParam(req, "type")}
+
+		value := chi.URLParam(req, "value")
+		switch m.MType {
+		case domain.GaugeType:
+			v, err := formatter.StringToFloat(value)
+			if err != nil {
+				http.Error(res, "bad gauge value", http.StatusBadRequest)
+				return
+			}
+			m.Value = &v
+		case domain.CounterType:
+			d, err := formatter.StringToInt(value)
+			if err != nil {
+				http.Error(res, "bad counter value", http.StatusBadRequest)
+				return
+			}
+			m.Delta = &d
+		default:
+			http.Error(res, "wrong metric type", http.StatusBadRequest)
+			return
+		}
+
+		if result := buf.Add([]domain.Metrics{m}); len(result.Rejected) != 0 {
+			http.Error(res, result.Rejected[0].Reason, http.StatusBadRequest)
+			return
+		}
+		res.WriteHeader(http.StatusOK)
+	})
+
+	updateJSON := func(res http.ResponseWriter, req *http.Request) {
+		var m domain.Metrics
+		if err := decodeBody(res, req, &m); err != nil {
+			http.Error(res, "bad metric JSON", http.StatusBadRequest)
+			return
+		}
+
+		writeResult(res, buf.Add([]domain.Metrics{m}))
+	}
+
+	updatesJSON := func(res http.ResponseWriter, req *http.Request) {
+		var ms []domain.Metrics
+		if err := decodeBody(res, req, &ms); err != nil {
+			http.Error(res, "bad metrics JSON", http.StatusBadRequest)
+			return
+		}
+
+		writeResult(res, buf.Add(ms))
+	}
+
+	// NOTE: paths with trailing slash are the ones agent and pkg/client send to, so they may push to agent as well
+	r.Post("/update", updateJSON)
+	r.Post("/update/", updateJSON)
+	r.Post("/updates", updatesJSON)
+	r.Post("/updates/", updatesJSON)
+
+	return r
+}
+
+// decodeBody decodes JSON body which may be compressed the way agent and pkg/client send it.
+func decodeBody(res http.ResponseWriter, req *http.Request, v any) error {
+	var body io.Reader = http.MaxBytesReader(res, req.Body, maxBodySize)
+	if req.Header.Get("Content-Encoding") == domain.CompressFormat {
+		zr, err := gzip.NewReader(body)
+		if err != nil {
+			return err
+		}
+		defer zr.Close()
+		body = io.LimitReader(zr, maxBodySize)
+	}
+
+	return json.NewDecoder(body).Decode(v)
+}
+
+// writeResult responds with per-metric result, status is 400 only if nothing was accepted.
+func writeResult(res http.ResponseWriter, result *domain.UpdateResult) {
+	status := http.StatusOK
+	if len(result.Accepted) == 0 && len(result.Rejected) != 0 {
+		status = http.StatusBadRequest
+	}
+
+	res.Header().Set("Content-Type", domain.JSONContentType)
+	res.WriteHeader(status)
+	_ = json.NewEncoder(res).Encode(result)
+}
@@ -0,0 +1,105 @@
+package push
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/frolmr/metrics/internal/domain"
+	"github.com/frolmr/metrics/pkg/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler(t *testing.T) {
+	tests := []struct {
+		name         string
+		path         string
+		body         string
+		wantStatus   int
+		wantRejected int
+	}{
+		{name: "gauge in path", path: "/update/gauge/queue/1.5", wantStatus: http.StatusOK},
+		{name: "counter in path", path: "/update/counter/orders/2", wantStatus: http.StatusOK},
+		{name: "bad counter value", path: "/update/counter/orders/1.5", wantStatus: http.StatusBadRequest},
+		{name: "unknown type", path: "/update/histogram/orders/1", wantStatus: http.StatusBadRequest},
+		{name: "single JSON", path: "/update/", body: `{"id":"orders","type":"counter","delta":3}`, wantStatus: http.StatusOK},
+		{
+			name:         "batch with rejected metric",
+			path:         "/updates",
+			body:         `[{"id":"queue","type":"gauge","value":4},{"id":"","type":"gauge","value":1}]`,
+			wantStatus:   http.StatusOK,
+			wantRejected: 1,
+		},
+		{
+			name:         "all rejected",
+			path:         "/updates/",
+			body:         `[{"id":"orders","type":"counter"}]`,
+			wantStatus:   http.StatusBadRequest,
+			wantRejected: 1,
+		},
+		{name: "bad JSON", path: "/updates", body: `[{`, wantStatus: http.StatusBadRequest},
+	}
+
+	buf := NewBuffer(nil)
+	ts := httptest.NewServer(NewHandler(buf))
+	defer ts.Close()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := ts.Client().Post(ts.URL+tt.path, domain.JSONContentType, strings.NewReader(tt.body))
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+			if tt.wantRejected != 0 {
+				var result domain.UpdateResult
+				require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+				assert.Len(t, result.Rejected, tt.wantRejected)
+			}
+		})
+	}
+
+	ms := buf.Drain()
+	assert.Equal(t, map[string]float64{"queue": 4}, ms.GaugeMetrics, "the last gauge value wins")
+	assert.Equal(t, map[string]int64{"orders": 5}, ms.CounterMetrics, "counter deltas add up")
+
+	ms = buf.Drain()
+	assert.Empty(t, ms.GaugeMetrics, "drained metrics are reported once")
+	assert.Empty(t, ms.CounterMetrics)
+}
+
+func TestBufferCounterOverflow(t *testing.T) {
+	buf := NewBuffer(nil)
+	delta := int64(math.MaxInt64)
+
+	result := buf.Add([]domain.Metrics{{ID: "orders", MType: domain.CounterType, Delta: &delta}})
+	require.Empty(t, result.Rejected)
+
+	result = buf.Add([]domain.Metrics{{ID: "orders", MType: domain.CounterType, Delta: &delta}})
+	assert.Len(t, result.Rejected, 1, "pending deltas are checked for overflow")
+	assert.Equal(t, map[string]int64{"orders": math.MaxInt64}, buf.Drain().CounterMetrics)
+}
+
+func TestPushWithClient(t *testing.T) {
+	buf := NewBuffer(nil)
+	ts := httptest.NewServer(NewHandler(buf))
+	defer ts.Close()
+
+	sender := client.NewHTTPSender(client.Config{Scheme: "http", Address: ts.Listener.Addr().String()})
+	defer sender.Close()
+
+	err := sender.Send(context.Background(), client.Batch{
+		Gauges:   map[string]float64{"queue": 3},
+		Counters: map[string]int64{"orders": 2},
+	})
+	require.NoError(t, err)
+
+	ms := buf.Drain()
+	assert.Equal(t, map[string]float64{"queue": 3}, ms.GaugeMetrics)
+	assert.Equal(t, map[string]int64{"orders": 2}, ms.CounterMetrics)
+}
@@ -0,0 +1,62 @@
+// Package reporter sends metrics collected by agent to the server with sender of pkg/client.
+package reporter
+
+import (
+	"context"
+	"log"
+
+	"github.com/frolmr/metrics/internal/agent/config"
+	"github.com/frolmr/metrics/internal/agent/hostip"
+	"github.com/frolmr/metrics/internal/agent/metrics"
+	"github.com/frolmr/metrics/pkg/client"
+)
+
+type Reporter struct {
+	sender client.Sender
+}
+
+// NewReporter function is constructor for reporter sending over scheme of the config.
+func NewReporter(cfg *config.Config, hostIP *hostip.Resolver) (*Reporter, error) {
+	sender, err := client.NewSender(client.Config{
+		Scheme:     cfg.Scheme,
+		Address:    cfg.HTTPAddress,
+		Key:        cfg.Key,
+		CryptoKey:  cfg.CryptoKey,
+		HostIP:     hostIP.IP,
+		GRPCStream: cfg.GRPCStream,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return NewReporterWithSender(sender), nil
+}
+
+// NewReporterWithSender function is constructor for reporter sending with given sender.
+func NewReporterWithSender(sender client.Sender) *Reporter {
+	return &Reporter{
+		sender: sender,
+	}
+}
+
+func (r *Reporter) Close() error {
+	return r.sender.Close()
+}
+
+// ReportMetrics sends metrics collected, failures are logged.
+func (r *Reporter) ReportMetrics(ms metrics.MetricsCollection) {
+	batch := client.Batch{
+		Gauges:   ms.GaugeMetrics,
+		Counters: ms.CounterMetrics,
+	}
+	if batch.Len() == 0 {
+		return
+	}
+
+	if err := r.sender.Send(context.Background(), batch); err != nil {
+		log.Println("failed to report metrics: ", err)
+		return
+	}
+
+	log.Printf("reported %d metrics", batch.Len())
+}
@@ -0,0 +1,45 @@
+package reporter
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/frolmr/metrics/internal/agent/metrics"
+	"github.com/frolmr/metrics/pkg/client"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeSender struct {
+	batches []client.Batch
+	err     error
+}
+
+func (s *fakeSender) Send(ctx context.Context, batch client.Batch) error {
+	s.batches = append(s.batches, batch)
+	return s.err
+}
+
+func (s *fakeSender) Close() error {
+	return nil
+}
+
+func TestReportMetrics(t *testing.T) {
+	sender := &fakeSender{}
+	r := NewReporterWithSender(sender)
+
+	r.ReportMetrics(metrics.MetricsCollection{
+		GaugeMetrics:   map[string]float64{"Alloc": 1.5},
+		CounterMetrics: map[string]int64{"PollCount": 3},
+	})
+	r.ReportMetrics(metrics.MetricsCollection{})
+
+	sender.err = errors.New("unreachable")
+	r.ReportMetrics(metrics.MetricsCollection{GaugeMetrics: map[string]float64{"Alloc": 2}})
+
+	assert.Equal(t, []client.Batch{
+		{Gauges: map[string]float64{"Alloc": 1.5}, Counters: map[string]int64{"PollCount": 3}},
+		{Gauges: map[string]float64{"Alloc": 2}},
+	}, sender.batches, "empty collection is not sent, failure is not fatal")
+	assert.NoError(t, r.Close())
+}
@@ -11,4 +11,5 @@ const (
 	CompressFormat = "gzip"
 
 	SignatureHeader = "HashSHA256"
+	RealIPHeader    = "X-Real-IP"
 )
@@ -1,5 +1,10 @@
 package domain
 
+import (
+	"errors"
+	"sort"
+)
+
 // Metrics represents a metric with its name, type, and value.
 // @Description Metrics request payload for metrics data.
 type Metrics struct {
@@ -19,3 +24,67 @@ type Metrics struct {
 	// Example: 3.14
 	Value *float64 `json:"value,omitempty"`
 }
+
+var (
+	ErrEmptyName    = errors.New("empty metric name")
+	ErrUnknownType  = errors.New("unknown metric type")
+	ErrMissingDelta = errors.New("counter metric without delta")
+	ErrMissingValue = errors.New("gauge metric without value")
+)
+
+// Validate checks that metric has a name, known type and value matching the type.
+func (m Metrics) Validate() error {
+	if m.ID == "" {
+		return ErrEmptyName
+	}
+
+	switch m.MType {
+	case CounterType:
+		if m.Delta == nil {
+			return ErrMissingDelta
+		}
+	case GaugeType:
+		if m.Value == nil {
+			return ErrMissingValue
+		}
+	default:
+		return ErrUnknownType
+	}
+
+	return nil
+}
+
+// MetricStatus describes outcome of a single metric in a bulk update.
+type MetricStatus struct {
+	// Index is the position of the metric in the request.
+	Index int `json:"index"`
+
+	// ID is the name of the metric.
+	ID string `json:"id"`
+
+	// MType is the type of the metric.
+	MType string `json:"type"`
+
+	// Reason explains why metric was rejected.
+	Reason string `json:"reason,omitempty"`
+}
+
+// UpdateResult lists accepted and rejected metrics of a bulk update.
+// @Description Per-metric result of bulk update.
+type UpdateResult struct {
+	Accepted []MetricStatus `json:"accepted"`
+	Rejected []MetricStatus `json:"rejected"`
+}
+
+// RejectAll moves all accepted metrics to rejected with the reason, used when storage fails to save them.
+func (r *UpdateResult) RejectAll(reason string) {
+	for _, status := range r.Accepted {
+		status.Reason = reason
+		r.Rejected = append(r.Rejected, status)
+	}
+	r.Accepted = r.Accepted[:0]
+
+	sort.Slice(r.Rejected, func(i, j int) bool {
+		return r.Rejected[i].Index < r.Rejected[j].Index
+	})
+}
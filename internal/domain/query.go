@@ -0,0 +1,145 @@
+package domain
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	// DefaultListLimit is page size used when limit is not set.
+	DefaultListLimit = 100
+	// MaxListLimit is the largest page size.
+	MaxListLimit = 1000
+)
+
+// SortOrder defines ordering of metrics listing, ties are always broken by name and then by type.
+type SortOrder string
+
+const (
+	// SortByName orders metrics by name in ascending order.
+	SortByName SortOrder = "name"
+	// SortByNameDesc orders metrics by name in descending order.
+	SortByNameDesc SortOrder = "-name"
+	// SortByType orders metrics by type and then by name.
+	SortByType SortOrder = "type"
+	// SortByTypeDesc orders metrics by type and then by name in descending order.
+	SortByTypeDesc SortOrder = "-type"
+)
+
+var (
+	ErrInvalidSort   = errors.New("invalid sort order")
+	ErrInvalidLimit  = errors.New("invalid limit")
+	ErrInvalidCursor = errors.New("invalid cursor")
+)
+
+// MetricKey identifies metric in listing order.
+type MetricKey struct {
+	ID    string `json:"id"`
+	MType string `json:"type"`
+}
+
+// MetricsQuery describes a page of metrics listing.
+type MetricsQuery struct {
+	// MType limits listing to one metric type, empty means any.
+	MType string
+	// Prefix limits listing to metrics which name starts with it.
+	Prefix string
+	// Sort is the listing order.
+	Sort SortOrder
+	// After is the key of the last metric of previous page, nil for the first page.
+	After *MetricKey
+	// Limit is the maximum number of metrics to return.
+	Limit int
+}
+
+// Descending reports whether sort order is descending.
+func (s SortOrder) Descending() bool {
+	return strings.HasPrefix(string(s), "-")
+}
+
+// ByType reports whether metrics are ordered by type first.
+func (s SortOrder) ByType() bool {
+	return strings.TrimPrefix(string(s), "-") == string(SortByType)
+}
+
+// Validate checks query and fills in defaults.
+func (q *MetricsQuery) Validate() error {
+	switch q.MType {
+	case "", GaugeType, CounterType:
+	default:
+		return ErrUnknownType
+	}
+
+	switch q.Sort {
+	case "":
+		q.Sort = SortByName
+	case SortByName, SortByNameDesc, SortByType, SortByTypeDesc:
+	default:
+		return fmt.Errorf("%w: %q", ErrInvalidSort, q.Sort)
+	}
+
+	switch {
+	case q.Limit == 0:
+		q.Limit = DefaultListLimit
+	case q.Limit < 0 || q.Limit > MaxListLimit:
+		return fmt.Errorf("%w: must be between 1 and %d", ErrInvalidLimit, MaxListLimit)
+	}
+
+	return nil
+}
+
+// Less reports whether metric a goes before metric b in the query order.
+func (q MetricsQuery) Less(a, b MetricKey) bool {
+	if a == b {
+		return false
+	}
+
+	a1, a2, b1, b2 := a.ID, a.MType, b.ID, b.MType
+	if q.Sort.ByType() {
+		a1, a2, b1, b2 = a.MType, a.ID, b.MType, b.ID
+	}
+
+	less := a1 < b1 || (a1 == b1 && a2 < b2)
+	if q.Sort.Descending() {
+		return !less
+	}
+	return less
+}
+
+// Match reports whether metric passes type and prefix filters.
+func (q MetricsQuery) Match(k MetricKey) bool {
+	return (q.MType == "" || q.MType == k.MType) && strings.HasPrefix(k.ID, q.Prefix)
+}
+
+// MetricsPage is a page of metrics listing.
+// @Description Page of metrics listing.
+type MetricsPage struct {
+	// Metrics of the page in requested order.
+	Metrics []Metrics `json:"metrics"`
+
+	// NextCursor is passed as cursor to get the next page, empty on the last page.
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// EncodeCursor makes opaque cursor pointing after the metric.
+func EncodeCursor(k MetricKey) string {
+	data, _ := json.Marshal(k)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor parses cursor made by EncodeCursor.
+func DecodeCursor(cursor string) (*MetricKey, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var k MetricKey
+	if err := json.Unmarshal(data, &k); err != nil || k.ID == "" {
+		return nil, ErrInvalidCursor
+	}
+	return &k, nil
+}
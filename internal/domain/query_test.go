@@ -0,0 +1,99 @@
+package domain
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetricsQueryValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   MetricsQuery
+		want    MetricsQuery
+		wantErr error
+	}{
+		{
+			name:  "defaults",
+			query: MetricsQuery{},
+			want:  MetricsQuery{Sort: SortByName, Limit: DefaultListLimit},
+		},
+		{
+			name:  "valid",
+			query: MetricsQuery{MType: GaugeType, Sort: SortByTypeDesc, Limit: 5},
+			want:  MetricsQuery{MType: GaugeType, Sort: SortByTypeDesc, Limit: 5},
+		},
+		{name: "unknown type", query: MetricsQuery{MType: "histogram"}, wantErr: ErrUnknownType},
+		{name: "unknown sort", query: MetricsQuery{Sort: "value"}, wantErr: ErrInvalidSort},
+		{name: "negative limit", query: MetricsQuery{Limit: -1}, wantErr: ErrInvalidLimit},
+		{name: "too large limit", query: MetricsQuery{Limit: MaxListLimit + 1}, wantErr: ErrInvalidLimit},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.query.Validate()
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, tt.query)
+		})
+	}
+}
+
+func TestMetricsQueryLess(t *testing.T) {
+	keys := []MetricKey{
+		{ID: "b", MType: GaugeType},
+		{ID: "a", MType: GaugeType},
+		{ID: "b", MType: CounterType},
+		{ID: "c", MType: CounterType},
+	}
+
+	tests := []struct {
+		sort SortOrder
+		want []MetricKey
+	}{
+		{
+			sort: SortByName,
+			want: []MetricKey{{"a", GaugeType}, {"b", CounterType}, {"b", GaugeType}, {"c", CounterType}},
+		},
+		{
+			sort: SortByNameDesc,
+			want: []MetricKey{{"c", CounterType}, {"b", GaugeType}, {"b", CounterType}, {"a", GaugeType}},
+		},
+		{
+			sort: SortByType,
+			want: []MetricKey{{"b", CounterType}, {"c", CounterType}, {"a", GaugeType}, {"b", GaugeType}},
+		},
+		{
+			sort: SortByTypeDesc,
+			want: []MetricKey{{"b", GaugeType}, {"a", GaugeType}, {"c", CounterType}, {"b", CounterType}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.sort), func(t *testing.T) {
+			q := MetricsQuery{Sort: tt.sort}
+			got := append([]MetricKey(nil), keys...)
+			sort.Slice(got, func(i, j int) bool { return q.Less(got[i], got[j]) })
+			assert.Equal(t, tt.want, got)
+			assert.False(t, q.Less(got[0], got[0]))
+		})
+	}
+}
+
+func TestCursor(t *testing.T) {
+	key := MetricKey{ID: "cpu_usage", MType: GaugeType}
+
+	after, err := DecodeCursor(EncodeCursor(key))
+	require.NoError(t, err)
+	assert.Equal(t, key, *after)
+
+	for _, cursor := range []string{"!!!", "bm90IGpzb24", EncodeCursor(MetricKey{})} {
+		_, err := DecodeCursor(cursor)
+		assert.ErrorIs(t, err, ErrInvalidCursor, cursor)
+	}
+}
@@ -0,0 +1,235 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+)
+
+const (
+	// DefaultNamePattern allows latin letters, digits and _ . : - in metric names.
+	DefaultNamePattern = `^[A-Za-z0-9_.:\-]+$`
+	// DefaultMaxNameLength matches size of name column in DB.
+	DefaultMaxNameLength = 50
+)
+
+// NonFinitePolicy defines what to do with NaN and Inf gauge values.
+type NonFinitePolicy string
+
+const (
+	// NonFiniteReject rejects NaN and Inf gauges, they can't be encoded to JSON anyway.
+	NonFiniteReject NonFinitePolicy = "reject"
+	// NonFiniteAllow stores NaN and Inf gauges as is.
+	NonFiniteAllow NonFinitePolicy = "allow"
+)
+
+// OverflowPolicy defines what to do when counter goes out of int64 range.
+type OverflowPolicy string
+
+const (
+	// OverflowReject rejects delta that overflows the counter.
+	OverflowReject OverflowPolicy = "reject"
+	// OverflowSaturate cuts delta so that counter stays at int64 limit.
+	OverflowSaturate OverflowPolicy = "saturate"
+)
+
+var (
+	ErrNameTooLong     = errors.New("metric name too long")
+	ErrInvalidName     = errors.New("invalid metric name")
+	ErrNonFiniteValue  = errors.New("non-finite gauge value")
+	ErrCounterOverflow = errors.New("counter overflow")
+	ErrInvalidRule     = errors.New("invalid validation rule")
+)
+
+// IsValidationError reports whether err means metric breaks validation rules, not that something failed.
+func IsValidationError(err error) bool {
+	for _, target := range []error{
+		ErrEmptyName, ErrUnknownType, ErrMissingDelta, ErrMissingValue,
+		ErrNameTooLong, ErrInvalidName, ErrNonFiniteValue, ErrCounterOverflow,
+	} {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidationRules configures Validator.
+type ValidationRules struct {
+	NamePattern     string
+	MaxNameLength   int
+	NonFinitePolicy NonFinitePolicy
+	OverflowPolicy  OverflowPolicy
+}
+
+// DefaultValidationRules returns rules compatible with DB storage.
+func DefaultValidationRules() ValidationRules {
+	return ValidationRules{
+		NamePattern:     DefaultNamePattern,
+		MaxNameLength:   DefaultMaxNameLength,
+		NonFinitePolicy: NonFiniteReject,
+		OverflowPolicy:  OverflowReject,
+	}
+}
+
+// CounterReader returns current counter value, error means there is no such counter yet.
+type CounterReader func(name string) (int64, error)
+
+// Validator applies the same metric rules to every way metrics get into the server.
+type Validator struct {
+	rules       ValidationRules
+	namePattern *regexp.Regexp
+}
+
+// NewValidator checks rules and creates validator.
+func NewValidator(rules ValidationRules) (*Validator, error) {
+	if rules.MaxNameLength <= 0 {
+		return nil, fmt.Errorf("%w: max name length must be positive", ErrInvalidRule)
+	}
+
+	switch rules.NonFinitePolicy {
+	case NonFiniteReject, NonFiniteAllow:
+	default:
+		return nil, fmt.Errorf("%w: unknown non-finite policy %q", ErrInvalidRule, rules.NonFinitePolicy)
+	}
+
+	switch rules.OverflowPolicy {
+	case OverflowReject, OverflowSaturate:
+	default:
+		return nil, fmt.Errorf("%w: unknown overflow policy %q", ErrInvalidRule, rules.OverflowPolicy)
+	}
+
+	namePattern, err := regexp.Compile(rules.NamePattern)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidRule, err)
+	}
+
+	return &Validator{
+		rules:       rules,
+		namePattern: namePattern,
+	}, nil
+}
+
+// DefaultValidator returns validator with DefaultValidationRules.
+func DefaultValidator() *Validator {
+	v, err := NewValidator(DefaultValidationRules())
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// ValidateName checks metric name length and format.
+func (v *Validator) ValidateName(name string) error {
+	if name == "" {
+		return ErrEmptyName
+	}
+	if len(name) > v.rules.MaxNameLength {
+		return fmt.Errorf("%w: %d > %d", ErrNameTooLong, len(name), v.rules.MaxNameLength)
+	}
+	if !v.namePattern.MatchString(name) {
+		return ErrInvalidName
+	}
+	return nil
+}
+
+// ValidateGauge checks gauge value against non-finite policy.
+func (v *Validator) ValidateGauge(value float64) error {
+	if v.rules.NonFinitePolicy == NonFiniteReject && (math.IsNaN(value) || math.IsInf(value, 0)) {
+		return ErrNonFiniteValue
+	}
+	return nil
+}
+
+// CheckCounter returns delta to add to current counter value according to overflow policy.
+func (v *Validator) CheckCounter(current, delta int64) (int64, error) {
+	switch {
+	case delta > 0 && current > math.MaxInt64-delta:
+		if v.rules.OverflowPolicy == OverflowSaturate {
+			return math.MaxInt64 - current, nil
+		}
+		return 0, ErrCounterOverflow
+	case delta < 0 && current < math.MinInt64-delta:
+		if v.rules.OverflowPolicy == OverflowSaturate {
+			return math.MinInt64 - current, nil
+		}
+		return 0, ErrCounterOverflow
+	default:
+		return delta, nil
+	}
+}
+
+// Validate checks metric structure, name and gauge value, counter overflow is checked by ValidateUpdate.
+func (v *Validator) Validate(m Metrics) error {
+	if err := m.Validate(); err != nil {
+		return err
+	}
+	if err := v.ValidateName(m.ID); err != nil {
+		return err
+	}
+	if m.MType == GaugeType {
+		return v.ValidateGauge(*m.Value)
+	}
+	return nil
+}
+
+// ValidateUpdate validates metric as Validate does and checks counter overflow against current value.
+// Returned metric has delta adjusted according to overflow policy.
+func (v *Validator) ValidateUpdate(m Metrics, counters CounterReader) (Metrics, error) {
+	return v.validateUpdate(m, make(map[string]int64), counters)
+}
+
+// ValidateBatch validates every metric and returns the valid ones together with per-metric result.
+// Counter deltas of the batch are summed up per name before overflow check.
+func (v *Validator) ValidateBatch(metrics []Metrics, counters CounterReader) ([]Metrics, *UpdateResult) {
+	valid := make([]Metrics, 0, len(metrics))
+	result := &UpdateResult{
+		Accepted: make([]MetricStatus, 0, len(metrics)),
+		Rejected: make([]MetricStatus, 0),
+	}
+
+	pending := make(map[string]int64)
+
+	for i, m := range metrics {
+		status := MetricStatus{Index: i, ID: m.ID, MType: m.MType}
+
+		m, err := v.validateUpdate(m, pending, counters)
+		if err != nil {
+			status.Reason = err.Error()
+			result.Rejected = append(result.Rejected, status)
+			continue
+		}
+
+		valid = append(valid, m)
+		result.Accepted = append(result.Accepted, status)
+	}
+
+	return valid, result
+}
+
+// validateUpdate keeps counter values expected after update in pending, so that deltas of one batch add up.
+func (v *Validator) validateUpdate(m Metrics, pending map[string]int64, counters CounterReader) (Metrics, error) {
+	if err := v.Validate(m); err != nil {
+		return m, err
+	}
+	if m.MType != CounterType {
+		return m, nil
+	}
+
+	current, ok := pending[m.ID]
+	if !ok && counters != nil {
+		if value, err := counters(m.ID); err == nil {
+			current = value
+		}
+	}
+
+	delta, err := v.CheckCounter(current, *m.Delta)
+	if err != nil {
+		return m, err
+	}
+
+	pending[m.ID] = current + delta
+	m.Delta = &delta
+	return m, nil
+}
@@ -0,0 +1,134 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewValidator(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(r *ValidationRules)
+	}{
+		{name: "bad pattern", modify: func(r *ValidationRules) { r.NamePattern = "[" }},
+		{name: "zero max length", modify: func(r *ValidationRules) { r.MaxNameLength = 0 }},
+		{name: "unknown non-finite policy", modify: func(r *ValidationRules) { r.NonFinitePolicy = "zero" }},
+		{name: "unknown overflow policy", modify: func(r *ValidationRules) { r.OverflowPolicy = "wrap" }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules := DefaultValidationRules()
+			tt.modify(&rules)
+
+			_, err := NewValidator(rules)
+			require.ErrorIs(t, err, ErrInvalidRule)
+		})
+	}
+}
+
+func TestValidatorValidate(t *testing.T) {
+	v := DefaultValidator()
+	value := 1.5
+	nan := math.NaN()
+	inf := math.Inf(1)
+	var delta int64 = 1
+
+	tests := []struct {
+		name    string
+		metric  Metrics
+		wantErr error
+	}{
+		{name: "valid gauge", metric: Metrics{ID: "cpu.load_1", MType: GaugeType, Value: &value}},
+		{name: "valid counter", metric: Metrics{ID: "PollCount", MType: CounterType, Delta: &delta}},
+		{name: "empty name", metric: Metrics{MType: GaugeType, Value: &value}, wantErr: ErrEmptyName},
+		{name: "too long name", metric: Metrics{ID: strings.Repeat("a", 51), MType: GaugeType, Value: &value}, wantErr: ErrNameTooLong},
+		{name: "whitespace in name", metric: Metrics{ID: "cpu load", MType: GaugeType, Value: &value}, wantErr: ErrInvalidName},
+		{name: "unicode name", metric: Metrics{ID: "загрузка", MType: GaugeType, Value: &value}, wantErr: ErrInvalidName},
+		{name: "NaN gauge", metric: Metrics{ID: "nan", MType: GaugeType, Value: &nan}, wantErr: ErrNonFiniteValue},
+		{name: "Inf gauge", metric: Metrics{ID: "inf", MType: GaugeType, Value: &inf}, wantErr: ErrNonFiniteValue},
+		{name: "unknown type", metric: Metrics{ID: "x", MType: "histogram"}, wantErr: ErrUnknownType},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := v.Validate(tt.metric)
+			if tt.wantErr == nil {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+
+	t.Run("non-finite allowed", func(t *testing.T) {
+		rules := DefaultValidationRules()
+		rules.NonFinitePolicy = NonFiniteAllow
+		allowing, err := NewValidator(rules)
+		require.NoError(t, err)
+
+		require.NoError(t, allowing.Validate(Metrics{ID: "nan", MType: GaugeType, Value: &nan}))
+	})
+}
+
+func TestValidatorCheckCounter(t *testing.T) {
+	rules := DefaultValidationRules()
+	rejecting, err := NewValidator(rules)
+	require.NoError(t, err)
+
+	rules.OverflowPolicy = OverflowSaturate
+	saturating, err := NewValidator(rules)
+	require.NoError(t, err)
+
+	_, err = rejecting.CheckCounter(math.MaxInt64-1, 2)
+	require.ErrorIs(t, err, ErrCounterOverflow)
+	_, err = rejecting.CheckCounter(math.MinInt64+1, -2)
+	require.ErrorIs(t, err, ErrCounterOverflow)
+
+	delta, err := saturating.CheckCounter(math.MaxInt64-1, 2)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), delta)
+
+	delta, err = saturating.CheckCounter(math.MinInt64+1, -2)
+	require.NoError(t, err)
+	assert.Equal(t, int64(-1), delta)
+
+	delta, err = rejecting.CheckCounter(10, 5)
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), delta)
+}
+
+func TestValidatorValidateBatch(t *testing.T) {
+	v := DefaultValidator()
+	big := int64(math.MaxInt64/2) + 1
+	value := 1.0
+
+	counters := func(name string) (int64, error) {
+		return 1, nil
+	}
+
+	valid, result := v.ValidateBatch([]Metrics{
+		{ID: "c", MType: CounterType, Delta: &big},
+		{ID: "g", MType: GaugeType, Value: &value},
+		{ID: "c", MType: CounterType, Delta: &big},
+	}, counters)
+
+	require.Len(t, valid, 2)
+	require.Len(t, result.Rejected, 1)
+	assert.Equal(t, 2, result.Rejected[0].Index, "deltas of one batch should add up")
+	assert.Equal(t, ErrCounterOverflow.Error(), result.Rejected[0].Reason)
+}
+
+func TestIsValidationError(t *testing.T) {
+	assert.True(t, IsValidationError(ErrEmptyName))
+	assert.True(t, IsValidationError(fmt.Errorf("%w: 60 > 50", ErrNameTooLong)))
+	assert.True(t, IsValidationError(ErrCounterOverflow))
+	assert.False(t, IsValidationError(errors.New("connection refused")))
+	assert.False(t, IsValidationError(nil))
+}
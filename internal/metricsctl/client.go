@@ -0,0 +1,56 @@
+// Package metricsctl implements metricsctl tool which manages metrics server over HTTP or gRPC.
+// Requests are signed, encrypted and compressed the same way agent does it, so tool works against
+// server protected with key, crypto key and trusted subnet.
+package metricsctl
+
+import (
+	"context"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/frolmr/metrics/internal/domain"
+)
+
+var (
+	ErrServer   = errors.New("server error")
+	ErrRejected = errors.New("metrics rejected")
+)
+
+// Options describe server and credentials.
+type Options struct {
+	// Scheme is http, https or grpc.
+	Scheme  string
+	Address string
+	// Key signs pushed metrics.
+	Key string
+	// CryptoKey encrypts pushed metrics.
+	CryptoKey *rsa.PublicKey
+	// HostIP is sent to server checking trusted subnet.
+	HostIP string
+}
+
+// Client talks to the server, list and watch pass metrics to fn one by one and stop on its error.
+type Client interface {
+	Ping(ctx context.Context) error
+	Get(ctx context.Context, mType, name string) (domain.Metrics, error)
+	List(ctx context.Context, mType, prefix string, fn func(m domain.Metrics) error) error
+	Push(ctx context.Context, metrics []domain.Metrics) error
+	Watch(ctx context.Context, mType, prefix string, fn func(m domain.Metrics) error) error
+	Export(ctx context.Context, format, mType, prefix string, w io.Writer) error
+	Import(ctx context.Context, counters string, r io.Reader) (ImportResult, error)
+	Close() error
+}
+
+// NewClient function is constructor for client of the scheme given by options.
+func NewClient(opts Options) (Client, error) {
+	switch opts.Scheme {
+	case "http", "https":
+		return NewHTTPClient(opts), nil
+	case "grpc":
+		return NewGRPCClient(opts)
+	default:
+		return nil, fmt.Errorf("unknown scheme %q", opts.Scheme)
+	}
+}
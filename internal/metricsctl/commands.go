@@ -0,0 +1,296 @@
+package metricsctl
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"time"
+
+	"github.com/frolmr/metrics/internal/agent/hostip"
+	"github.com/frolmr/metrics/internal/domain"
+	"github.com/frolmr/metrics/pkg/encryptor"
+	"github.com/frolmr/metrics/pkg/formatter"
+)
+
+const (
+	schemeEnvName    = "SCHEME"
+	addressEnvName   = "ADDRESS"
+	keyEnvName       = "KEY"
+	cryptoKeyEnvName = "CRYPTO_KEY"
+	hostIPEnvName    = "HOST_IP"
+
+	defaultScheme  = "http"
+	defaultAddress = "localhost:8080"
+	defaultTimeout = 10 * time.Second
+)
+
+// ErrUsage is returned when command line is malformed, usage is printed to stderr already.
+var ErrUsage = errors.New("bad usage")
+
+const usage = `Usage: metricsctl [flags] <command> [args]
+
+Commands:
+  get <type> <name>                 print metric value
+  list [-type t] [-prefix p]        print metrics
+  push gauge|counter <name> <value> set gauge or add to counter
+  watch [-type t] [-prefix p]       print metric updates until interrupted
+  export [-format f] [-type t] [-prefix p]
+                                    dump metrics as csv, ndjson or json
+  import [-counters add|overwrite] <file>
+                                    load JSON or NDJSON dump, - reads stdin
+  ping                              check that server is up
+
+Flags:
+`
+
+// Run parses command line, flags default to the same env variables agent uses, and executes the command.
+// Watch runs until ctx is done, other commands are limited by -timeout.
+func Run(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("metricsctl", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprint(stderr, usage)
+		fs.PrintDefaults()
+	}
+
+	var (
+		opts          Options
+		cryptoKeyPath string
+		timeout       time.Duration
+	)
+	fs.StringVar(&opts.Scheme, "s", envOr(schemeEnvName, defaultScheme), "server scheme: http, https or grpc")
+	fs.StringVar(&opts.Address, "a", envOr(addressEnvName, defaultAddress), "address and port of the server")
+	fs.StringVar(&opts.Key, "k", os.Getenv(keyEnvName), "key to sign pushed metrics")
+	fs.StringVar(&cryptoKeyPath, "crypto-key", os.Getenv(cryptoKeyEnvName), "public crypto key path")
+	fs.StringVar(&opts.HostIP, "host-ip", os.Getenv(hostIPEnvName), "host IP sent to server, detected if empty")
+	fs.DurationVar(&timeout, "timeout", defaultTimeout, "request timeout")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return ErrUsage
+	}
+
+	if err := formatter.CheckSchemeFormat(opts.Scheme); err != nil {
+		return err
+	}
+	if err := formatter.CheckAddrFormat(opts.Address); err != nil {
+		return err
+	}
+	if opts.HostIP != "" && net.ParseIP(opts.HostIP) == nil {
+		return errors.New("bad host IP format")
+	}
+
+	var err error
+	if opts.CryptoKey, err = encryptor.LoadPublicKey(cryptoKeyPath); err != nil {
+		return err
+	}
+
+	if opts.HostIP == "" {
+		// NOTE: server may not check trusted subnet at all, so failed detection is not an error
+		resolver := hostip.NewResolver("", "", opts.Address)
+		if resolver.Resolve() == nil {
+			opts.HostIP = resolver.IP()
+		}
+	}
+
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return ErrUsage
+	}
+
+	client, err := NewClient(opts)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	cmd := &command{client: client, stdout: stdout, stderr: stderr}
+	name, cmdArgs := fs.Arg(0), fs.Args()[1:]
+
+	if name == "watch" {
+		return cmd.watch(ctx, cmdArgs)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	switch name {
+	case "get":
+		return cmd.get(ctx, cmdArgs)
+	case "list":
+		return cmd.list(ctx, cmdArgs)
+	case "push":
+		return cmd.push(ctx, cmdArgs)
+	case "export":
+		return cmd.export(ctx, cmdArgs)
+	case "import":
+		return cmd.importDump(ctx, cmdArgs)
+	case "ping":
+		return cmd.ping(ctx, cmdArgs)
+	default:
+		fmt.Fprintf(stderr, "unknown command %q\n", name)
+		fs.Usage()
+		return ErrUsage
+	}
+}
+
+type command struct {
+	client Client
+	stdout io.Writer
+	stderr io.Writer
+}
+
+func (c *command) get(ctx context.Context, args []string) error {
+	if len(args) != 2 {
+		return c.usageError("get <type> <name>")
+	}
+
+	m, err := c.client.Get(ctx, args[0], args[1])
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(c.stdout, formatValue(m))
+	return err
+}
+
+func (c *command) list(ctx context.Context, args []string) error {
+	fs, mType, prefix := c.filterFlags("list")
+	if err := fs.Parse(args); err != nil || fs.NArg() != 0 {
+		return ErrUsage
+	}
+
+	return c.client.List(ctx, *mType, *prefix, c.printMetric)
+}
+
+func (c *command) push(ctx context.Context, args []string) error {
+	if len(args) != 3 {
+		return c.usageError("push gauge|counter <name> <value>")
+	}
+
+	m := domain.Metrics{ID: args[1], MType: args[0]}
+	switch m.MType {
+	case domain.GaugeType:
+		value, err := formatter.StringToFloat(args[2])
+		if err != nil {
+			return fmt.Errorf("bad gauge value %q: %w", args[2], err)
+		}
+		m.Value = &value
+	case domain.CounterType:
+		delta, err := formatter.StringToInt(args[2])
+		if err != nil {
+			return fmt.Errorf("bad counter value %q: %w", args[2], err)
+		}
+		m.Delta = &delta
+	default:
+		return c.usageError("push gauge|counter <name> <value>")
+	}
+
+	return c.client.Push(ctx, []domain.Metrics{m})
+}
+
+func (c *command) watch(ctx context.Context, args []string) error {
+	fs, mType, prefix := c.filterFlags("watch")
+	if err := fs.Parse(args); err != nil || fs.NArg() != 0 {
+		return ErrUsage
+	}
+
+	return c.client.Watch(ctx, *mType, *prefix, c.printMetric)
+}
+
+func (c *command) export(ctx context.Context, args []string) error {
+	fs, mType, prefix := c.filterFlags("export")
+	format := fs.String("format", ExportFormatJSON, "export format: csv, ndjson or json")
+	if err := fs.Parse(args); err != nil || fs.NArg() != 0 {
+		return ErrUsage
+	}
+
+	return c.client.Export(ctx, *format, *mType, *prefix, c.stdout)
+}
+
+func (c *command) importDump(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	fs.SetOutput(c.stderr)
+	counters := fs.String("counters", ImportCountersAdd, "add imported counters to existing ones or overwrite them")
+	if err := fs.Parse(args); err != nil || fs.NArg() != 1 {
+		return c.usageError("import [-counters add|overwrite] <file>")
+	}
+
+	var r io.Reader = os.Stdin
+	if path := fs.Arg(0); path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	result, err := c.client.Import(ctx, *counters, r)
+	for _, status := range result.Errors {
+		fmt.Fprintf(c.stderr, "rejected %d %s: %s\n", status.Index, status.ID, status.Reason)
+	}
+	if err != nil {
+		return err
+	}
+
+	if _, err := fmt.Fprintf(c.stdout, "imported %d metrics, rejected %d\n", result.Imported, result.Rejected); err != nil {
+		return err
+	}
+	if result.Imported == 0 && result.Rejected != 0 {
+		return ErrRejected
+	}
+	return nil
+}
+
+func (c *command) ping(ctx context.Context, args []string) error {
+	if len(args) != 0 {
+		return c.usageError("ping")
+	}
+
+	if err := c.client.Ping(ctx); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintln(c.stdout, "ok")
+	return err
+}
+
+func (c *command) filterFlags(name string) (fs *flag.FlagSet, mType, prefix *string) {
+	fs = flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(c.stderr)
+	mType = fs.String("type", "", "type of the metrics (gauge or counter)")
+	prefix = fs.String("prefix", "", "name prefix")
+	return fs, mType, prefix
+}
+
+func (c *command) printMetric(m domain.Metrics) error {
+	_, err := fmt.Fprintf(c.stdout, "%s\t%s\t%s\n", m.MType, m.ID, formatValue(m))
+	return err
+}
+
+func (c *command) usageError(synopsis string) error {
+	fmt.Fprintln(c.stderr, "usage: metricsctl "+synopsis)
+	return ErrUsage
+}
+
+func formatValue(m domain.Metrics) string {
+	switch {
+	case m.Delta != nil:
+		return formatter.IntToString(*m.Delta)
+	case m.Value != nil:
+		return formatter.FloatToString(*m.Value)
+	default:
+		return ""
+	}
+}
+
+func envOr(name, fallback string) string {
+	if v := os.Getenv(name); v != "" {
+		return v
+	}
+	return fallback
+}
@@ -0,0 +1,95 @@
+package metricsctl
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/frolmr/metrics/internal/domain"
+)
+
+// Export formats, the same as accepted by export endpoint of the server.
+const (
+	ExportFormatCSV    = "csv"
+	ExportFormatNDJSON = "ndjson"
+	ExportFormatJSON   = "json"
+)
+
+// exportEncoder writes metrics one by one between begin and end of the document.
+type exportEncoder interface {
+	begin() error
+	encode(m domain.Metrics) error
+	end() error
+}
+
+func newExportEncoder(format string, w io.Writer) (exportEncoder, error) {
+	switch format {
+	case ExportFormatCSV:
+		return &csvEncoder{w: csv.NewWriter(w)}, nil
+	case ExportFormatNDJSON:
+		return &jsonEncoder{w: w, lines: true}, nil
+	case ExportFormatJSON:
+		return &jsonEncoder{w: w}, nil
+	default:
+		return nil, fmt.Errorf("unknown export format %q", format)
+	}
+}
+
+type csvEncoder struct {
+	w *csv.Writer
+}
+
+func (e *csvEncoder) begin() error {
+	return e.w.Write([]string{"id", "type", "value"})
+}
+
+func (e *csvEncoder) encode(m domain.Metrics) error {
+	return e.w.Write([]string{m.ID, m.MType, formatValue(m)})
+}
+
+func (e *csvEncoder) end() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+// jsonEncoder writes metrics as JSON array or, if lines is set, as one JSON object per line.
+type jsonEncoder struct {
+	w     io.Writer
+	lines bool
+	count int
+}
+
+func (e *jsonEncoder) begin() error {
+	if e.lines {
+		return nil
+	}
+	_, err := io.WriteString(e.w, "[")
+	return err
+}
+
+func (e *jsonEncoder) encode(m domain.Metrics) error {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+
+	if !e.lines && e.count > 0 {
+		data = append([]byte(",\n"), data...)
+	}
+	if e.lines {
+		data = append(data, '\n')
+	}
+	e.count++
+
+	_, err = e.w.Write(data)
+	return err
+}
+
+func (e *jsonEncoder) end() error {
+	if e.lines {
+		return nil
+	}
+	_, err := io.WriteString(e.w, "]\n")
+	return err
+}
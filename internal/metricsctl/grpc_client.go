@@ -0,0 +1,342 @@
+package metricsctl
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/frolmr/metrics/internal/domain"
+	"github.com/frolmr/metrics/pkg/encryptor"
+	pb "github.com/frolmr/metrics/pkg/proto/metrics"
+	"github.com/frolmr/metrics/pkg/signer"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// GRPCClient talks to gRPC API of the server.
+type GRPCClient struct {
+	opts   Options
+	conn   *grpc.ClientConn
+	client pb.MetricsClient
+	health healthpb.HealthClient
+}
+
+// NewGRPCClient function is constructor for gRPC client.
+func NewGRPCClient(opts Options) (*GRPCClient, error) {
+	// NOTE: payload is protected by crypto key the same way as over HTTP, not by transport
+	conn, err := grpc.NewClient(opts.Address, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to gRPC server: %w", err)
+	}
+
+	return &GRPCClient{
+		opts:   opts,
+		conn:   conn,
+		client: pb.NewMetricsClient(conn),
+		health: healthpb.NewHealthClient(conn),
+	}, nil
+}
+
+func (c *GRPCClient) Close() error {
+	return c.conn.Close()
+}
+
+func (c *GRPCClient) Ping(ctx context.Context) error {
+	resp, err := c.health.Check(c.outgoing(ctx), &healthpb.HealthCheckRequest{})
+	if err != nil {
+		return err
+	}
+	if resp.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+		return fmt.Errorf("%w: %s", ErrServer, resp.GetStatus())
+	}
+	return nil
+}
+
+func (c *GRPCClient) Get(ctx context.Context, mType, name string) (domain.Metrics, error) {
+	pbType, err := toProtoType(mType)
+	if err != nil {
+		return domain.Metrics{}, err
+	}
+
+	m, err := c.client.GetMetric(c.outgoing(ctx), &pb.GetMetricRequest{Key: name, Type: pbType})
+	if err != nil {
+		return domain.Metrics{}, err
+	}
+	return toDomainMetric(m), nil
+}
+
+func (c *GRPCClient) List(ctx context.Context, mType, prefix string, fn func(m domain.Metrics) error) error {
+	pbType, err := toProtoType(mType)
+	if err != nil {
+		return err
+	}
+
+	req := &pb.ListMetricsRequest{Type: pbType, Prefix: prefix, PageSize: domain.MaxListLimit}
+	for {
+		resp, err := c.client.ListMetrics(c.outgoing(ctx), req)
+		if err != nil {
+			return err
+		}
+		for _, m := range resp.GetMetrics() {
+			if err := fn(toDomainMetric(m)); err != nil {
+				return err
+			}
+		}
+		if resp.GetNextPageToken() == "" {
+			return nil
+		}
+		req.PageToken = resp.GetNextPageToken()
+	}
+}
+
+// Push sends metrics as the agent does: signed over plain request and encrypted.
+func (c *GRPCClient) Push(ctx context.Context, metrics []domain.Metrics) error {
+	rejected, err := c.update(ctx, metrics)
+	if err != nil {
+		return err
+	}
+	return rejectedError(rejected)
+}
+
+// Import decodes dump and pushes it in chunks, gRPC API has no import of its own.
+// Counters to overwrite are read first and pushed as deltas to the imported values.
+func (c *GRPCClient) Import(ctx context.Context, counters string, r io.Reader) (ImportResult, error) {
+	result := ImportResult{Errors: make([]domain.MetricStatus, 0)}
+	if err := checkCountersMode(counters); err != nil {
+		return result, err
+	}
+
+	var (
+		chunk   = make([]domain.Metrics, 0, importChunkSize)
+		indexes = make([]int, 0, importChunkSize)
+		index   int
+		// expected keeps overwritten counters, so that repeated counter of dump is not read before it's pushed
+		expected = make(map[string]int64)
+	)
+
+	push := func() error {
+		if len(chunk) == 0 {
+			return nil
+		}
+		rejected, err := c.update(ctx, chunk)
+		if err != nil {
+			return err
+		}
+		for _, status := range rejected {
+			status.Index = indexes[status.Index]
+			result.Errors = append(result.Errors, status)
+		}
+		result.Imported += len(chunk) - len(rejected)
+		result.Rejected += len(rejected)
+		chunk, indexes = chunk[:0], indexes[:0]
+		return nil
+	}
+
+	err := decodeDump(r, func(m domain.Metrics) error {
+		defer func() { index++ }()
+
+		if counters == ImportCountersOverwrite && m.MType == domain.CounterType && m.Delta != nil {
+			delta, err := c.counterDelta(ctx, m.ID, *m.Delta, expected)
+			if err != nil {
+				if !errors.Is(err, domain.ErrCounterOverflow) {
+					return err
+				}
+				result.Rejected++
+				result.Errors = append(result.Errors, domain.MetricStatus{Index: index, ID: m.ID, MType: m.MType, Reason: err.Error()})
+				return nil
+			}
+			m.Delta = &delta
+		}
+
+		chunk = append(chunk, m)
+		indexes = append(indexes, index)
+		if len(chunk) < importChunkSize {
+			return nil
+		}
+		return push()
+	})
+	if err != nil {
+		return result, err
+	}
+	return result, push()
+}
+
+// counterDelta returns delta which makes counter equal to target, missing counter is zero.
+func (c *GRPCClient) counterDelta(ctx context.Context, name string, target int64, expected map[string]int64) (int64, error) {
+	current, ok := expected[name]
+	if !ok {
+		m, err := c.Get(ctx, domain.CounterType, name)
+		switch {
+		case status.Code(err) == codes.NotFound:
+		case err != nil:
+			return 0, err
+		case m.Delta != nil:
+			current = *m.Delta
+		}
+	}
+
+	if (current > 0 && target < math.MinInt64+current) || (current < 0 && target > math.MaxInt64+current) {
+		return 0, domain.ErrCounterOverflow
+	}
+	expected[name] = target
+	return target - current, nil
+}
+
+// update sends metrics and returns the rejected ones, index of rejected metric is its position in metrics.
+func (c *GRPCClient) update(ctx context.Context, metrics []domain.Metrics) ([]domain.MetricStatus, error) {
+	req := &pb.UpdateMetricsBulkRequest{Metrics: make([]*pb.Metric, 0, len(metrics))}
+	for _, m := range metrics {
+		pm, err := toProtoMetric(m)
+		if err != nil {
+			return nil, err
+		}
+		req.Metrics = append(req.Metrics, pm)
+	}
+
+	ctx = c.outgoing(ctx)
+	if c.opts.Key != "" {
+		data, err := json.Marshal(req)
+		if err != nil {
+			return nil, err
+		}
+		ctx = metadata.AppendToOutgoingContext(ctx, domain.SignatureHeader,
+			hex.EncodeToString(signer.SignPayloadWithKey(data, []byte(c.opts.Key))))
+	}
+
+	if c.opts.CryptoKey != nil {
+		data, err := proto.Marshal(req)
+		if err != nil {
+			return nil, err
+		}
+		encrypted, err := encryptor.Encrypt(c.opts.CryptoKey, data)
+		if err != nil {
+			return nil, err
+		}
+		req = &pb.UpdateMetricsBulkRequest{EncryptedMetrics: encrypted}
+	}
+
+	resp, err := c.client.UpdateMetricsBulk(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	rejected := make([]domain.MetricStatus, 0, len(resp.GetRejected()))
+	for _, r := range resp.GetRejected() {
+		status := domain.MetricStatus{Index: int(r.GetIndex()), ID: r.GetKey(), Reason: r.GetError()}
+		if status.Index < len(metrics) {
+			status.MType = metrics[status.Index].MType
+		}
+		rejected = append(rejected, status)
+	}
+	if len(rejected) == 0 && !resp.GetReceived() {
+		return nil, fmt.Errorf("%w: %s", ErrServer, resp.GetError())
+	}
+	return rejected, nil
+}
+
+// Watch receives metric updates until ctx is done or server closes stream.
+func (c *GRPCClient) Watch(ctx context.Context, mType, prefix string, fn func(m domain.Metrics) error) error {
+	pbType, err := toProtoType(mType)
+	if err != nil {
+		return err
+	}
+
+	stream, err := c.client.WatchMetrics(c.outgoing(ctx), &pb.WatchMetricsRequest{Type: pbType, Prefix: prefix})
+	if err != nil {
+		return err
+	}
+
+	for {
+		m, err := stream.Recv()
+		switch {
+		case errors.Is(err, io.EOF), status.Code(err) == codes.Canceled && ctx.Err() != nil:
+			return nil
+		case err != nil:
+			return err
+		}
+		if err := fn(toDomainMetric(m)); err != nil {
+			return err
+		}
+	}
+}
+
+// Export lists metrics and encodes them on the client, gRPC API has no export of its own.
+func (c *GRPCClient) Export(ctx context.Context, format, mType, prefix string, w io.Writer) error {
+	enc, err := newExportEncoder(format, w)
+	if err != nil {
+		return err
+	}
+
+	if err := enc.begin(); err != nil {
+		return err
+	}
+	if err := c.List(ctx, mType, prefix, enc.encode); err != nil {
+		return err
+	}
+	return enc.end()
+}
+
+func (c *GRPCClient) outgoing(ctx context.Context) context.Context {
+	if c.opts.HostIP == "" {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, domain.RealIPHeader, c.opts.HostIP)
+}
+
+func toProtoType(mType string) (pb.Metric_MType, error) {
+	switch mType {
+	case "":
+		return pb.Metric_MTYPE_UNDEFINED, nil
+	case domain.CounterType:
+		return pb.Metric_MTYPE_COUNTER, nil
+	case domain.GaugeType:
+		return pb.Metric_MTYPE_GAUGE, nil
+	default:
+		return pb.Metric_MTYPE_UNDEFINED, fmt.Errorf("unknown metric type %q", mType)
+	}
+}
+
+func toProtoMetric(m domain.Metrics) (*pb.Metric, error) {
+	pbType, err := toProtoType(m.MType)
+	if err != nil {
+		return nil, err
+	}
+
+	pm := &pb.Metric{Key: m.ID, Type: pbType}
+	switch {
+	case m.Delta != nil:
+		pm.MValue = &pb.Metric_Delta{Delta: *m.Delta}
+	case m.Value != nil:
+		pm.MValue = &pb.Metric_Value{Value: *m.Value}
+	}
+	return pm, nil
+}
+
+func toDomainMetric(pm *pb.Metric) domain.Metrics {
+	m := domain.Metrics{ID: pm.GetKey()}
+	switch pm.GetType() {
+	case pb.Metric_MTYPE_COUNTER:
+		m.MType = domain.CounterType
+	case pb.Metric_MTYPE_GAUGE:
+		m.MType = domain.GaugeType
+	}
+
+	switch mValue := pm.GetMValue().(type) {
+	case *pb.Metric_Delta:
+		delta := mValue.Delta
+		m.Delta = &delta
+	case *pb.Metric_Value:
+		value := mValue.Value
+		m.Value = &value
+	}
+	return m
+}
@@ -0,0 +1,286 @@
+package metricsctl
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/frolmr/metrics/internal/domain"
+	"github.com/frolmr/metrics/pkg/encryptor"
+	"github.com/frolmr/metrics/pkg/signer"
+)
+
+// HTTPClient talks to versioned HTTP API of the server.
+type HTTPClient struct {
+	opts    Options
+	baseURL string
+	client  *http.Client
+}
+
+// NewHTTPClient function is constructor for HTTP client.
+func NewHTTPClient(opts Options) *HTTPClient {
+	return &HTTPClient{
+		opts:    opts,
+		baseURL: opts.Scheme + "://" + opts.Address + "/api/v1",
+		client:  &http.Client{},
+	}
+}
+
+func (c *HTTPClient) Close() error {
+	c.client.CloseIdleConnections()
+	return nil
+}
+
+func (c *HTTPClient) Ping(ctx context.Context) error {
+	resp, err := c.do(ctx, http.MethodGet, "/ping", nil, nil)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+func (c *HTTPClient) Get(ctx context.Context, mType, name string) (domain.Metrics, error) {
+	var m domain.Metrics
+	err := c.getJSON(ctx, "/value/"+url.PathEscape(mType)+"/"+url.PathEscape(name), nil, &m)
+	return m, err
+}
+
+func (c *HTTPClient) List(ctx context.Context, mType, prefix string, fn func(m domain.Metrics) error) error {
+	query := url.Values{"limit": {fmt.Sprint(domain.MaxListLimit)}}
+	setFilter(query, mType, prefix)
+
+	for {
+		var page domain.MetricsPage
+		if err := c.getJSON(ctx, "/metrics", query, &page); err != nil {
+			return err
+		}
+		for _, m := range page.Metrics {
+			if err := fn(m); err != nil {
+				return err
+			}
+		}
+		if page.NextCursor == "" {
+			return nil
+		}
+		query.Set("cursor", page.NextCursor)
+	}
+}
+
+// Push sends metrics as the agent does: signed, encrypted and compressed.
+func (c *HTTPClient) Push(ctx context.Context, metrics []domain.Metrics) error {
+	payload, err := json.Marshal(metrics)
+	if err != nil {
+		return err
+	}
+
+	body, headers, err := c.protect(payload)
+	if err != nil {
+		return err
+	}
+	headers.Set("Content-Type", domain.JSONContentType)
+
+	resp, err := c.do(ctx, http.MethodPost, "/updates", body, headers)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var result domain.UpdateResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return err
+	}
+	return rejectedError(result.Rejected)
+}
+
+// Import sends the whole dump to import endpoint protected the same way as Push.
+func (c *HTTPClient) Import(ctx context.Context, counters string, r io.Reader) (ImportResult, error) {
+	var result ImportResult
+	if err := checkCountersMode(counters); err != nil {
+		return result, err
+	}
+
+	payload, err := io.ReadAll(r)
+	if err != nil {
+		return result, err
+	}
+
+	contentType := ndjsonContentType
+	if isJSONArray(payload) {
+		contentType = domain.JSONContentType
+	}
+
+	body, headers, err := c.protect(payload)
+	if err != nil {
+		return result, err
+	}
+	headers.Set("Content-Type", contentType)
+
+	resp, err := c.do(ctx, http.MethodPost, "/import?"+url.Values{"counters": {counters}}.Encode(), body, headers)
+	if err != nil {
+		return result, err
+	}
+	defer resp.Body.Close()
+
+	err = json.NewDecoder(resp.Body).Decode(&result)
+	return result, err
+}
+
+// protect signs, encrypts and compresses payload as the agent does and returns headers telling server about it.
+func (c *HTTPClient) protect(payload []byte) (*bytes.Buffer, http.Header, error) {
+	headers := http.Header{}
+	headers.Set("Content-Encoding", domain.CompressFormat)
+	if c.opts.Key != "" {
+		headers.Set(domain.SignatureHeader, hex.EncodeToString(signer.SignPayloadWithKey(payload, []byte(c.opts.Key))))
+	}
+
+	if c.opts.CryptoKey != nil {
+		var err error
+		if payload, err = encryptor.Encrypt(c.opts.CryptoKey, payload); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var body bytes.Buffer
+	zw := gzip.NewWriter(&body)
+	if _, err := zw.Write(payload); err != nil {
+		return nil, nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, nil, err
+	}
+	return &body, headers, nil
+}
+
+// Watch reads server-sent events of metric updates until ctx is done or server closes stream.
+func (c *HTTPClient) Watch(ctx context.Context, mType, prefix string, fn func(m domain.Metrics) error) error {
+	query := url.Values{}
+	setFilter(query, mType, prefix)
+
+	headers := http.Header{}
+	headers.Set("Accept", "text/event-stream")
+
+	resp, err := c.do(ctx, http.MethodGet, "/stream?"+query.Encode(), nil, headers)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var event string
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "event: "):
+			event = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: ") && event == "metric":
+			var m domain.Metrics
+			if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &m); err != nil {
+				return err
+			}
+			if err := fn(m); err != nil {
+				return err
+			}
+		case line == "":
+			event = ""
+		}
+	}
+
+	if ctx.Err() != nil {
+		return nil
+	}
+	return scanner.Err()
+}
+
+// Export copies server export to w as is.
+func (c *HTTPClient) Export(ctx context.Context, format, mType, prefix string, w io.Writer) error {
+	query := url.Values{"format": {format}}
+	setFilter(query, mType, prefix)
+
+	resp, err := c.do(ctx, http.MethodGet, "/export?"+query.Encode(), nil, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	_, err = io.Copy(w, resp.Body)
+	return err
+}
+
+func (c *HTTPClient) getJSON(ctx context.Context, path string, query url.Values, v any) error {
+	if len(query) != 0 {
+		path += "?" + query.Encode()
+	}
+
+	resp, err := c.do(ctx, http.MethodGet, path, nil, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// do sends request and turns error responses into errors, caller closes body of successful response.
+func (c *HTTPClient) do(ctx context.Context, method, path string, body io.Reader, headers http.Header) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range headers {
+		req.Header[k] = v
+	}
+	if req.Header.Get("Accept") == "" {
+		req.Header.Set("Accept", domain.JSONContentType)
+	}
+	if c.opts.HostIP != "" {
+		req.Header.Set(domain.RealIPHeader, c.opts.HostIP)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < http.StatusMultipleChoices {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+
+	var apiErr struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	}
+	data, _ := io.ReadAll(resp.Body)
+	if json.Unmarshal(data, &apiErr) == nil && apiErr.Message != "" {
+		return nil, fmt.Errorf("%w: %s: %s: %s", ErrServer, resp.Status, apiErr.Code, apiErr.Message)
+	}
+	return nil, fmt.Errorf("%w: %s: %s", ErrServer, resp.Status, strings.TrimSpace(string(data)))
+}
+
+func setFilter(query url.Values, mType, prefix string) {
+	if mType != "" {
+		query.Set("type", mType)
+	}
+	if prefix != "" {
+		query.Set("prefix", prefix)
+	}
+}
+
+func rejectedError(rejected []domain.MetricStatus) error {
+	if len(rejected) == 0 {
+		return nil
+	}
+
+	reasons := make([]string, 0, len(rejected))
+	for _, r := range rejected {
+		reasons = append(reasons, r.ID+": "+r.Reason)
+	}
+	return fmt.Errorf("%w: %s", ErrRejected, strings.Join(reasons, "; "))
+}
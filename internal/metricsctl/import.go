@@ -0,0 +1,80 @@
+package metricsctl
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/frolmr/metrics/internal/domain"
+)
+
+// Counter modes of import, the same as accepted by import endpoint of the server.
+const (
+	// ImportCountersAdd adds imported values to existing counters.
+	ImportCountersAdd = "add"
+	// ImportCountersOverwrite sets counters to imported values.
+	ImportCountersOverwrite = "overwrite"
+)
+
+const (
+	// importChunkSize is the most metrics pushed at once when import is done by the client.
+	importChunkSize = 1000
+
+	ndjsonContentType = "application/x-ndjson"
+)
+
+// ImportResult tells how many metrics were imported and why the others were rejected.
+type ImportResult struct {
+	Imported int `json:"imported"`
+	Rejected int `json:"rejected"`
+	// Errors lists rejected metrics, index is the position of metric in the dump.
+	Errors []domain.MetricStatus `json:"errors"`
+}
+
+func checkCountersMode(mode string) error {
+	switch mode {
+	case ImportCountersAdd, ImportCountersOverwrite:
+		return nil
+	default:
+		return fmt.Errorf("unknown counters mode %q", mode)
+	}
+}
+
+// isJSONArray tells JSON array dump from NDJSON one by its first significant byte.
+func isJSONArray(data []byte) bool {
+	trimmed := bytes.TrimLeft(data, " \t\r\n")
+	return len(trimmed) != 0 && trimmed[0] == '['
+}
+
+// decodeDump passes metrics of JSON array or NDJSON dump to fn one by one.
+func decodeDump(r io.Reader, fn func(m domain.Metrics) error) error {
+	br := bufio.NewReader(r)
+	head, _ := br.Peek(512)
+	dec := json.NewDecoder(br)
+
+	isArray := isJSONArray(head)
+	if isArray {
+		if _, err := dec.Token(); err != nil {
+			return err
+		}
+	}
+
+	for index := 0; dec.More(); index++ {
+		var m domain.Metrics
+		if err := dec.Decode(&m); err != nil {
+			return fmt.Errorf("invalid dump at index %d: %w", index, err)
+		}
+		if err := fn(m); err != nil {
+			return err
+		}
+	}
+
+	if isArray {
+		if _, err := dec.Token(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
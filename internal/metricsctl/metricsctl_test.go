@@ -0,0 +1,265 @@
+package metricsctl
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"net"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/frolmr/metrics/internal/server/application"
+	"github.com/frolmr/metrics/internal/server/config"
+	"github.com/frolmr/metrics/internal/server/controller"
+	"github.com/frolmr/metrics/internal/server/decryptor"
+	"github.com/frolmr/metrics/internal/server/interceptors"
+	"github.com/frolmr/metrics/internal/server/logger"
+	"github.com/frolmr/metrics/internal/server/pubsub"
+	"github.com/frolmr/metrics/internal/server/storage"
+	pb "github.com/frolmr/metrics/pkg/proto/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+const testKey = "secret"
+
+// syncBuffer is written by watch while test reads it.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+type testServer struct {
+	scheme  string
+	address string
+	hub     *pubsub.Hub
+}
+
+// writeKeyPair generates crypto key, saves public part for the tool and returns private part for the server.
+func writeKeyPair(t *testing.T) (string, *rsa.PrivateKey) {
+	t.Helper()
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "public.pem")
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0600))
+	return path, privateKey
+}
+
+func newHTTPServer(t *testing.T, privateKey *rsa.PrivateKey) testServer {
+	t.Helper()
+
+	_, subnet, err := net.ParseCIDR("127.0.0.0/8")
+	require.NoError(t, err)
+
+	lgr := &logger.Logger{SugaredLogger: *zap.NewNop().Sugar()}
+	hub := pubsub.NewHub(10)
+	stor := storage.NewPublishingStorage(storage.NewMemStorage(), hub)
+	cfg := &config.Config{Key: testKey, CryptoKey: privateKey, TrustedSubnet: subnet}
+
+	ts := httptest.NewServer(controller.NewController(lgr, cfg).SetupHandlers(stor, hub))
+	t.Cleanup(ts.Close)
+	return testServer{scheme: "http", address: ts.Listener.Addr().String(), hub: hub}
+}
+
+func newGRPCServer(t *testing.T, privateKey *rsa.PrivateKey) testServer {
+	t.Helper()
+
+	hub := pubsub.NewHub(10)
+	stor := storage.NewPublishingStorage(storage.NewMemStorage(), hub)
+	d := decryptor.NewDecryptor(privateKey)
+
+	s := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(interceptors.NewDecryptInterceptor(d), interceptors.NewSignatureInterceptor(testKey)),
+		grpc.ChainStreamInterceptor(interceptors.NewStreamDecryptInterceptor(d), interceptors.NewStreamSignatureInterceptor(testKey)),
+	)
+	pb.RegisterMetricsServer(s, application.NewMetricsServer(stor, hub, nil))
+	healthpb.RegisterHealthServer(s, application.NewHealthServer(stor))
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go func() { _ = s.Serve(lis) }()
+	t.Cleanup(s.Stop)
+
+	return testServer{scheme: "grpc", address: lis.Addr().String(), hub: hub}
+}
+
+func TestRun(t *testing.T) {
+	keyPath, privateKey := writeKeyPair(t)
+
+	servers := map[string]testServer{
+		"http": newHTTPServer(t, privateKey),
+		"grpc": newGRPCServer(t, privateKey),
+	}
+
+	for name, srv := range servers {
+		t.Run(name, func(t *testing.T) {
+			run := func(args ...string) (string, error) {
+				var stdout, stderr bytes.Buffer
+				flags := []string{"-s", srv.scheme, "-a", srv.address, "-k", testKey, "-crypto-key", keyPath, "-host-ip", "127.0.0.1"}
+				err := Run(context.Background(), append(flags, args...), &stdout, &stderr)
+				return stdout.String(), err
+			}
+
+			out, err := run("ping")
+			require.NoError(t, err)
+			assert.Equal(t, "ok\n", out)
+
+			_, err = run("push", "counter", "hits", "3")
+			require.NoError(t, err)
+			_, err = run("push", "counter", "hits", "2")
+			require.NoError(t, err)
+			_, err = run("push", "gauge", "load", "1.5")
+			require.NoError(t, err)
+			_, err = run("push", "gauge", "mem", "42")
+			require.NoError(t, err)
+
+			out, err = run("get", "counter", "hits")
+			require.NoError(t, err)
+			assert.Equal(t, "5\n", out, "counter deltas are added")
+
+			_, err = run("get", "gauge", "missing")
+			assert.Error(t, err)
+
+			out, err = run("list", "-type", "gauge")
+			require.NoError(t, err)
+			assert.Equal(t, "gauge\tload\t1.5\ngauge\tmem\t42\n", out)
+
+			out, err = run("list", "-prefix", "h")
+			require.NoError(t, err)
+			assert.Equal(t, "counter\thits\t5\n", out)
+
+			out, err = run("export", "-format", "csv", "-type", "gauge")
+			require.NoError(t, err)
+			assert.Equal(t, "id,type,value\nload,gauge,1.5\nmem,gauge,42\n", out)
+
+			out, err = run("export", "-format", "ndjson", "-prefix", "h")
+			require.NoError(t, err)
+			assert.Equal(t, `{"id":"hits","type":"counter","delta":5}`+"\n", out)
+
+			_, err = run("push", "gauge", "load", "not-a-number")
+			assert.Error(t, err)
+
+			dir := t.TempDir()
+			arrayDump := filepath.Join(dir, "dump.json")
+			require.NoError(t, os.WriteFile(arrayDump, []byte(`[
+				{"id":"hits","type":"counter","delta":100},
+				{"id":"disk","type":"gauge","value":7},
+				{"id":"","type":"gauge","value":1}
+			]`), 0600))
+			out, err = run("import", "-counters", "overwrite", arrayDump)
+			require.NoError(t, err)
+			assert.Equal(t, "imported 2 metrics, rejected 1\n", out)
+
+			out, err = run("get", "counter", "hits")
+			require.NoError(t, err)
+			assert.Equal(t, "100\n", out, "counter is overwritten")
+
+			ndjsonDump := filepath.Join(dir, "dump.ndjson")
+			require.NoError(t, os.WriteFile(ndjsonDump, []byte(
+				`{"id":"hits","type":"counter","delta":1}`+"\n"+`{"id":"hits","type":"counter","delta":2}`+"\n"), 0600))
+			out, err = run("import", ndjsonDump)
+			require.NoError(t, err)
+			assert.Equal(t, "imported 2 metrics, rejected 0\n", out)
+
+			out, err = run("get", "counter", "hits")
+			require.NoError(t, err)
+			assert.Equal(t, "103\n", out, "counters are added by default")
+
+			_, err = run("import", "-counters", "replace", ndjsonDump)
+			assert.Error(t, err)
+
+			_, err = run("frobnicate")
+			assert.ErrorIs(t, err, ErrUsage)
+		})
+	}
+}
+
+func TestRunRejected(t *testing.T) {
+	keyPath, privateKey := writeKeyPair(t)
+	srv := newHTTPServer(t, privateKey)
+
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{name: "wrong key", args: []string{"-k", "wrong", "-crypto-key", keyPath, "-host-ip", "127.0.0.1"}},
+		{name: "no crypto key", args: []string{"-k", testKey, "-host-ip", "127.0.0.1"}},
+		{name: "untrusted host", args: []string{"-k", testKey, "-crypto-key", keyPath, "-host-ip", "10.0.0.1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := append([]string{"-a", srv.address}, tt.args...)
+			err := Run(context.Background(), append(args, "push", "gauge", "load", "1"), &bytes.Buffer{}, &bytes.Buffer{})
+			assert.ErrorIs(t, err, ErrServer)
+		})
+	}
+}
+
+func TestRunWatch(t *testing.T) {
+	keyPath, privateKey := writeKeyPair(t)
+
+	servers := map[string]testServer{
+		"http": newHTTPServer(t, privateKey),
+		"grpc": newGRPCServer(t, privateKey),
+	}
+
+	for name, srv := range servers {
+		t.Run(name, func(t *testing.T) {
+			flags := []string{"-s", srv.scheme, "-a", srv.address, "-k", testKey, "-crypto-key", keyPath, "-host-ip", "127.0.0.1"}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			var stdout syncBuffer
+			done := make(chan error, 1)
+			go func() {
+				done <- Run(ctx, append(flags, "watch", "-prefix", "cpu"), &stdout, &bytes.Buffer{})
+			}()
+			require.Eventually(t, func() bool { return srv.hub.Subscribers() == 1 }, time.Second, 10*time.Millisecond)
+
+			push := append(flags, "push", "gauge", "mem", "1")
+			require.NoError(t, Run(context.Background(), push, &bytes.Buffer{}, &bytes.Buffer{}))
+			push = append(flags, "push", "gauge", "cpu", "0.5")
+			require.NoError(t, Run(context.Background(), push, &bytes.Buffer{}, &bytes.Buffer{}))
+
+			require.Eventually(t, func() bool { return strings.Contains(stdout.String(), "cpu") }, time.Second, 10*time.Millisecond)
+			assert.Equal(t, "gauge\tcpu\t0.5\n", stdout.String())
+
+			cancel()
+			select {
+			case err := <-done:
+				assert.NoError(t, err)
+			case <-time.After(time.Second):
+				t.Fatal("watch didn't stop")
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+// Package apierror writes HTTP errors either as JSON for API clients or as plain text for legacy ones.
+package apierror
+
+import (
+	"encoding/json"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/frolmr/metrics/internal/domain"
+)
+
+// APIPrefix is the path prefix of versioned API, all its errors are JSON.
+const APIPrefix = "/api/v1"
+
+// Error codes returned in JSON error body.
+const (
+	CodeBadRequest           = "bad_request"
+	CodeValidationFailed     = "validation_failed"
+	CodeInvalidSignature     = "invalid_signature"
+	CodeDecryptionFailed     = "decryption_failed"
+	CodeForbidden            = "forbidden"
+	CodeNotFound             = "not_found"
+	CodeMethodNotAllowed     = "method_not_allowed"
+	CodeNotAcceptable        = "not_acceptable"
+	CodeUnsupportedMediaType = "unsupported_media_type"
+	CodeInternal             = "internal_error"
+	CodeUnavailable          = "unavailable"
+)
+
+// Error is the JSON body of API error response.
+// @Description API error.
+type Error struct {
+	// Code is a stable machine readable error code.
+	// Example: "validation_failed"
+	Code string `json:"code"`
+
+	// Message is a human readable description.
+	// Example: "invalid metric name"
+	Message string `json:"message"`
+
+	// Details holds optional structured information about the error.
+	Details any `json:"details,omitempty"`
+}
+
+// Write writes error as JSON if client is an API client, otherwise as plain text the way http.Error does.
+func Write(res http.ResponseWriter, req *http.Request, status int, code, message string, details any) {
+	if !WantsJSON(req) {
+		http.Error(res, message, status)
+		return
+	}
+
+	body, err := json.Marshal(Error{Code: code, Message: message, Details: details})
+	if err != nil {
+		http.Error(res, message, status)
+		return
+	}
+
+	res.Header().Del("Content-Length")
+	res.Header().Set("Content-Type", domain.JSONContentType)
+	res.Header().Set("X-Content-Type-Options", "nosniff")
+	res.WriteHeader(status)
+	_, _ = res.Write(body)
+}
+
+// WantsJSON reports whether request comes to versioned API or explicitly accepts JSON.
+func WantsJSON(req *http.Request) bool {
+	if IsAPIRequest(req) {
+		return true
+	}
+	return strings.Contains(req.Header.Get("Accept"), domain.JSONContentType)
+}
+
+// IsAPIRequest reports whether request path belongs to versioned API.
+func IsAPIRequest(req *http.Request) bool {
+	return req.URL.Path == APIPrefix || strings.HasPrefix(req.URL.Path, APIPrefix+"/")
+}
+
+// Accepts reports whether Accept header allows media type, missing header accepts anything.
+func Accepts(req *http.Request, mediaType string) bool {
+	accept := req.Header.Get("Accept")
+	if accept == "" {
+		return true
+	}
+
+	majorType, _, _ := strings.Cut(mediaType, "/")
+	for _, part := range strings.Split(accept, ",") {
+		accepted, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil || params["q"] == "0" {
+			continue
+		}
+		if accepted == "*/*" || accepted == mediaType || accepted == majorType+"/*" {
+			return true
+		}
+	}
+	return false
+}
+
+// Negotiate returns the first of offered media types allowed by Accept header, empty string if none is.
+func Negotiate(req *http.Request, offers ...string) string {
+	for _, offer := range offers {
+		if Accepts(req, offer) {
+			return offer
+		}
+	}
+	return ""
+}
+
+// HasContentType reports whether request body has the media type, parameters like charset are ignored.
+func HasContentType(req *http.Request, mediaType string) bool {
+	contentType, _, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	return err == nil && contentType == mediaType
+}
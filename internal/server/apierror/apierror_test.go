@@ -0,0 +1,109 @@
+package apierror
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/frolmr/metrics/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWrite(t *testing.T) {
+	t.Run("JSON for versioned API", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, APIPrefix+"/value/gauge/x", nil)
+		rec := httptest.NewRecorder()
+
+		Write(rec, req, http.StatusNotFound, CodeNotFound, "metric not found", map[string]string{"id": "x"})
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.Equal(t, domain.JSONContentType, rec.Header().Get("Content-Type"))
+
+		var body Error
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+		assert.Equal(t, CodeNotFound, body.Code)
+		assert.Equal(t, "metric not found", body.Message)
+		assert.Equal(t, map[string]any{"id": "x"}, body.Details)
+	})
+
+	t.Run("JSON for legacy route when accepted", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/value/gauge/x", nil)
+		req.Header.Set("Accept", domain.JSONContentType)
+		rec := httptest.NewRecorder()
+
+		Write(rec, req, http.StatusBadRequest, CodeBadRequest, "bad", nil)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.JSONEq(t, `{"code":"bad_request","message":"bad"}`, rec.Body.String())
+	})
+
+	t.Run("plain text for legacy route", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/value/gauge/x", nil)
+		rec := httptest.NewRecorder()
+
+		Write(rec, req, http.StatusBadRequest, CodeBadRequest, "bad", nil)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, "bad\n", rec.Body.String())
+	})
+}
+
+func TestIsAPIRequest(t *testing.T) {
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{path: "/api/v1", want: true},
+		{path: "/api/v1/ping", want: true},
+		{path: "/api/v10/ping", want: false},
+		{path: "/ping", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			assert.Equal(t, tt.want, IsAPIRequest(req))
+		})
+	}
+}
+
+func TestNegotiate(t *testing.T) {
+	offers := []string{domain.JSONContentType, domain.TextContentType}
+
+	tests := []struct {
+		name   string
+		accept string
+		want   string
+	}{
+		{name: "no header", accept: "", want: domain.JSONContentType},
+		{name: "any", accept: "*/*", want: domain.JSONContentType},
+		{name: "text only", accept: "text/plain", want: domain.TextContentType},
+		{name: "text wildcard", accept: "text/*", want: domain.TextContentType},
+		{name: "json with params", accept: "application/json; charset=utf-8", want: domain.JSONContentType},
+		{name: "json refused", accept: "application/json;q=0, text/plain", want: domain.TextContentType},
+		{name: "nothing suitable", accept: "image/png", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			assert.Equal(t, tt.want, Negotiate(req, offers...))
+		})
+	}
+}
+
+func TestHasContentType(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	assert.False(t, HasContentType(req, domain.JSONContentType))
+
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	assert.True(t, HasContentType(req, domain.JSONContentType))
+
+	req.Header.Set("Content-Type", domain.TextContentType)
+	assert.False(t, HasContentType(req, domain.JSONContentType))
+}
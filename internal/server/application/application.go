@@ -8,6 +8,7 @@ import (
 	"log"
 	"net"
 	"net/http"
+	"strings"
 	"sync"
 	"time"
 
@@ -16,54 +17,100 @@ import (
 	pb "github.com/frolmr/metrics/pkg/proto/metrics"
 	_ "github.com/jackc/pgx/v5/stdlib"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
 
+	"github.com/frolmr/metrics/internal/domain"
 	"github.com/frolmr/metrics/internal/server/config"
 	"github.com/frolmr/metrics/internal/server/controller"
 	"github.com/frolmr/metrics/internal/server/db/migrator"
+	"github.com/frolmr/metrics/internal/server/decryptor"
 	"github.com/frolmr/metrics/internal/server/interceptors"
 	"github.com/frolmr/metrics/internal/server/logger"
+	"github.com/frolmr/metrics/internal/server/pubsub"
 	"github.com/frolmr/metrics/internal/server/storage"
 )
 
 type Application struct {
 	config         *config.Config
 	logger         *logger.Logger
+	mu             sync.Mutex
 	httpServer     *http.Server
+	grpcServer     *grpc.Server
+	healthServer   *HealthServer
+	statsdServer   *StatsDServer
+	graphiteServer *GraphiteServer
 	pprofServer    *http.Server
 	snapshotCancel context.CancelFunc
 	wg             sync.WaitGroup
+	hub            *pubsub.Hub
+	requestCounter *interceptors.RequestCounter
 }
 
+type serveFunc func() error
+
 func NewApplication(cfg *config.Config, lgr *logger.Logger) *Application {
 	return &Application{
-		config: cfg,
-		logger: lgr,
+		config:         cfg,
+		logger:         lgr,
+		hub:            pubsub.NewHub(pubsub.DefaultBufferSize),
+		requestCounter: interceptors.NewRequestCounter(),
 	}
 }
 
+// RunServer starts HTTP and/or gRPC servers sharing one storage and blocks until they stop.
+// With http(s) scheme gRPC is served additionally when GRPCAddress is set: on a separate
+// listener or, if GRPCAddress equals HTTPAddress, multiplexed on the HTTP port.
 func (app *Application) RunServer() error {
 	storage, storageErr := app.setupStorage()
 	if storageErr != nil {
 		return fmt.Errorf("error while storage setup: %w", storageErr)
 	}
 
-	switch app.config.Scheme {
-	case "http", "https":
-		return app.runHTTPServer(storage)
-	case "grpc":
-		return app.runGRPCServer(storage)
-	default:
-		return errors.New("unknown protocol")
+	servers, err := app.setupServers(storage)
+	if err != nil {
+		return err
+	}
+
+	if app.config.StatsDAddress != "" {
+		statsdServe, err := app.setupStatsDServer(storage)
+		if err != nil {
+			return err
+		}
+		servers = append(servers, statsdServe)
+	}
+
+	if app.config.GraphiteAddress != "" {
+		graphiteServe, err := app.setupGraphiteServer(storage)
+		if err != nil {
+			return err
+		}
+		servers = append(servers, graphiteServe)
 	}
+
+	errCh := make(chan error, len(servers))
+	for _, serve := range servers {
+		go func() {
+			errCh <- serve()
+		}()
+	}
+
+	for range servers {
+		if err := <-errCh; err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
+// RunProfServer serves pprof and gRPC request counts on localhost.
 func (app *Application) RunProfServer() {
 	app.pprofServer = &http.Server{
 		Addr:         "localhost:6060",
 		ReadTimeout:  3 * time.Second,
 		WriteTimeout: 3 * time.Second,
 		IdleTimeout:  5 * time.Second,
+		Handler:      app.profHandler(),
 	}
 
 	log.Println("Starting pprof server on :6060...")
@@ -72,42 +119,195 @@ func (app *Application) RunProfServer() {
 	}
 }
 
-func (app *Application) runHTTPServer(stor storage.Repository) error {
-	ctrl := controller.NewController(app.logger, app.config)
+// profHandler serves gRPC request counts on /debug/grpc/requests and pprof registered in default mux.
+// NOTE: counts are kept out of metric storage, so they never mix with user metrics
+func (app *Application) profHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/debug/grpc/requests", app.requestCounter)
+	mux.Handle("/", http.DefaultServeMux)
+	return mux
+}
+
+func (app *Application) setupServers(stor storage.Repository) ([]serveFunc, error) {
+	app.mu.Lock()
+	defer app.mu.Unlock()
+
+	switch app.config.Scheme {
+	case "http", "https":
+		handler := controller.NewController(app.logger, app.config).SetupHandlers(stor, app.hub)
+
+		if app.config.GRPCAddress == "" {
+			httpServe, err := app.setupHTTPServer(handler, false)
+			if err != nil {
+				return nil, err
+			}
+			return []serveFunc{httpServe}, nil
+		}
+
+		grpcServer, err := app.setupGRPCServer(stor)
+		if err != nil {
+			return nil, err
+		}
+
+		if app.config.GRPCAddress == app.config.HTTPAddress {
+			httpServe, err := app.setupHTTPServer(withGRPC(grpcServer, handler), true)
+			if err != nil {
+				return nil, err
+			}
+			app.logger.SugaredLogger.Infof("Serving gRPC on HTTP port %s", app.config.HTTPAddress)
+			return []serveFunc{httpServe}, nil
+		}
+
+		httpServe, err := app.setupHTTPServer(handler, false)
+		if err != nil {
+			return nil, err
+		}
+		grpcServe, err := app.listenGRPC(grpcServer, app.config.GRPCAddress)
+		if err != nil {
+			return nil, err
+		}
+		return []serveFunc{httpServe, grpcServe}, nil
+	case "grpc":
+		grpcServer, err := app.setupGRPCServer(stor)
+		if err != nil {
+			return nil, err
+		}
+		grpcServe, err := app.listenGRPC(grpcServer, app.config.HTTPAddress)
+		if err != nil {
+			return nil, err
+		}
+		return []serveFunc{grpcServe}, nil
+	default:
+		return nil, errors.New("unknown protocol")
+	}
+}
+
+func (app *Application) setupHTTPServer(handler http.Handler, withH2C bool) (serveFunc, error) {
+	listen, err := net.Listen("tcp", app.config.HTTPAddress)
+	if err != nil {
+		return nil, err
+	}
 
 	app.httpServer = &http.Server{
 		Addr:              app.config.HTTPAddress,
 		ReadHeaderTimeout: 3 * time.Second,
-		Handler:           ctrl.SetupHandlers(stor),
+		Handler:           handler,
 	}
 
-	app.logger.SugaredLogger.Infof("Starting HTTP server on %s", app.config.HTTPAddress)
-	return app.httpServer.ListenAndServe()
+	if withH2C {
+		var protocols http.Protocols
+		protocols.SetHTTP1(true)
+		protocols.SetUnencryptedHTTP2(true)
+		app.httpServer.Protocols = &protocols
+	}
+
+	httpServer := app.httpServer
+	return func() error {
+		app.logger.SugaredLogger.Infof("Starting HTTP server on %s", app.config.HTTPAddress)
+		if err := httpServer.Serve(listen); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+		return nil
+	}, nil
 }
 
-func (app *Application) runGRPCServer(stor storage.Repository) error {
-	listen, err := net.Listen("tcp", app.config.HTTPAddress)
-	if err != nil {
-		return err
+func (app *Application) setupGRPCServer(stor storage.Repository) (*grpc.Server, error) {
+	// NOTE: logging and counting go first so that they see status of recovered panics and rejected signatures
+	unaryInterceptors := []grpc.UnaryServerInterceptor{
+		interceptors.NewLoggingInterceptor(app.logger),
+		app.requestCounter.UnaryInterceptor(),
+		interceptors.NewRecoveryInterceptor(app.logger),
+	}
+	streamInterceptors := []grpc.StreamServerInterceptor{
+		interceptors.NewStreamLoggingInterceptor(app.logger),
+		app.requestCounter.StreamInterceptor(),
+		interceptors.NewStreamRecoveryInterceptor(app.logger),
 	}
 
-	var opts []grpc.ServerOption
+	// NOTE: metrics are decrypted before signature check, agent signs plain metrics
+	if app.config.CryptoKey != nil {
+		d := decryptor.NewDecryptor(app.config.CryptoKey)
+		unaryInterceptors = append(unaryInterceptors, interceptors.NewDecryptInterceptor(d))
+		streamInterceptors = append(streamInterceptors, interceptors.NewStreamDecryptInterceptor(d))
+	}
 
 	if app.config.Key != "" {
-		opts = append(opts, grpc.UnaryInterceptor(interceptors.NewSignatureInterceptor(app.config.Key)))
+		unaryInterceptors = append(unaryInterceptors, interceptors.NewSignatureInterceptor(app.config.Key))
+		streamInterceptors = append(streamInterceptors, interceptors.NewStreamSignatureInterceptor(app.config.Key))
 	}
 
-	if app.config.CryptoKey != nil {
-		creds, err := credentials.NewServerTLSFromFile("server.crt", "server.key")
-		if err != nil {
-			return fmt.Errorf("failed to create TLS credentials: %w", err)
-		}
-		opts = append(opts, grpc.Creds(creds))
+	opts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(unaryInterceptors...),
+		grpc.ChainStreamInterceptor(streamInterceptors...),
+	}
+
+	app.grpcServer = grpc.NewServer(opts...)
+	pb.RegisterMetricsServer(app.grpcServer, NewMetricsServer(stor, app.hub, app.config.Validator))
+	app.healthServer = NewHealthServer(stor)
+	healthpb.RegisterHealthServer(app.grpcServer, app.healthServer)
+	reflection.Register(app.grpcServer)
+
+	return app.grpcServer, nil
+}
+
+func (app *Application) setupStatsDServer(stor storage.Repository) (serveFunc, error) {
+	conn, err := net.ListenPacket("udp", app.config.StatsDAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	app.mu.Lock()
+	defer app.mu.Unlock()
+
+	app.statsdServer = NewStatsDServer(conn, stor, app.config.Validator, app.config.IngestRules,
+		app.config.StatsDFlushInterval, app.logger)
+
+	statsdServer := app.statsdServer
+	return func() error {
+		app.logger.SugaredLogger.Infof("Starting StatsD listener on %s", conn.LocalAddr())
+		return statsdServer.Serve()
+	}, nil
+}
+
+func (app *Application) setupGraphiteServer(stor storage.Repository) (serveFunc, error) {
+	listen, err := net.Listen("tcp", app.config.GraphiteAddress)
+	if err != nil {
+		return nil, err
 	}
 
-	s := grpc.NewServer(opts...)
-	pb.RegisterMetricsServer(s, NewMetricsServer(stor))
-	return s.Serve(listen)
+	app.mu.Lock()
+	defer app.mu.Unlock()
+
+	app.graphiteServer = NewGraphiteServer(listen, stor, app.config.Validator, app.config.IngestRules, app.logger)
+
+	graphiteServer := app.graphiteServer
+	return func() error {
+		app.logger.SugaredLogger.Infof("Starting Graphite listener on %s", listen.Addr())
+		return graphiteServer.Serve()
+	}, nil
+}
+
+func (app *Application) listenGRPC(grpcServer *grpc.Server, address string) (serveFunc, error) {
+	listen, err := net.Listen("tcp", address)
+	if err != nil {
+		return nil, err
+	}
+
+	return func() error {
+		app.logger.SugaredLogger.Infof("Starting gRPC server on %s", address)
+		return grpcServer.Serve(listen)
+	}, nil
+}
+
+// withGRPC routes HTTP/2 gRPC requests to gRPC server and everything else to HTTP handler.
+func withGRPC(grpcServer *grpc.Server, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		if req.ProtoMajor == 2 && strings.HasPrefix(req.Header.Get("Content-Type"), "application/grpc") {
+			grpcServer.ServeHTTP(res, req)
+			return
+		}
+		next.ServeHTTP(res, req)
+	})
 }
 
 func (app *Application) setupStorage() (storage.Repository, error) {
@@ -117,12 +317,13 @@ func (app *Application) setupStorage() (storage.Repository, error) {
 			return nil, fmt.Errorf("could not setup DB: %w", err)
 		}
 		retriableStor := storage.NewRetriableStorage(storage.NewDBStorage(db))
-		return retriableStor, nil
+		return storage.NewPublishingStorage(retriableStor, app.hub), nil
 	}
 
 	memstor := storage.NewMemStorage()
+	memstor.SetValidator(app.config.Validator)
 	app.setupSnapshots(memstor)
-	return memstor, nil
+	return storage.NewPublishingStorage(memstor, app.hub), nil
 }
 
 func (app *Application) setupDB() (*sql.DB, error) {
@@ -178,14 +379,44 @@ func (app *Application) runSnapshotSaver(ctx context.Context, fs *storage.FileSn
 	}
 }
 
+// Shutdown stops all servers gracefully, watchers are disconnected first so that streams don't hold it.
 func (app *Application) Shutdown(ctx context.Context) error {
-	if app.snapshotCancel != nil {
-		app.snapshotCancel()
+	app.mu.Lock()
+	httpServer, grpcServer, healthServer := app.httpServer, app.grpcServer, app.healthServer
+	statsdServer, graphiteServer := app.statsdServer, app.graphiteServer
+	app.mu.Unlock()
+
+	// NOTE: snapshot saver is stopped last, so that metrics saved by listeners and by requests still in flight
+	// get into the final snapshot
+	var statsdErr, graphiteErr error
+	if statsdServer != nil {
+		statsdErr = statsdServer.Shutdown(ctx)
+	}
+	if graphiteServer != nil {
+		graphiteErr = graphiteServer.Shutdown(ctx)
 	}
 
+	app.hub.Close()
+
+	if healthServer != nil {
+		healthServer.Shutdown()
+	}
+
+	if grpcServer != nil {
+		stopGRPCServer(ctx, grpcServer)
+		for key, count := range app.requestCounter.Snapshot() {
+			app.logger.SugaredLogger.Infoln("method", key.Method, "code", key.Code, "requests", count)
+		}
+	}
+
+	// NOTE: HTTP shutdown waits for requests in flight, gRPC multiplexed on the HTTP port included
 	var httpErr error
-	if app.httpServer != nil {
-		httpErr = app.httpServer.Shutdown(ctx)
+	if httpServer != nil {
+		httpErr = httpServer.Shutdown(ctx)
+	}
+
+	if app.snapshotCancel != nil {
+		app.snapshotCancel()
 	}
 
 	var pprofErr error
@@ -204,8 +435,39 @@ func (app *Application) Shutdown(ctx context.Context) error {
 	case <-ctx.Done():
 	}
 
-	if httpErr != nil {
-		return httpErr
+	return errors.Join(httpErr, statsdErr, graphiteErr, pprofErr)
+}
+
+func stopGRPCServer(ctx context.Context, grpcServer *grpc.Server) {
+	stopped := make(chan struct{})
+	go func() {
+		grpcServer.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		grpcServer.Stop()
+	}
+}
+
+// saveMetrics validates metrics received by ingestion listener and saves the valid ones, problems are logged
+// as listeners have no way to report them to clients.
+func saveMetrics(stor storage.Repository, validator *domain.Validator, lgr *logger.Logger, source string, metrics []domain.Metrics) {
+	if len(metrics) == 0 {
+		return
+	}
+
+	valid, result := validator.ValidateBatch(metrics, stor.GetCounterMetric)
+	for _, status := range result.Rejected {
+		lgr.SugaredLogger.Warnln(source, "metric rejected", "id", status.ID, "reason", status.Reason)
+	}
+	if len(valid) == 0 {
+		return
+	}
+
+	if err := stor.UpdateMetrics(valid); err != nil {
+		lgr.SugaredLogger.Errorln(source, "error saving metrics:", err.Error())
 	}
-	return pprofErr
 }
@@ -2,14 +2,27 @@ package application
 
 import (
 	"context"
+	"encoding/json"
+	"io"
+	"net"
 	"net/http"
+	"net/http/httptest"
+	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/frolmr/metrics/internal/server/config"
+	"github.com/frolmr/metrics/internal/server/interceptors"
 	"github.com/frolmr/metrics/internal/server/logger"
+	"github.com/frolmr/metrics/internal/server/storage"
+	pb "github.com/frolmr/metrics/pkg/proto/metrics"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
 )
 
 func TestApplication(t *testing.T) {
@@ -64,4 +77,244 @@ func TestApplication(t *testing.T) {
 			require.NoError(t, err)
 		}
 	})
+	t.Run("http server with ingestion listeners", func(t *testing.T) {
+		cfg := &config.Config{
+			Scheme:              "http",
+			HTTPAddress:         "localhost:0",
+			StatsDAddress:       "localhost:0",
+			StatsDFlushInterval: time.Second,
+			GraphiteAddress:     "localhost:0",
+		}
+		log, logErr := logger.NewLogger()
+		require.NoError(t, logErr)
+		app := NewApplication(cfg, log)
+
+		runErr := make(chan error, 1)
+		go func() {
+			runErr <- app.RunServer()
+		}()
+
+		require.Eventually(t, func() bool {
+			app.mu.Lock()
+			defer app.mu.Unlock()
+			return app.statsdServer != nil && app.graphiteServer != nil
+		}, time.Second, 10*time.Millisecond)
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		require.NoError(t, app.Shutdown(ctx))
+
+		select {
+		case err := <-runErr:
+			require.NoError(t, err)
+		case <-time.After(time.Second):
+			t.Fatal("servers did not stop")
+		}
+	})
+
+	for _, tt := range []struct {
+		name      string
+		sharePort bool
+	}{
+		{name: "http and grpc on separate ports", sharePort: false},
+		{name: "http and grpc on one port", sharePort: true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{
+				Scheme:      "http",
+				HTTPAddress: freeAddress(t),
+			}
+			cfg.GRPCAddress = cfg.HTTPAddress
+			if !tt.sharePort {
+				cfg.GRPCAddress = freeAddress(t)
+			}
+
+			log, logErr := logger.NewLogger()
+			require.NoError(t, logErr)
+			app := NewApplication(cfg, log)
+
+			runErr := make(chan error, 1)
+			go func() {
+				runErr <- app.RunServer()
+			}()
+
+			require.Eventually(t, func() bool {
+				//nolint:noctx // No need for context in tests
+				resp, err := http.Get("http://" + cfg.HTTPAddress + "/ping")
+				if err != nil {
+					return false
+				}
+				resp.Body.Close()
+				return resp.StatusCode == http.StatusOK
+			}, time.Second, 10*time.Millisecond)
+
+			conn, err := grpc.NewClient(cfg.GRPCAddress, grpc.WithTransportCredentials(insecure.NewCredentials()))
+			require.NoError(t, err)
+			defer conn.Close()
+
+			health, err := healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{})
+			require.NoError(t, err)
+			assert.Equal(t, healthpb.HealthCheckResponse_SERVING, health.GetStatus())
+
+			reflectionStream, err := reflectionpb.NewServerReflectionClient(conn).ServerReflectionInfo(context.Background())
+			require.NoError(t, err)
+			require.NoError(t, reflectionStream.Send(&reflectionpb.ServerReflectionRequest{
+				MessageRequest: &reflectionpb.ServerReflectionRequest_ListServices{},
+			}))
+			reflectionResp, err := reflectionStream.Recv()
+			require.NoError(t, err)
+			assert.Contains(t, reflectionResp.GetListServicesResponse().GetService(), &reflectionpb.ServiceResponse{Name: "metrics.Metrics"})
+			require.NoError(t, reflectionStream.CloseSend())
+
+			client := pb.NewMetricsClient(conn)
+			_, err = client.UpdateMetricsBulk(context.Background(), &pb.UpdateMetricsBulkRequest{
+				Metrics: []*pb.Metric{{Key: "shared", Type: pb.Metric_MTYPE_GAUGE, MValue: &pb.Metric_Value{Value: 1.5}}},
+			})
+			require.NoError(t, err)
+			assert.Equal(t, int64(1), app.requestCounter.Count(pb.Metrics_UpdateMetricsBulk_FullMethodName, codes.OK))
+
+			//nolint:noctx // No need for context in tests
+			resp, err := http.Get("http://" + cfg.HTTPAddress + "/value/gauge/shared")
+			require.NoError(t, err)
+			body, err := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			require.NoError(t, err)
+			assert.Equal(t, "1.5", string(body), "storage should be shared between HTTP and gRPC")
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+			require.NoError(t, app.Shutdown(ctx))
+
+			select {
+			case err := <-runErr:
+				require.NoError(t, err)
+			case <-time.After(time.Second):
+				t.Fatal("servers did not stop")
+			}
+		})
+	}
+
+	t.Run("grpc request counts are kept out of storage", func(t *testing.T) {
+		cfg := &config.Config{
+			Scheme:          "grpc",
+			HTTPAddress:     freeAddress(t),
+			FileStoragePath: filepath.Join(t.TempDir(), "metrics.json"),
+			StoreInterval:   time.Hour,
+		}
+		log, logErr := logger.NewLogger()
+		require.NoError(t, logErr)
+		app := NewApplication(cfg, log)
+
+		runErr := make(chan error, 1)
+		go func() {
+			runErr <- app.RunServer()
+		}()
+
+		conn, err := grpc.NewClient(cfg.HTTPAddress, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		require.NoError(t, err)
+		defer conn.Close()
+
+		client := pb.NewMetricsClient(conn)
+		require.Eventually(t, func() bool {
+			_, err := client.UpdateMetricsBulk(context.Background(), &pb.UpdateMetricsBulkRequest{
+				Metrics: []*pb.Metric{{Key: "counted", Type: pb.Metric_MTYPE_COUNTER, MValue: &pb.Metric_Delta{Delta: 1}}},
+			})
+			return err == nil
+		}, time.Second, 10*time.Millisecond)
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		require.NoError(t, app.Shutdown(ctx))
+
+		select {
+		case err := <-runErr:
+			require.NoError(t, err)
+		case <-time.After(time.Second):
+			t.Fatal("servers did not stop")
+		}
+
+		res := httptest.NewRecorder()
+		app.profHandler().ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/debug/grpc/requests", nil))
+		require.Equal(t, http.StatusOK, res.Code)
+		var counts []interceptors.RequestCount
+		require.NoError(t, json.Unmarshal(res.Body.Bytes(), &counts))
+		assert.Contains(t, counts, interceptors.RequestCount{
+			Method: pb.Metrics_UpdateMetricsBulk_FullMethodName, Code: codes.OK.String(), Requests: 1,
+		})
+
+		memstor := storage.NewMemStorage()
+		require.NoError(t, storage.NewFileSnapshot(memstor, cfg.FileStoragePath).RestoreData())
+		assert.Equal(t, map[string]int64{"counted": 1}, memstor.CounterMetrics)
+		assert.Empty(t, memstor.GaugeMetrics, "request counts are not stored as metrics")
+	})
+
+	t.Run("request in flight gets into final snapshot", func(t *testing.T) {
+		cfg := &config.Config{
+			Scheme:          "http",
+			HTTPAddress:     freeAddress(t),
+			FileStoragePath: filepath.Join(t.TempDir(), "metrics.json"),
+			StoreInterval:   time.Hour,
+		}
+		log, logErr := logger.NewLogger()
+		require.NoError(t, logErr)
+		app := NewApplication(cfg, log)
+
+		runErr := make(chan error, 1)
+		go func() {
+			runErr <- app.RunServer()
+		}()
+
+		require.Eventually(t, func() bool {
+			//nolint:noctx // No need for context in tests
+			resp, err := http.Get("http://" + cfg.HTTPAddress + "/ping")
+			if err != nil {
+				return false
+			}
+			resp.Body.Close()
+			return resp.StatusCode == http.StatusOK
+		}, time.Second, 10*time.Millisecond)
+
+		// NOTE: body is sent in two parts, so that handler is still reading it when shutdown starts
+		body, bodyWriter := io.Pipe()
+		updated := make(chan int, 1)
+		go func() {
+			//nolint:noctx // No need for context in tests
+			resp, err := http.Post("http://"+cfg.HTTPAddress+"/update/", "application/json", body)
+			if err != nil {
+				updated <- 0
+				return
+			}
+			resp.Body.Close()
+			updated <- resp.StatusCode
+		}()
+		_, err := io.WriteString(bodyWriter, `{"id":"in_flight",`)
+		require.NoError(t, err)
+		time.Sleep(50 * time.Millisecond)
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		shutdownErr := make(chan error, 1)
+		go func() {
+			shutdownErr <- app.Shutdown(ctx)
+		}()
+		time.Sleep(50 * time.Millisecond)
+
+		_, err = io.WriteString(bodyWriter, `"type":"counter","delta":3}`)
+		require.NoError(t, err)
+		require.NoError(t, bodyWriter.Close())
+		assert.Equal(t, http.StatusOK, <-updated)
+		require.NoError(t, <-shutdownErr)
+		require.NoError(t, <-runErr)
+
+		memstor := storage.NewMemStorage()
+		require.NoError(t, storage.NewFileSnapshot(memstor, cfg.FileStoragePath).RestoreData())
+		assert.Equal(t, map[string]int64{"in_flight": 3}, memstor.CounterMetrics)
+	})
+}
+
+func freeAddress(t *testing.T) string {
+	lis, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	defer lis.Close()
+	return lis.Addr().String()
 }
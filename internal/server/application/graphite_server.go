@@ -0,0 +1,172 @@
+package application
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"sync"
+
+	"github.com/frolmr/metrics/internal/domain"
+	"github.com/frolmr/metrics/internal/server/ingest"
+	"github.com/frolmr/metrics/internal/server/logger"
+	"github.com/frolmr/metrics/internal/server/storage"
+)
+
+const (
+	// maxGraphiteLineSize limits a single line, connection sending longer line is closed.
+	maxGraphiteLineSize = 64 * 1024
+	// graphiteBatchSize is the most metrics saved at once, smaller batches are saved when client pauses.
+	graphiteBatchSize = 1000
+)
+
+// GraphiteServer receives metrics in Graphite plaintext protocol over TCP.
+type GraphiteServer struct {
+	stor      storage.Repository
+	validator *domain.Validator
+	rules     *ingest.Rules
+	logger    *logger.Logger
+
+	listener net.Listener
+	wg       sync.WaitGroup
+
+	mu     sync.Mutex
+	conns  map[net.Conn]struct{}
+	closed bool
+}
+
+// NewGraphiteServer function is constructor for Graphite server accepting on listener, nil validator means default rules.
+func NewGraphiteServer(listener net.Listener, stor storage.Repository, validator *domain.Validator,
+	rules *ingest.Rules, lgr *logger.Logger) *GraphiteServer {
+	if validator == nil {
+		validator = domain.DefaultValidator()
+	}
+
+	return &GraphiteServer{
+		stor:      stor,
+		validator: validator,
+		rules:     rules,
+		logger:    lgr,
+		listener:  listener,
+		conns:     make(map[net.Conn]struct{}),
+	}
+}
+
+// Serve accepts connections until Shutdown.
+func (s *GraphiteServer) Serve() error {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+
+		if !s.track(conn) {
+			conn.Close()
+			return nil
+		}
+
+		s.wg.Add(1)
+		go s.handle(conn)
+	}
+}
+
+// Shutdown stops accepting, closes connections and waits till metrics they sent are saved.
+func (s *GraphiteServer) Shutdown(ctx context.Context) error {
+	s.mu.Lock()
+	s.closed = true
+	for conn := range s.conns {
+		conn.Close()
+	}
+	s.mu.Unlock()
+
+	err := s.listener.Close()
+	if errors.Is(err, net.ErrClosed) {
+		err = nil
+	}
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *GraphiteServer) track(conn net.Conn) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return false
+	}
+	s.conns[conn] = struct{}{}
+	return true
+}
+
+func (s *GraphiteServer) untrack(conn net.Conn) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.conns, conn)
+	conn.Close()
+}
+
+// handle reads lines and saves them in batches, batch is saved when it's full or there is no more data to read.
+func (s *GraphiteServer) handle(conn net.Conn) {
+	defer s.wg.Done()
+	defer s.untrack(conn)
+
+	reader := bufio.NewReaderSize(conn, maxGraphiteLineSize)
+	batch := make([]domain.Metrics, 0, graphiteBatchSize)
+
+	for {
+		line, err := reader.ReadSlice('\n')
+		if errors.Is(err, bufio.ErrBufferFull) {
+			s.logger.SugaredLogger.Warnln("graphite", "line is too long, closing connection from", conn.RemoteAddr())
+			break
+		}
+
+		if m, ok := s.parse(string(line)); ok {
+			batch = append(batch, m)
+		}
+
+		if err != nil {
+			break
+		}
+		if len(batch) >= graphiteBatchSize || (len(batch) != 0 && reader.Buffered() == 0) {
+			saveMetrics(s.stor, s.validator, s.logger, "graphite", batch)
+			batch = make([]domain.Metrics, 0, graphiteBatchSize)
+		}
+	}
+
+	saveMetrics(s.stor, s.validator, s.logger, "graphite", batch)
+}
+
+func (s *GraphiteServer) parse(line string) (domain.Metrics, bool) {
+	if strings.TrimSpace(line) == "" {
+		return domain.Metrics{}, false
+	}
+
+	sample, err := ingest.ParseGraphiteLine(line)
+	if err != nil {
+		s.logger.SugaredLogger.Debugln("graphite", err.Error())
+		return domain.Metrics{}, false
+	}
+
+	m, err := s.rules.GraphiteMetric(sample)
+	if err != nil {
+		s.logger.SugaredLogger.Debugln("graphite", err.Error())
+		return domain.Metrics{}, false
+	}
+	return m, true
+}
@@ -0,0 +1,128 @@
+package application
+
+import (
+	"context"
+	"net"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/frolmr/metrics/internal/domain"
+	"github.com/frolmr/metrics/internal/server/ingest"
+	"github.com/frolmr/metrics/internal/server/logger"
+	"github.com/frolmr/metrics/internal/server/mocks"
+	"github.com/frolmr/metrics/internal/server/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+func startGraphiteServer(t *testing.T, stor storage.Repository, rules *ingest.Rules) (*GraphiteServer, string, <-chan error) {
+	t.Helper()
+
+	listen, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+
+	lgr := &logger.Logger{SugaredLogger: *zap.NewNop().Sugar()}
+	s := NewGraphiteServer(listen, stor, nil, rules, lgr)
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- s.Serve()
+	}()
+
+	return s, listen.Addr().String(), serveErr
+}
+
+func TestGraphiteServer(t *testing.T) {
+	t.Run("saves lines of closed connection", func(t *testing.T) {
+		rules := &ingest.Rules{
+			Rules:             []ingest.Rule{{Match: "requests$", Type: domain.CounterType}},
+			GraphiteTemplates: []ingest.GraphiteTemplate{{Filter: "servers", Template: ".host.measurement*"}},
+		}
+		require.NoError(t, rules.Compile())
+
+		stor := storage.NewMemStorage()
+		stor.CounterMetrics["app.requests"] = 1
+
+		s, addr, serveErr := startGraphiteServer(t, stor, rules)
+
+		conn, err := net.Dial("tcp", addr)
+		require.NoError(t, err)
+		_, err = conn.Write([]byte("servers.web1.cpu.load 0.5 1700000000\nbad line\napp.requests 2 -1\napp.requests 3"))
+		require.NoError(t, err)
+		require.NoError(t, conn.Close())
+
+		// NOTE: connection is closed by client, so server saves everything without waiting for shutdown
+		time.Sleep(50 * time.Millisecond)
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		require.NoError(t, s.Shutdown(ctx))
+		require.NoError(t, <-serveErr)
+
+		load, err := stor.GetGaugeMetric("cpu.load")
+		require.NoError(t, err)
+		assert.InDelta(t, 0.5, load, 1e-9)
+
+		requests, err := stor.GetCounterMetric("app.requests")
+		require.NoError(t, err)
+		assert.Equal(t, int64(6), requests)
+	})
+
+	t.Run("saves batch when client pauses", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// NOTE: mock instead of MemStorage as storage is read here while connection handler writes it
+		saved := make(chan []domain.Metrics, 1)
+		mockRepo := mocks.NewMockRepository(ctrl)
+		mockRepo.EXPECT().UpdateMetrics(gomock.Any()).DoAndReturn(func(metrics []domain.Metrics) error {
+			saved <- metrics
+			return nil
+		})
+
+		s, addr, serveErr := startGraphiteServer(t, mockRepo, nil)
+
+		conn, err := net.Dial("tcp", addr)
+		require.NoError(t, err)
+		defer conn.Close()
+
+		_, err = conn.Write([]byte("a.b 1 1700000000\na.c 2 1700000000\n"))
+		require.NoError(t, err)
+
+		select {
+		case metrics := <-saved:
+			require.Len(t, metrics, 2)
+			assert.Equal(t, "a.b", metrics[0].ID)
+			assert.Equal(t, "a.c", metrics[1].ID)
+		case <-time.After(time.Second):
+			t.Fatal("metrics were not saved while connection is open")
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		require.NoError(t, s.Shutdown(ctx))
+		require.NoError(t, <-serveErr)
+	})
+
+	t.Run("closes connection sending too long line", func(t *testing.T) {
+		s, addr, serveErr := startGraphiteServer(t, storage.NewMemStorage(), nil)
+
+		conn, err := net.Dial("tcp", addr)
+		require.NoError(t, err)
+		defer conn.Close()
+
+		_, _ = conn.Write([]byte(strings.Repeat("a", maxGraphiteLineSize+1)))
+
+		require.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Second)))
+		_, err = conn.Read(make([]byte, 1))
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, os.ErrDeadlineExceeded, "server should close connection")
+
+		require.NoError(t, s.Shutdown(context.Background()))
+		require.NoError(t, <-serveErr)
+	})
+}
@@ -2,41 +2,240 @@ package application
 
 import (
 	"context"
+	"errors"
+	"io"
+	"strings"
 
 	"github.com/frolmr/metrics/internal/domain"
+	"github.com/frolmr/metrics/internal/server/pubsub"
 	"github.com/frolmr/metrics/internal/server/storage"
 	pb "github.com/frolmr/metrics/pkg/proto/metrics"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type MetricsServer struct {
 	pb.UnimplementedMetricsServer
-	stor storage.Repository
+	stor      storage.Repository
+	hub       *pubsub.Hub
+	validator *domain.Validator
 }
 
-func NewMetricsServer(stor storage.Repository) *MetricsServer {
+// NewMetricsServer function is constructor for gRPC metrics service, hub is used for WatchMetrics and may be nil.
+// Metrics are validated with default rules if validator is nil.
+func NewMetricsServer(stor storage.Repository, hub *pubsub.Hub, validator *domain.Validator) *MetricsServer {
+	if validator == nil {
+		validator = domain.DefaultValidator()
+	}
+
 	return &MetricsServer{
-		stor: stor,
+		stor:      stor,
+		hub:       hub,
+		validator: validator,
 	}
 }
 
+// UpdateMetricsBulk saves valid metrics of the request, invalid ones are listed in the ack with reasons.
 func (s *MetricsServer) UpdateMetricsBulk(ctx context.Context, in *pb.UpdateMetricsBulkRequest) (*pb.Ack, error) {
-	var metrics []domain.Metrics
+	received, errMsg, rejected := s.updateMetrics(in.GetMetrics())
+
+	return &pb.Ack{Received: received, Error: errMsg, Rejected: rejected}, nil
+}
+
+// StreamMetrics receives metrics batches over one long-living stream and acks every batch separately.
+func (s *MetricsServer) StreamMetrics(stream grpc.BidiStreamingServer[pb.MetricsBatch, pb.BatchAck]) error {
+	for {
+		batch, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		received, errMsg, rejected := s.updateMetrics(batch.GetMetrics())
+		ack := &pb.BatchAck{Id: batch.GetId(), Received: received, Error: errMsg, Rejected: rejected}
+
+		if err := stream.Send(ack); err != nil {
+			return err
+		}
+	}
+}
+
+// GetMetric returns current value of a single metric.
+func (s *MetricsServer) GetMetric(ctx context.Context, in *pb.GetMetricRequest) (*pb.Metric, error) {
+	switch in.GetType() {
+	case pb.Metric_MTYPE_COUNTER:
+		delta, err := s.stor.GetCounterMetric(in.GetKey())
+		if err != nil {
+			return nil, getMetricError(in.GetKey(), err)
+		}
+		return &pb.Metric{Key: in.GetKey(), Type: pb.Metric_MTYPE_COUNTER, MValue: &pb.Metric_Delta{Delta: delta}}, nil
+	case pb.Metric_MTYPE_GAUGE:
+		value, err := s.stor.GetGaugeMetric(in.GetKey())
+		if err != nil {
+			return nil, getMetricError(in.GetKey(), err)
+		}
+		return &pb.Metric{Key: in.GetKey(), Type: pb.Metric_MTYPE_GAUGE, MValue: &pb.Metric_Value{Value: value}}, nil
+	default:
+		return nil, status.Error(codes.InvalidArgument, "wrong metric type")
+	}
+}
+
+// ListMetrics returns metrics ordered by name and type, filtered by name prefix and type, page by page.
+func (s *MetricsServer) ListMetrics(ctx context.Context, in *pb.ListMetricsRequest) (*pb.ListMetricsResponse, error) {
+	pageSize := int(in.GetPageSize())
+	switch {
+	case pageSize < 0:
+		return nil, status.Error(codes.InvalidArgument, "negative page size")
+	case pageSize == 0:
+		pageSize = domain.DefaultListLimit
+	case pageSize > domain.MaxListLimit:
+		pageSize = domain.MaxListLimit
+	}
 
-	for _, v := range in.GetMetrics() {
-		switch v.GetType() {
-		case pb.Metric_MTYPE_COUNTER:
-			delta := v.GetDelta()
-			metrics = append(metrics, domain.Metrics{ID: v.Key, MType: domain.CounterType, Delta: &delta})
-		case pb.Metric_MTYPE_GAUGE:
-			value := v.GetValue()
-			metrics = append(metrics, domain.Metrics{ID: v.Key, MType: domain.GaugeType, Value: &value})
+	query := domain.MetricsQuery{
+		MType:  toDomainType(in.GetType()),
+		Prefix: in.GetPrefix(),
+		Sort:   domain.SortByName,
+		// NOTE: one extra metric tells whether there is a next page
+		Limit: pageSize + 1,
+	}
+	if in.GetPageToken() != "" {
+		var err error
+		// NOTE: page tokens are cursors of HTTP listing, so paging can be continued over either API
+		if query.After, err = domain.DecodeCursor(in.GetPageToken()); err != nil {
+			return nil, status.Error(codes.InvalidArgument, "invalid page token")
 		}
 	}
 
-	err := s.stor.UpdateMetrics(metrics)
+	metrics, err := s.stor.ListMetrics(query)
 	if err != nil {
-		return nil, err
+		return nil, status.Errorf(codes.Internal, "failed to read metrics: %v", err)
+	}
+
+	resp := &pb.ListMetricsResponse{Metrics: make([]*pb.Metric, 0, min(len(metrics), pageSize))}
+	for _, m := range metrics[:min(len(metrics), pageSize)] {
+		resp.Metrics = append(resp.Metrics, toProtoMetric(m))
+	}
+	if len(metrics) > pageSize {
+		last := metrics[pageSize-1]
+		resp.NextPageToken = domain.EncodeCursor(domain.MetricKey{ID: last.ID, MType: last.MType})
+	}
+
+	return resp, nil
+}
+
+// getMetricError tells missing metric from storage failure.
+func getMetricError(name string, err error) error {
+	if errors.Is(err, storage.ErrMetricNotFound) {
+		return status.Errorf(codes.NotFound, "metric %s not found", name)
+	}
+	return status.Errorf(codes.Internal, "failed to read metric %s: %v", name, err)
+}
+
+// WatchMetrics streams accepted updates matching name prefix and type until client goes away.
+func (s *MetricsServer) WatchMetrics(in *pb.WatchMetricsRequest, stream grpc.ServerStreamingServer[pb.Metric]) error {
+	if s.hub == nil {
+		return status.Error(codes.Unavailable, "metrics watch is not enabled")
+	}
+
+	mType := toDomainType(in.GetType())
+	sub := s.hub.Subscribe(func(m domain.Metrics) bool {
+		return strings.HasPrefix(m.ID, in.GetPrefix()) && (mType == "" || m.MType == mType)
+	})
+	defer sub.Close()
+
+	for {
+		select {
+		case m, ok := <-sub.Updates():
+			if !ok {
+				return nil
+			}
+			if err := stream.Send(toProtoMetric(m)); err != nil {
+				return err
+			}
+		case <-stream.Context().Done():
+			return nil
+		}
+	}
+}
+
+// updateMetrics validates every metric and saves valid ones with the same semantics as HTTP bulk update:
+// batch is received if at least one metric is accepted, rejected metrics are reported with reasons.
+func (s *MetricsServer) updateMetrics(in []*pb.Metric) (bool, *string, []*pb.MetricError) {
+	valid, result := s.validator.ValidateBatch(toDomainMetrics(in), s.stor.GetCounterMetric)
+
+	var errMsg *string
+	if len(valid) != 0 {
+		if err := s.stor.UpdateMetrics(valid); err != nil {
+			result.RejectAll(err.Error())
+			msg := err.Error()
+			errMsg = &msg
+		}
+	}
+
+	received := len(result.Accepted) != 0 || len(in) == 0
+	if !received && errMsg == nil {
+		msg := "all metrics rejected"
+		errMsg = &msg
+	}
+
+	rejected := make([]*pb.MetricError, 0, len(result.Rejected))
+	for _, r := range result.Rejected {
+		rejected = append(rejected, &pb.MetricError{Index: uint32(r.Index), Key: r.ID, Error: r.Reason}) //nolint:gosec // index is bounded by request size
+	}
+
+	return received, errMsg, rejected
+}
+
+func toDomainType(mType pb.Metric_MType) string {
+	switch mType {
+	case pb.Metric_MTYPE_COUNTER:
+		return domain.CounterType
+	case pb.Metric_MTYPE_GAUGE:
+		return domain.GaugeType
+	default:
+		return ""
+	}
+}
+
+func toProtoMetric(m domain.Metrics) *pb.Metric {
+	if m.MType == domain.CounterType && m.Delta != nil {
+		return &pb.Metric{Key: m.ID, Type: pb.Metric_MTYPE_COUNTER, MValue: &pb.Metric_Delta{Delta: *m.Delta}}
+	}
+	if m.MType == domain.GaugeType && m.Value != nil {
+		return &pb.Metric{Key: m.ID, Type: pb.Metric_MTYPE_GAUGE, MValue: &pb.Metric_Value{Value: *m.Value}}
+	}
+	return &pb.Metric{Key: m.ID}
+}
+
+// toDomainMetrics converts every metric keeping request order, value is set only if it matches metric type.
+func toDomainMetrics(in []*pb.Metric) []domain.Metrics {
+	metrics := make([]domain.Metrics, 0, len(in))
+
+	for _, v := range in {
+		m := domain.Metrics{ID: v.GetKey(), MType: toDomainType(v.GetType())}
+		if m.MType == "" {
+			m.MType = v.GetType().String()
+		}
+
+		switch mValue := v.GetMValue().(type) {
+		case *pb.Metric_Delta:
+			if m.MType == domain.CounterType {
+				delta := mValue.Delta
+				m.Delta = &delta
+			}
+		case *pb.Metric_Value:
+			if m.MType == domain.GaugeType {
+				value := mValue.Value
+				m.Value = &value
+			}
+		}
+
+		metrics = append(metrics, m)
 	}
 
-	return &pb.Ack{Received: true}, nil
+	return metrics
 }
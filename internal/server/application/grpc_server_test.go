@@ -2,11 +2,29 @@ package application
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
+	"github.com/frolmr/metrics/internal/domain"
+	"github.com/frolmr/metrics/internal/server/handlers"
+	"github.com/frolmr/metrics/internal/server/mocks"
+	"github.com/frolmr/metrics/internal/server/pubsub"
 	"github.com/frolmr/metrics/internal/server/storage"
 	pb "github.com/frolmr/metrics/pkg/proto/metrics"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
 )
 
 func TestMetricsServer(t *testing.T) {
@@ -15,7 +33,7 @@ func TestMetricsServer(t *testing.T) {
 		CounterMetrics: make(map[string]int64),
 	}
 
-	server := NewMetricsServer(mockStorage)
+	server := NewMetricsServer(mockStorage, nil, nil)
 
 	t.Run("valid gauge metric", func(t *testing.T) {
 		req := &pb.UpdateMetricsBulkRequest{
@@ -85,4 +103,281 @@ func TestMetricsServer(t *testing.T) {
 		require.True(t, exists)
 		require.Equal(t, int64(42), counterVal)
 	})
+
+	t.Run("partially invalid metrics", func(t *testing.T) {
+		req := &pb.UpdateMetricsBulkRequest{
+			Metrics: []*pb.Metric{
+				{Key: "partial", Type: pb.Metric_MTYPE_GAUGE, MValue: &pb.Metric_Value{Value: 2.5}},
+				{Key: "no_value", Type: pb.Metric_MTYPE_COUNTER},
+				{Key: "mismatch", Type: pb.Metric_MTYPE_GAUGE, MValue: &pb.Metric_Delta{Delta: 1}},
+				{Key: "no_type", MValue: &pb.Metric_Delta{Delta: 1}},
+			},
+		}
+
+		resp, err := server.UpdateMetricsBulk(context.Background(), req)
+		require.NoError(t, err)
+		require.True(t, resp.GetReceived())
+		require.Nil(t, resp.Error)
+		require.Len(t, resp.GetRejected(), 3)
+		require.Equal(t, uint32(1), resp.GetRejected()[0].GetIndex())
+		require.Equal(t, "no_value", resp.GetRejected()[0].GetKey())
+		require.Equal(t, "counter metric without delta", resp.GetRejected()[0].GetError())
+		require.Equal(t, "gauge metric without value", resp.GetRejected()[1].GetError())
+		require.Equal(t, "unknown metric type", resp.GetRejected()[2].GetError())
+
+		require.Equal(t, 2.5, mockStorage.GaugeMetrics["partial"])
+	})
+
+	t.Run("name and value rules", func(t *testing.T) {
+		req := &pb.UpdateMetricsBulkRequest{
+			Metrics: []*pb.Metric{
+				{Key: "bad name", Type: pb.Metric_MTYPE_GAUGE, MValue: &pb.Metric_Value{Value: 1}},
+				{Key: "nan", Type: pb.Metric_MTYPE_GAUGE, MValue: &pb.Metric_Value{Value: math.NaN()}},
+				{Key: "count", Type: pb.Metric_MTYPE_COUNTER, MValue: &pb.Metric_Delta{Delta: math.MaxInt64}},
+			},
+		}
+
+		resp, err := server.UpdateMetricsBulk(context.Background(), req)
+		require.NoError(t, err)
+		require.False(t, resp.GetReceived())
+		require.Len(t, resp.GetRejected(), 3)
+		require.Equal(t, domain.ErrInvalidName.Error(), resp.GetRejected()[0].GetError())
+		require.Equal(t, domain.ErrNonFiniteValue.Error(), resp.GetRejected()[1].GetError())
+		require.Equal(t, domain.ErrCounterOverflow.Error(), resp.GetRejected()[2].GetError())
+	})
+
+	t.Run("all metrics invalid", func(t *testing.T) {
+		req := &pb.UpdateMetricsBulkRequest{
+			Metrics: []*pb.Metric{{Type: pb.Metric_MTYPE_GAUGE, MValue: &pb.Metric_Value{Value: 1}}},
+		}
+
+		resp, err := server.UpdateMetricsBulk(context.Background(), req)
+		require.NoError(t, err)
+		require.False(t, resp.GetReceived())
+		require.Equal(t, "all metrics rejected", resp.GetError())
+		require.Equal(t, "empty metric name", resp.GetRejected()[0].GetError())
+	})
+}
+
+func newBufconnClient(t *testing.T, srv pb.MetricsServer) pb.MetricsClient {
+	lis := bufconn.Listen(1024 * 1024)
+	s := grpc.NewServer()
+	pb.RegisterMetricsServer(s, srv)
+	go func() {
+		_ = s.Serve(lis)
+	}()
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return pb.NewMetricsClient(conn)
+}
+
+func TestMetricsServerStream(t *testing.T) {
+	mockStorage := storage.NewMemStorage()
+	client := newBufconnClient(t, NewMetricsServer(mockStorage, nil, nil))
+
+	stream, err := client.StreamMetrics(context.Background())
+	require.NoError(t, err)
+
+	for i := uint64(1); i <= 3; i++ {
+		err := stream.Send(&pb.MetricsBatch{
+			Id: i,
+			Metrics: []*pb.Metric{
+				{
+					Key:    "count",
+					Type:   pb.Metric_MTYPE_COUNTER,
+					MValue: &pb.Metric_Delta{Delta: 1},
+				},
+			},
+		})
+		require.NoError(t, err)
+
+		ack, err := stream.Recv()
+		require.NoError(t, err)
+		require.Equal(t, i, ack.GetId())
+		require.True(t, ack.GetReceived())
+	}
+
+	err = stream.Send(&pb.MetricsBatch{
+		Id:      4,
+		Metrics: []*pb.Metric{{Key: "count", Type: pb.Metric_MTYPE_COUNTER}},
+	})
+	require.NoError(t, err)
+
+	ack, err := stream.Recv()
+	require.NoError(t, err)
+	require.False(t, ack.GetReceived())
+	require.Len(t, ack.GetRejected(), 1)
+	require.Equal(t, "counter metric without delta", ack.GetRejected()[0].GetError())
+
+	require.NoError(t, stream.CloseSend())
+	_, err = stream.Recv()
+	require.ErrorIs(t, err, io.EOF)
+
+	require.Equal(t, int64(3), mockStorage.CounterMetrics["count"])
+}
+
+func TestMetricsServerGetMetric(t *testing.T) {
+	mockStorage := storage.NewMemStorage()
+	mockStorage.CounterMetrics["count"] = 42
+	mockStorage.GaugeMetrics["gauge"] = 1.23
+
+	server := NewMetricsServer(mockStorage, nil, nil)
+
+	resp, err := server.GetMetric(context.Background(), &pb.GetMetricRequest{Key: "count", Type: pb.Metric_MTYPE_COUNTER})
+	require.NoError(t, err)
+	require.Equal(t, int64(42), resp.GetDelta())
+
+	resp, err = server.GetMetric(context.Background(), &pb.GetMetricRequest{Key: "gauge", Type: pb.Metric_MTYPE_GAUGE})
+	require.NoError(t, err)
+	require.Equal(t, 1.23, resp.GetValue())
+
+	_, err = server.GetMetric(context.Background(), &pb.GetMetricRequest{Key: "missing", Type: pb.Metric_MTYPE_GAUGE})
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = server.GetMetric(context.Background(), &pb.GetMetricRequest{Key: "count"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	t.Run("storage failure", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := mocks.NewMockRepository(ctrl)
+		mockRepo.EXPECT().GetCounterMetric("count").Return(int64(0), errors.New("db is down"))
+
+		_, err := NewMetricsServer(mockRepo, nil, nil).GetMetric(context.Background(),
+			&pb.GetMetricRequest{Key: "count", Type: pb.Metric_MTYPE_COUNTER})
+		require.Equal(t, codes.Internal, status.Code(err), "outage is not a missing metric")
+	})
+}
+
+func TestMetricsServerListMetrics(t *testing.T) {
+	mockStorage := storage.NewMemStorage()
+	mockStorage.CounterMetrics["cpu_count"] = 1
+	mockStorage.GaugeMetrics["cpu_load"] = 0.5
+	mockStorage.GaugeMetrics["cpu_temp"] = 40
+	mockStorage.GaugeMetrics["mem_free"] = 100
+
+	server := NewMetricsServer(mockStorage, nil, nil)
+
+	t.Run("pagination", func(t *testing.T) {
+		var keys []string
+		token := ""
+		for {
+			resp, err := server.ListMetrics(context.Background(), &pb.ListMetricsRequest{PageSize: 3, PageToken: token})
+			require.NoError(t, err)
+			for _, m := range resp.GetMetrics() {
+				keys = append(keys, m.GetKey())
+			}
+			if resp.GetNextPageToken() == "" {
+				break
+			}
+			token = resp.GetNextPageToken()
+		}
+		require.Equal(t, []string{"cpu_count", "cpu_load", "cpu_temp", "mem_free"}, keys)
+	})
+
+	t.Run("filters", func(t *testing.T) {
+		resp, err := server.ListMetrics(context.Background(), &pb.ListMetricsRequest{Prefix: "cpu", Type: pb.Metric_MTYPE_GAUGE})
+		require.NoError(t, err)
+		require.Len(t, resp.GetMetrics(), 2)
+		require.Equal(t, "cpu_load", resp.GetMetrics()[0].GetKey())
+		require.Empty(t, resp.GetNextPageToken())
+	})
+
+	t.Run("page is read from storage", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		value := 0.5
+		mockRepo := mocks.NewMockRepository(ctrl)
+		mockRepo.EXPECT().ListMetrics(domain.MetricsQuery{
+			MType:  domain.GaugeType,
+			Prefix: "cpu",
+			Sort:   domain.SortByName,
+			After:  &domain.MetricKey{ID: "cpu_count", MType: domain.CounterType},
+			Limit:  2,
+		}).Return([]domain.Metrics{
+			{ID: "cpu_load", MType: domain.GaugeType, Value: &value},
+			{ID: "cpu_temp", MType: domain.GaugeType, Value: &value},
+		}, nil)
+
+		resp, err := NewMetricsServer(mockRepo, nil, nil).ListMetrics(context.Background(), &pb.ListMetricsRequest{
+			Prefix:    "cpu",
+			Type:      pb.Metric_MTYPE_GAUGE,
+			PageSize:  1,
+			PageToken: domain.EncodeCursor(domain.MetricKey{ID: "cpu_count", MType: domain.CounterType}),
+		})
+		require.NoError(t, err)
+		require.Len(t, resp.GetMetrics(), 1)
+		require.Equal(t, "cpu_load", resp.GetMetrics()[0].GetKey())
+		require.NotEmpty(t, resp.GetNextPageToken())
+	})
+
+	t.Run("cursor of HTTP listing", func(t *testing.T) {
+		res := httptest.NewRecorder()
+		handlers.NewRequestHandler(mockStorage, nil).ListMetrics()(res, httptest.NewRequest(http.MethodGet, "/api/v1/metrics?limit=2", nil))
+		require.Equal(t, http.StatusOK, res.Code)
+
+		var page domain.MetricsPage
+		require.NoError(t, json.Unmarshal(res.Body.Bytes(), &page))
+		require.NotEmpty(t, page.NextCursor)
+
+		resp, err := server.ListMetrics(context.Background(), &pb.ListMetricsRequest{PageToken: page.NextCursor})
+		require.NoError(t, err)
+		require.Len(t, resp.GetMetrics(), 2)
+		require.Equal(t, "cpu_temp", resp.GetMetrics()[0].GetKey())
+	})
+
+	t.Run("invalid arguments", func(t *testing.T) {
+		_, err := server.ListMetrics(context.Background(), &pb.ListMetricsRequest{PageToken: "%%%"})
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+		_, err = server.ListMetrics(context.Background(), &pb.ListMetricsRequest{PageSize: -1})
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func TestMetricsServerWatchMetrics(t *testing.T) {
+	hub := pubsub.NewHub(10)
+	stor := storage.NewPublishingStorage(storage.NewMemStorage(), hub)
+	client := newBufconnClient(t, NewMetricsServer(stor, hub, nil))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream, err := client.WatchMetrics(ctx, &pb.WatchMetricsRequest{Prefix: "cpu", Type: pb.Metric_MTYPE_GAUGE})
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool { return hub.Subscribers() == 1 }, time.Second, 10*time.Millisecond)
+
+	require.NoError(t, stor.UpdateGaugeMetric("mem_free", 1))
+	require.NoError(t, stor.UpdateCounterMetric("cpu_count", 1))
+	require.NoError(t, stor.UpdateGaugeMetric("cpu_load", 0.7))
+
+	m, err := stream.Recv()
+	require.NoError(t, err)
+	require.Equal(t, "cpu_load", m.GetKey())
+	require.Equal(t, 0.7, m.GetValue())
+
+	cancel()
+	require.Eventually(t, func() bool { return hub.Subscribers() == 0 }, time.Second, 10*time.Millisecond)
+}
+
+func TestMetricsServerWatchMetricsDisabled(t *testing.T) {
+	client := newBufconnClient(t, NewMetricsServer(storage.NewMemStorage(), nil, nil))
+
+	stream, err := client.WatchMetrics(context.Background(), &pb.WatchMetricsRequest{})
+	require.NoError(t, err)
+
+	_, err = stream.Recv()
+	require.Equal(t, codes.Unavailable, status.Code(err))
 }
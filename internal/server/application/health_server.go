@@ -0,0 +1,108 @@
+package application
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/frolmr/metrics/internal/server/storage"
+	pb "github.com/frolmr/metrics/pkg/proto/metrics"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	healthWatchInterval = 5 * time.Second
+)
+
+// HealthServer implements grpc.health.v1 on top of storage Ping, like HTTP /ping does.
+type HealthServer struct {
+	healthpb.UnimplementedHealthServer
+	stor          storage.Repository
+	watchInterval time.Duration
+	shutdown      chan struct{}
+	shutdownOnce  sync.Once
+}
+
+// NewHealthServer function is constructor for gRPC health service.
+func NewHealthServer(stor storage.Repository) *HealthServer {
+	return &HealthServer{
+		stor:          stor,
+		watchInterval: healthWatchInterval,
+		shutdown:      make(chan struct{}),
+	}
+}
+
+// Shutdown switches all services to NOT_SERVING and ends active watches.
+func (h *HealthServer) Shutdown() {
+	h.shutdownOnce.Do(func() {
+		close(h.shutdown)
+	})
+}
+
+// Check reports SERVING for the whole server ("") and metrics service while storage is reachable.
+func (h *HealthServer) Check(ctx context.Context, in *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	if !isKnownService(in.GetService()) {
+		return nil, status.Errorf(codes.NotFound, "unknown service %s", in.GetService())
+	}
+	return &healthpb.HealthCheckResponse{Status: h.servingStatus()}, nil
+}
+
+// List reports statuses of all known services.
+func (h *HealthServer) List(ctx context.Context, in *healthpb.HealthListRequest) (*healthpb.HealthListResponse, error) {
+	servingStatus := h.servingStatus()
+	return &healthpb.HealthListResponse{
+		Statuses: map[string]*healthpb.HealthCheckResponse{
+			"":                                 {Status: servingStatus},
+			pb.Metrics_ServiceDesc.ServiceName: {Status: servingStatus},
+		},
+	}, nil
+}
+
+// Watch sends current status and then every status change until client goes away.
+func (h *HealthServer) Watch(in *healthpb.HealthCheckRequest, stream grpc.ServerStreamingServer[healthpb.HealthCheckResponse]) error {
+	ticker := time.NewTicker(h.watchInterval)
+	defer ticker.Stop()
+
+	lastStatus := healthpb.HealthCheckResponse_UNKNOWN
+	for {
+		currentStatus := healthpb.HealthCheckResponse_SERVICE_UNKNOWN
+		if isKnownService(in.GetService()) {
+			currentStatus = h.servingStatus()
+		}
+
+		if currentStatus != lastStatus {
+			if err := stream.Send(&healthpb.HealthCheckResponse{Status: currentStatus}); err != nil {
+				return err
+			}
+			lastStatus = currentStatus
+		}
+
+		select {
+		case <-ticker.C:
+		case <-h.shutdown:
+			return stream.Send(&healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_NOT_SERVING})
+		case <-stream.Context().Done():
+			return nil
+		}
+	}
+}
+
+func (h *HealthServer) servingStatus() healthpb.HealthCheckResponse_ServingStatus {
+	select {
+	case <-h.shutdown:
+		return healthpb.HealthCheckResponse_NOT_SERVING
+	default:
+	}
+
+	if err := h.stor.Ping(); err != nil {
+		return healthpb.HealthCheckResponse_NOT_SERVING
+	}
+	return healthpb.HealthCheckResponse_SERVING
+}
+
+func isKnownService(service string) bool {
+	return service == "" || service == pb.Metrics_ServiceDesc.ServiceName
+}
@@ -0,0 +1,103 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/frolmr/metrics/internal/server/mocks"
+	"github.com/frolmr/metrics/internal/server/storage"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestHealthServerCheck(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockRepository(ctrl)
+	hs := NewHealthServer(repo)
+
+	t.Run("serving", func(t *testing.T) {
+		repo.EXPECT().Ping().Return(nil)
+
+		resp, err := hs.Check(context.Background(), &healthpb.HealthCheckRequest{Service: "metrics.Metrics"})
+		require.NoError(t, err)
+		require.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.GetStatus())
+	})
+
+	t.Run("storage unavailable", func(t *testing.T) {
+		repo.EXPECT().Ping().Return(errors.New("db down"))
+
+		resp, err := hs.Check(context.Background(), &healthpb.HealthCheckRequest{})
+		require.NoError(t, err)
+		require.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, resp.GetStatus())
+	})
+
+	t.Run("unknown service", func(t *testing.T) {
+		_, err := hs.Check(context.Background(), &healthpb.HealthCheckRequest{Service: "other.Service"})
+		require.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("list", func(t *testing.T) {
+		repo.EXPECT().Ping().Return(nil)
+
+		resp, err := hs.List(context.Background(), &healthpb.HealthListRequest{})
+		require.NoError(t, err)
+		require.Len(t, resp.GetStatuses(), 2)
+		require.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.GetStatuses()["metrics.Metrics"].GetStatus())
+	})
+
+	t.Run("not serving after shutdown", func(t *testing.T) {
+		hs.Shutdown()
+
+		resp, err := hs.Check(context.Background(), &healthpb.HealthCheckRequest{})
+		require.NoError(t, err)
+		require.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, resp.GetStatus())
+	})
+}
+
+func TestHealthServerWatch(t *testing.T) {
+	hs := NewHealthServer(storage.NewMemStorage())
+
+	lis := bufconn.Listen(1024 * 1024)
+	s := grpc.NewServer()
+	healthpb.RegisterHealthServer(s, hs)
+	go func() {
+		_ = s.Serve(lis)
+	}()
+	defer s.Stop()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	stream, err := healthpb.NewHealthClient(conn).Watch(context.Background(), &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+
+	resp, err := stream.Recv()
+	require.NoError(t, err)
+	require.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.GetStatus())
+
+	hs.Shutdown()
+
+	resp, err = stream.Recv()
+	require.NoError(t, err)
+	require.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, resp.GetStatus())
+
+	_, err = stream.Recv()
+	require.ErrorIs(t, err, io.EOF)
+}
@@ -0,0 +1,141 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/frolmr/metrics/internal/domain"
+	"github.com/frolmr/metrics/internal/server/ingest"
+	"github.com/frolmr/metrics/internal/server/logger"
+	"github.com/frolmr/metrics/internal/server/storage"
+)
+
+// maxStatsDPacketSize is the largest UDP payload.
+const maxStatsDPacketSize = 64 * 1024
+
+// StatsDServer receives StatsD metrics over UDP and saves aggregates every flush interval.
+type StatsDServer struct {
+	stor       storage.Repository
+	validator  *domain.Validator
+	aggregator *ingest.StatsDAggregator
+	interval   time.Duration
+	logger     *logger.Logger
+
+	conn net.PacketConn
+	done chan struct{}
+
+	mu      sync.Mutex
+	started bool
+	closed  bool
+}
+
+// NewStatsDServer function is constructor for StatsD server reading conn, nil validator means default rules.
+func NewStatsDServer(conn net.PacketConn, stor storage.Repository, validator *domain.Validator, rules *ingest.Rules,
+	interval time.Duration, lgr *logger.Logger) *StatsDServer {
+	if validator == nil {
+		validator = domain.DefaultValidator()
+	}
+
+	return &StatsDServer{
+		stor:       stor,
+		validator:  validator,
+		aggregator: ingest.NewStatsDAggregator(rules),
+		interval:   interval,
+		logger:     lgr,
+		conn:       conn,
+		done:       make(chan struct{}),
+	}
+}
+
+// Serve reads packets until Shutdown, metrics left at that moment are flushed before return.
+func (s *StatsDServer) Serve() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.started = true
+	s.mu.Unlock()
+
+	defer close(s.done)
+
+	stop := make(chan struct{})
+	flushed := make(chan struct{})
+	go s.runFlusher(stop, flushed)
+
+	err := s.receive()
+
+	close(stop)
+	<-flushed
+
+	if errors.Is(err, net.ErrClosed) {
+		return nil
+	}
+	return err
+}
+
+// Shutdown stops receiving and waits for the final flush if Serve was started.
+func (s *StatsDServer) Shutdown(ctx context.Context) error {
+	s.mu.Lock()
+	closed, started := s.closed, s.started
+	s.closed = true
+	s.mu.Unlock()
+
+	if !closed {
+		if err := s.conn.Close(); err != nil {
+			return err
+		}
+	}
+	if !started {
+		return nil
+	}
+
+	select {
+	case <-s.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *StatsDServer) receive() error {
+	buf := make([]byte, maxStatsDPacketSize)
+	for {
+		n, _, err := s.conn.ReadFrom(buf)
+		if err != nil {
+			return err
+		}
+
+		samples, errs := ingest.ParseStatsD(string(buf[:n]))
+		for _, err := range errs {
+			s.logger.SugaredLogger.Debugln("statsd", err.Error())
+		}
+		for _, sample := range samples {
+			s.aggregator.Add(sample)
+		}
+	}
+}
+
+func (s *StatsDServer) runFlusher(stop <-chan struct{}, flushed chan<- struct{}) {
+	defer close(flushed)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.flush()
+		case <-stop:
+			s.flush()
+			return
+		}
+	}
+}
+
+func (s *StatsDServer) flush() {
+	saveMetrics(s.stor, s.validator, s.logger, "statsd", s.aggregator.Flush())
+}
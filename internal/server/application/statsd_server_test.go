@@ -0,0 +1,115 @@
+package application
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/frolmr/metrics/internal/domain"
+	"github.com/frolmr/metrics/internal/server/logger"
+	"github.com/frolmr/metrics/internal/server/mocks"
+	"github.com/frolmr/metrics/internal/server/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+func startStatsDServer(t *testing.T, stor storage.Repository, interval time.Duration) (*StatsDServer, net.Conn, <-chan error) {
+	t.Helper()
+
+	conn, err := net.ListenPacket("udp", "localhost:0")
+	require.NoError(t, err)
+
+	lgr := &logger.Logger{SugaredLogger: *zap.NewNop().Sugar()}
+	s := NewStatsDServer(conn, stor, nil, nil, interval, lgr)
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- s.Serve()
+	}()
+
+	client, err := net.Dial("udp", conn.LocalAddr().String())
+	require.NoError(t, err)
+	t.Cleanup(func() { client.Close() })
+
+	return s, client, serveErr
+}
+
+func TestStatsDServer(t *testing.T) {
+	t.Run("flush by interval", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// NOTE: mock instead of MemStorage as storage is read here while flusher writes it
+		flushed := make(chan []domain.Metrics, 1)
+		mockRepo := mocks.NewMockRepository(ctrl)
+		mockRepo.EXPECT().GetCounterMetric("requests").Return(int64(10), nil)
+		mockRepo.EXPECT().UpdateMetrics(gomock.Any()).DoAndReturn(func(metrics []domain.Metrics) error {
+			flushed <- metrics
+			return nil
+		})
+
+		s, client, serveErr := startStatsDServer(t, mockRepo, 20*time.Millisecond)
+
+		_, err := client.Write([]byte("requests:2|c\nload:0.5|g\nbad line\nrequests:1|c|@0.5"))
+		require.NoError(t, err)
+
+		select {
+		case metrics := <-flushed:
+			require.Len(t, metrics, 2)
+			assert.Equal(t, "load", metrics[0].ID)
+			assert.InDelta(t, 0.5, *metrics[0].Value, 1e-9)
+			assert.Equal(t, "requests", metrics[1].ID)
+			assert.Equal(t, int64(4), *metrics[1].Delta)
+		case <-time.After(time.Second):
+			t.Fatal("metrics were not flushed")
+		}
+
+		require.NoError(t, s.Shutdown(context.Background()))
+		require.NoError(t, <-serveErr)
+	})
+
+	t.Run("flush on shutdown", func(t *testing.T) {
+		stor := storage.NewMemStorage()
+
+		s, client, serveErr := startStatsDServer(t, stor, time.Hour)
+
+		_, err := client.Write([]byte("latency:10|ms\nlatency:30|ms\nbad name:1|g"))
+		require.NoError(t, err)
+
+		// NOTE: UDP gives no delivery signal, so wait for the packet to be read before shutdown
+		time.Sleep(50 * time.Millisecond)
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		require.NoError(t, s.Shutdown(ctx))
+		require.NoError(t, <-serveErr)
+
+		count, err := stor.GetCounterMetric("latency_count")
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), count)
+
+		mean, err := stor.GetGaugeMetric("latency_mean")
+		require.NoError(t, err)
+		assert.InDelta(t, 20.0, mean, 1e-9)
+
+		_, err = stor.GetGaugeMetric("bad name")
+		assert.Error(t, err, "invalid metric name should be rejected by validator")
+	})
+
+	t.Run("shutdown without serve", func(t *testing.T) {
+		conn, err := net.ListenPacket("udp", "localhost:0")
+		require.NoError(t, err)
+
+		lgr := &logger.Logger{SugaredLogger: *zap.NewNop().Sugar()}
+		s := NewStatsDServer(conn, storage.NewMemStorage(), nil, nil, time.Hour, lgr)
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		require.NoError(t, s.Shutdown(ctx), "nothing to wait for")
+		require.NoError(t, s.Shutdown(ctx), "shutdown is idempotent")
+		require.NoError(t, s.Serve(), "serve after shutdown returns at once")
+	})
+}
@@ -12,6 +12,8 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/frolmr/metrics/internal/domain"
+	"github.com/frolmr/metrics/internal/server/ingest"
 	"github.com/frolmr/metrics/pkg/fileconfig"
 	"github.com/frolmr/metrics/pkg/formatter"
 )
@@ -19,23 +21,39 @@ import (
 const (
 	maxParamCount = 4
 
-	schemeEnvName        = "SCHEME"
-	addressEnvName       = "ADDRESS"
-	storeIntervalEnv     = "STORE_INTERVAL"
-	fileStoragePathEnv   = "FILE_STORAGE_PATH"
-	restoreEnv           = "RESTORE"
-	databaseDsnEnv       = "DATABASE_DSN"
-	keyEnv               = "KEY"
-	cryptoKeyEnvName     = "CRYPTO_KEY"
-	trustedSubnetEnvName = "TRUSTED_SUBNET"
+	schemeEnvName              = "SCHEME"
+	addressEnvName             = "ADDRESS"
+	storeIntervalEnv           = "STORE_INTERVAL"
+	fileStoragePathEnv         = "FILE_STORAGE_PATH"
+	restoreEnv                 = "RESTORE"
+	databaseDsnEnv             = "DATABASE_DSN"
+	keyEnv                     = "KEY"
+	cryptoKeyEnvName           = "CRYPTO_KEY"
+	trustedSubnetEnvName       = "TRUSTED_SUBNET"
+	grpcAddressEnvName         = "GRPC_ADDRESS"
+	metricNamePatternEnvName   = "METRIC_NAME_PATTERN"
+	metricNameMaxLengthEnvName = "METRIC_NAME_MAX_LENGTH"
+	gaugeNonFiniteEnvName      = "GAUGE_NON_FINITE"
+	counterOverflowEnvName     = "COUNTER_OVERFLOW"
+	ingestRulesPathEnvName     = "INGEST_RULES"
+	statsdAddressEnvName       = "STATSD_ADDRESS"
+	statsdFlushIntervalEnvName = "STATSD_FLUSH_INTERVAL"
+	graphiteAddressEnvName     = "GRAPHITE_ADDRESS"
+	unsignedIngestEnvName      = "UNSIGNED_INGEST"
 )
 
 const (
-	defaultScheme          = "http"
-	defaultAddress         = "localhost:8080"
-	defaultStoreInterval   = 300
-	defaultFileStoragePath = "data_snapshot"
-	defaultRestore         = false
+	defaultScheme              = "http"
+	defaultAddress             = "localhost:8080"
+	defaultStoreInterval       = 300
+	defaultFileStoragePath     = "data_snapshot"
+	defaultRestore             = false
+	defaultMetricNamePattern   = domain.DefaultNamePattern
+	defaultMetricNameMaxLength = domain.DefaultMaxNameLength
+	defaultGaugeNonFinite      = string(domain.NonFiniteReject)
+	defaultCounterOverflow     = string(domain.OverflowReject)
+	defaultStatsdFlushInterval = 10
+	defaultUnsignedIngest      = false
 )
 
 // Config structure to store server configuration.
@@ -53,6 +71,24 @@ type Config struct {
 	Profiling bool
 
 	TrustedSubnet *net.IPNet
+
+	GRPCAddress string
+
+	Validator *domain.Validator
+
+	// IngestRules type and name metrics of line protocol, StatsD and Graphite.
+	IngestRules *ingest.Rules
+
+	// StatsDAddress is UDP address of StatsD listener, listener is disabled if empty.
+	StatsDAddress       string
+	StatsDFlushInterval time.Duration
+
+	// GraphiteAddress is TCP address of Graphite plaintext listener, listener is disabled if empty.
+	GraphiteAddress string
+
+	// UnsignedIngest accepts line protocol, Prometheus remote write and OTLP while Key or CryptoKey is set,
+	// these senders can neither sign nor encrypt bodies. Without keys the routes are always open.
+	UnsignedIngest bool
 }
 
 // NewConfig setups server config: read flags and env variables.
@@ -73,18 +109,45 @@ func NewConfig() (*Config, error) {
 
 	trustedSubnets := make([]string, 0, maxParamCount)
 
+	grpcAddressValues := make([]string, 0, maxParamCount)
+
+	metricNamePatternValues := make([]string, 0, maxParamCount)
+	metricNameMaxLengthValues := make([]int, 0, maxParamCount)
+	gaugeNonFiniteValues := make([]string, 0, maxParamCount)
+	counterOverflowValues := make([]string, 0, maxParamCount)
+
+	ingestRulesPathValues := make([]string, 0, maxParamCount)
+
+	statsdAddressValues := make([]string, 0, maxParamCount)
+
+	statsdFlushIntervalValues := make([]int, 0, maxParamCount)
+
+	graphiteAddressValues := make([]string, 0, maxParamCount)
+
+	unsignedIngestValues := make([]bool, 0, maxParamCount)
+
 	var (
-		serverScheme      string
-		serverHTTPAddress string
-		databaseDsn       string
-		storeIntervalSec  int
-		fileStoragePath   string
-		restore           string
-		key               string
-		cryptoKeyPath     string
-		profile           bool
-		configFile        string
-		trustedSubnet     string
+		serverScheme        string
+		serverHTTPAddress   string
+		databaseDsn         string
+		storeIntervalSec    int
+		fileStoragePath     string
+		restore             string
+		key                 string
+		cryptoKeyPath       string
+		profile             bool
+		configFile          string
+		trustedSubnet       string
+		grpcAddress         string
+		metricNamePattern   string
+		metricNameMaxLength int
+		gaugeNonFinite      string
+		counterOverflow     string
+		ingestRulesPath     string
+		statsdAddress       string
+		statsdFlushInterval int
+		graphiteAddress     string
+		unsignedIngest      string
 	)
 
 	schemeValues = append(schemeValues, defaultScheme)
@@ -92,6 +155,12 @@ func NewConfig() (*Config, error) {
 	storeIntervalValues = append(storeIntervalValues, defaultStoreInterval)
 	filePathValues = append(filePathValues, defaultFileStoragePath)
 	restoreValues = append(restoreValues, defaultRestore)
+	metricNamePatternValues = append(metricNamePatternValues, defaultMetricNamePattern)
+	metricNameMaxLengthValues = append(metricNameMaxLengthValues, defaultMetricNameMaxLength)
+	gaugeNonFiniteValues = append(gaugeNonFiniteValues, defaultGaugeNonFinite)
+	counterOverflowValues = append(counterOverflowValues, defaultCounterOverflow)
+	statsdFlushIntervalValues = append(statsdFlushIntervalValues, defaultStatsdFlushInterval)
+	unsignedIngestValues = append(unsignedIngestValues, defaultUnsignedIngest)
 
 	flag.StringVar(&serverScheme, "s", "", "server scheme: http or https")
 	flag.StringVar(&serverHTTPAddress, "a", "", "address and port of the server")
@@ -104,6 +173,16 @@ func NewConfig() (*Config, error) {
 	flag.BoolVar(&profile, "p", profile, "bool flag for app profiling")
 	flag.StringVar(&configFile, "config", "", "path to config file")
 	flag.StringVar(&trustedSubnet, "t", "", "CIDR for trusted subnet")
+	flag.StringVar(&grpcAddress, "g", "", "address and port of the gRPC server, equal to -a to share one port")
+	flag.StringVar(&metricNamePattern, "metric-name-pattern", "", "regexp for metric names")
+	flag.IntVar(&metricNameMaxLength, "metric-name-max-length", 0, "max length of metric name")
+	flag.StringVar(&gaugeNonFinite, "gauge-non-finite", "", "NaN and Inf gauges policy: reject or allow")
+	flag.StringVar(&counterOverflow, "counter-overflow", "", "counter overflow policy: reject or saturate")
+	flag.StringVar(&ingestRulesPath, "ingest-rules", "", "path to JSON file with rules for line protocol, StatsD and Graphite metrics")
+	flag.StringVar(&statsdAddress, "statsd-address", "", "UDP address of StatsD listener, disabled if empty")
+	flag.IntVar(&statsdFlushInterval, "statsd-flush-interval", 0, "StatsD metrics flush interval in seconds")
+	flag.StringVar(&graphiteAddress, "graphite-address", "", "TCP address of Graphite plaintext listener, disabled if empty")
+	flag.StringVar(&unsignedIngest, "unsigned-ingest", "", "accept unsigned line protocol, Prometheus and OTLP writes when keys are set")
 	flag.Parse()
 
 	if configFile != "" {
@@ -139,6 +218,36 @@ func NewConfig() (*Config, error) {
 			if fileCfg.TrustedSubnet != "" {
 				trustedSubnets = append(trustedSubnets, fileCfg.TrustedSubnet)
 			}
+			if fileCfg.GRPCAddress != "" {
+				grpcAddressValues = append(grpcAddressValues, fileCfg.GRPCAddress)
+			}
+			if fileCfg.MetricNamePattern != "" {
+				metricNamePatternValues = append(metricNamePatternValues, fileCfg.MetricNamePattern)
+			}
+			if fileCfg.MetricNameMaxLength != 0 {
+				metricNameMaxLengthValues = append(metricNameMaxLengthValues, fileCfg.MetricNameMaxLength)
+			}
+			if fileCfg.GaugeNonFinite != "" {
+				gaugeNonFiniteValues = append(gaugeNonFiniteValues, fileCfg.GaugeNonFinite)
+			}
+			if fileCfg.CounterOverflow != "" {
+				counterOverflowValues = append(counterOverflowValues, fileCfg.CounterOverflow)
+			}
+			if fileCfg.IngestRulesPath != "" {
+				ingestRulesPathValues = append(ingestRulesPathValues, fileCfg.IngestRulesPath)
+			}
+			if fileCfg.StatsDAddress != "" {
+				statsdAddressValues = append(statsdAddressValues, fileCfg.StatsDAddress)
+			}
+			if fileCfg.StatsDFlushIntervalSec != 0 {
+				statsdFlushIntervalValues = append(statsdFlushIntervalValues, fileCfg.StatsDFlushIntervalSec)
+			}
+			if fileCfg.GraphiteAddress != "" {
+				graphiteAddressValues = append(graphiteAddressValues, fileCfg.GraphiteAddress)
+			}
+			if fileCfg.UnsignedIngest {
+				unsignedIngestValues = append(unsignedIngestValues, fileCfg.UnsignedIngest)
+			}
 		}
 	}
 
@@ -180,6 +289,48 @@ func NewConfig() (*Config, error) {
 		trustedSubnets = append(trustedSubnets, trustedSubnet)
 	}
 
+	if grpcAddress != "" {
+		grpcAddressValues = append(grpcAddressValues, grpcAddress)
+	}
+
+	if metricNamePattern != "" {
+		metricNamePatternValues = append(metricNamePatternValues, metricNamePattern)
+	}
+
+	if metricNameMaxLength != 0 {
+		metricNameMaxLengthValues = append(metricNameMaxLengthValues, metricNameMaxLength)
+	}
+
+	if gaugeNonFinite != "" {
+		gaugeNonFiniteValues = append(gaugeNonFiniteValues, gaugeNonFinite)
+	}
+
+	if counterOverflow != "" {
+		counterOverflowValues = append(counterOverflowValues, counterOverflow)
+	}
+
+	if ingestRulesPath != "" {
+		ingestRulesPathValues = append(ingestRulesPathValues, ingestRulesPath)
+	}
+
+	if statsdAddress != "" {
+		statsdAddressValues = append(statsdAddressValues, statsdAddress)
+	}
+
+	if statsdFlushInterval != 0 {
+		statsdFlushIntervalValues = append(statsdFlushIntervalValues, statsdFlushInterval)
+	}
+
+	if graphiteAddress != "" {
+		graphiteAddressValues = append(graphiteAddressValues, graphiteAddress)
+	}
+
+	if unsignedIngest != "" {
+		if unsignedIngestFlag, err := strconv.ParseBool(unsignedIngest); err == nil {
+			unsignedIngestValues = append(unsignedIngestValues, unsignedIngestFlag)
+		}
+	}
+
 	if serverSchemeEnv := os.Getenv(schemeEnvName); serverSchemeEnv != "" {
 		schemeValues = append(schemeValues, serverSchemeEnv)
 	}
@@ -218,6 +369,46 @@ func NewConfig() (*Config, error) {
 		trustedSubnets = append(trustedSubnets, trustedSubnetEnv)
 	}
 
+	if grpcAddressEnv := os.Getenv(grpcAddressEnvName); grpcAddressEnv != "" {
+		grpcAddressValues = append(grpcAddressValues, grpcAddressEnv)
+	}
+
+	if metricNamePatternEnv := os.Getenv(metricNamePatternEnvName); metricNamePatternEnv != "" {
+		metricNamePatternValues = append(metricNamePatternValues, metricNamePatternEnv)
+	}
+
+	if metricNameMaxLengthEnv, err := strconv.Atoi(os.Getenv(metricNameMaxLengthEnvName)); err == nil && metricNameMaxLengthEnv != 0 {
+		metricNameMaxLengthValues = append(metricNameMaxLengthValues, metricNameMaxLengthEnv)
+	}
+
+	if gaugeNonFiniteEnv := os.Getenv(gaugeNonFiniteEnvName); gaugeNonFiniteEnv != "" {
+		gaugeNonFiniteValues = append(gaugeNonFiniteValues, gaugeNonFiniteEnv)
+	}
+
+	if counterOverflowEnv := os.Getenv(counterOverflowEnvName); counterOverflowEnv != "" {
+		counterOverflowValues = append(counterOverflowValues, counterOverflowEnv)
+	}
+
+	if ingestRulesPathEnv := os.Getenv(ingestRulesPathEnvName); ingestRulesPathEnv != "" {
+		ingestRulesPathValues = append(ingestRulesPathValues, ingestRulesPathEnv)
+	}
+
+	if statsdAddressEnv := os.Getenv(statsdAddressEnvName); statsdAddressEnv != "" {
+		statsdAddressValues = append(statsdAddressValues, statsdAddressEnv)
+	}
+
+	if statsdFlushIntervalEnv, err := strconv.Atoi(os.Getenv(statsdFlushIntervalEnvName)); err == nil && statsdFlushIntervalEnv != 0 {
+		statsdFlushIntervalValues = append(statsdFlushIntervalValues, statsdFlushIntervalEnv)
+	}
+
+	if graphiteAddressEnv := os.Getenv(graphiteAddressEnvName); graphiteAddressEnv != "" {
+		graphiteAddressValues = append(graphiteAddressValues, graphiteAddressEnv)
+	}
+
+	if unsignedIngestEnv, err := strconv.ParseBool(os.Getenv(unsignedIngestEnvName)); err == nil {
+		unsignedIngestValues = append(unsignedIngestValues, unsignedIngestEnv)
+	}
+
 	schemeConfig := schemeValues[len(schemeValues)-1]
 	if err := formatter.CheckSchemeFormat(schemeConfig); err != nil {
 		return nil, err
@@ -260,17 +451,73 @@ func NewConfig() (*Config, error) {
 		}
 	}
 
+	var grpcAddressConfig string
+	if len(grpcAddressValues) != 0 {
+		grpcAddressConfig = grpcAddressValues[len(grpcAddressValues)-1]
+		if err := formatter.CheckAddrFormat(grpcAddressConfig); err != nil {
+			return nil, err
+		}
+	}
+
+	validator, err := domain.NewValidator(domain.ValidationRules{
+		NamePattern:     metricNamePatternValues[len(metricNamePatternValues)-1],
+		MaxNameLength:   metricNameMaxLengthValues[len(metricNameMaxLengthValues)-1],
+		NonFinitePolicy: domain.NonFinitePolicy(gaugeNonFiniteValues[len(gaugeNonFiniteValues)-1]),
+		OverflowPolicy:  domain.OverflowPolicy(counterOverflowValues[len(counterOverflowValues)-1]),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var ingestRulesPathConfig string
+	if len(ingestRulesPathValues) != 0 {
+		ingestRulesPathConfig = ingestRulesPathValues[len(ingestRulesPathValues)-1]
+	}
+
+	ingestRules, err := ingest.LoadRules(ingestRulesPathConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	var statsdAddressConfig string
+	if len(statsdAddressValues) != 0 {
+		statsdAddressConfig = statsdAddressValues[len(statsdAddressValues)-1]
+		if err := formatter.CheckAddrFormat(statsdAddressConfig); err != nil {
+			return nil, err
+		}
+	}
+
+	statsdFlushIntervalConfig := statsdFlushIntervalValues[len(statsdFlushIntervalValues)-1]
+	if statsdFlushIntervalConfig <= 0 {
+		return nil, errors.New("statsd flush interval must be positive")
+	}
+
+	var graphiteAddressConfig string
+	if len(graphiteAddressValues) != 0 {
+		graphiteAddressConfig = graphiteAddressValues[len(graphiteAddressValues)-1]
+		if err := formatter.CheckAddrFormat(graphiteAddressConfig); err != nil {
+			return nil, err
+		}
+	}
+
 	return &Config{
-		Scheme:          schemeConfig,
-		HTTPAddress:     addressConfig,
-		DatabaseDSN:     databaseDSNConfig,
-		StoreInterval:   time.Duration(storeIntervalConfig) * time.Second,
-		FileStoragePath: fileStorageConfig,
-		Restore:         restoreConfig,
-		Key:             keyConfig,
-		CryptoKey:       privateKey,
-		Profiling:       profile,
-		TrustedSubnet:   trustedSubnetConfig,
+		Scheme:              schemeConfig,
+		HTTPAddress:         addressConfig,
+		DatabaseDSN:         databaseDSNConfig,
+		StoreInterval:       time.Duration(storeIntervalConfig) * time.Second,
+		FileStoragePath:     fileStorageConfig,
+		Restore:             restoreConfig,
+		Key:                 keyConfig,
+		CryptoKey:           privateKey,
+		Profiling:           profile,
+		TrustedSubnet:       trustedSubnetConfig,
+		GRPCAddress:         grpcAddressConfig,
+		Validator:           validator,
+		IngestRules:         ingestRules,
+		StatsDAddress:       statsdAddressConfig,
+		StatsDFlushInterval: time.Duration(statsdFlushIntervalConfig) * time.Second,
+		GraphiteAddress:     graphiteAddressConfig,
+		UnsignedIngest:      unsignedIngestValues[len(unsignedIngestValues)-1],
 	}, nil
 }
 
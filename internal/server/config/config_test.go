@@ -12,6 +12,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/frolmr/metrics/internal/domain"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -842,3 +843,208 @@ func TestTrustedSubnetPriority(t *testing.T) {
 		})
 	}
 }
+
+func TestParseGRPCAddressFlag(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		envValue string
+		want     string
+		wantErr  bool
+	}{
+		{name: "not set", args: []string{}, want: ""},
+		{name: "from flag", args: []string{"-g", "localhost:3200"}, want: "localhost:3200"},
+		{name: "env overrides flag", args: []string{"-g", "localhost:3200"}, envValue: "localhost:3300", want: "localhost:3300"},
+		{name: "bad format", args: []string{"-g", "localhost"}, wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if test.envValue != "" {
+				t.Setenv("GRPC_ADDRESS", test.envValue)
+			}
+
+			os.Args = append([]string{"cmd"}, test.args...)
+			flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+
+			config, err := NewConfig()
+			if test.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.want, config.GRPCAddress)
+		})
+	}
+}
+
+func TestValidationRulesConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		env     map[string]string
+		wantErr bool
+	}{
+		{name: "defaults", args: []string{}},
+		{name: "from flags", args: []string{"-metric-name-pattern", "^[a-z]+$", "-metric-name-max-length", "10",
+			"-gauge-non-finite", "allow", "-counter-overflow", "saturate"}},
+		{name: "from env", args: []string{}, env: map[string]string{"METRIC_NAME_MAX_LENGTH": "10", "COUNTER_OVERFLOW": "saturate"}},
+		{name: "bad pattern", args: []string{"-metric-name-pattern", "["}, wantErr: true},
+		{name: "bad policy", args: []string{}, env: map[string]string{"GAUGE_NON_FINITE": "zero"}, wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for k, v := range test.env {
+				t.Setenv(k, v)
+			}
+
+			os.Args = append([]string{"cmd"}, test.args...)
+			flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+
+			config, err := NewConfig()
+			if test.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.NotNil(t, config.Validator)
+		})
+	}
+
+	t.Run("rules applied", func(t *testing.T) {
+		os.Args = []string{"cmd", "-metric-name-max-length", "3"}
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+
+		config, err := NewConfig()
+		require.NoError(t, err)
+		require.NoError(t, config.Validator.ValidateName("abc"))
+		require.ErrorIs(t, config.Validator.ValidateName("abcd"), domain.ErrNameTooLong)
+	})
+}
+
+func TestIngestRulesConfig(t *testing.T) {
+	dir := t.TempDir()
+
+	rulesPath := filepath.Join(dir, "rules.json")
+	require.NoError(t, os.WriteFile(rulesPath, []byte(`{"rules":[{"match":"_count$","type":"counter"}],"tags":["host"]}`), 0600))
+
+	badRulesPath := filepath.Join(dir, "bad.json")
+	require.NoError(t, os.WriteFile(badRulesPath, []byte(`{"rules":[{"match":"x","type":"histogram"}]}`), 0600))
+
+	tests := []struct {
+		name     string
+		args     []string
+		env      map[string]string
+		wantType string
+		wantErr  bool
+	}{
+		{name: "defaults", args: []string{}, wantType: domain.GaugeType},
+		{name: "from flag", args: []string{"-ingest-rules", rulesPath}, wantType: domain.CounterType},
+		{name: "from env", args: []string{}, env: map[string]string{"INGEST_RULES": rulesPath}, wantType: domain.CounterType},
+		{name: "missing file", args: []string{"-ingest-rules", filepath.Join(dir, "missing.json")}, wantErr: true},
+		{name: "bad rules", args: []string{"-ingest-rules", badRulesPath}, wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for k, v := range test.env {
+				t.Setenv(k, v)
+			}
+
+			os.Args = append([]string{"cmd"}, test.args...)
+			flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+
+			config, err := NewConfig()
+			if test.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.wantType, config.IngestRules.TypeOf("requests_count"))
+		})
+	}
+}
+
+func TestStatsDConfig(t *testing.T) {
+	tests := []struct {
+		name         string
+		args         []string
+		env          map[string]string
+		wantAddress  string
+		wantInterval time.Duration
+		wantErr      bool
+	}{
+		{name: "defaults", args: []string{}, wantInterval: 10 * time.Second},
+		{name: "from flags", args: []string{"-statsd-address", ":8125", "-statsd-flush-interval", "5"},
+			wantAddress: ":8125", wantInterval: 5 * time.Second},
+		{name: "env overrides flags", args: []string{"-statsd-address", ":8125"},
+			env:         map[string]string{"STATSD_ADDRESS": "localhost:9125", "STATSD_FLUSH_INTERVAL": "1"},
+			wantAddress: "localhost:9125", wantInterval: time.Second},
+		{name: "bad address", args: []string{"-statsd-address", "8125"}, wantErr: true},
+		{name: "bad interval", args: []string{"-statsd-flush-interval", "-1"}, wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for k, v := range test.env {
+				t.Setenv(k, v)
+			}
+
+			os.Args = append([]string{"cmd"}, test.args...)
+			flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+
+			config, err := NewConfig()
+			if test.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.wantAddress, config.StatsDAddress)
+			assert.Equal(t, test.wantInterval, config.StatsDFlushInterval)
+		})
+	}
+}
+
+func TestGraphiteAddressConfig(t *testing.T) {
+	os.Args = []string{"cmd", "-graphite-address", ":2003"}
+	flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+
+	config, err := NewConfig()
+	require.NoError(t, err)
+	assert.Equal(t, ":2003", config.GraphiteAddress)
+
+	t.Setenv("GRAPHITE_ADDRESS", "2003")
+	flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+
+	_, err = NewConfig()
+	require.Error(t, err)
+}
+
+func TestUnsignedIngestConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		env  map[string]string
+		want bool
+	}{
+		{name: "off by default", args: []string{}, want: false},
+		{name: "from flag", args: []string{"-unsigned-ingest", "true"}, want: true},
+		{name: "env overrides flag", args: []string{"-unsigned-ingest", "true"}, env: map[string]string{"UNSIGNED_INGEST": "false"}, want: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for k, v := range test.env {
+				t.Setenv(k, v)
+			}
+
+			os.Args = append([]string{"cmd"}, test.args...)
+			flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+
+			config, err := NewConfig()
+			require.NoError(t, err)
+			assert.Equal(t, test.want, config.UnsignedIngest)
+		})
+	}
+}
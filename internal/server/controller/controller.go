@@ -2,12 +2,18 @@
 package controller
 
 import (
+	"net/http"
+
+	"github.com/frolmr/metrics/internal/domain"
+	"github.com/frolmr/metrics/internal/server/apierror"
 	"github.com/frolmr/metrics/internal/server/config"
 	"github.com/frolmr/metrics/internal/server/decryptor"
 	"github.com/frolmr/metrics/internal/server/handlers"
 	"github.com/frolmr/metrics/internal/server/logger"
 	"github.com/frolmr/metrics/internal/server/middleware"
+	"github.com/frolmr/metrics/internal/server/pubsub"
 	"github.com/frolmr/metrics/internal/server/storage"
+	"github.com/frolmr/metrics/internal/server/ui"
 	"github.com/go-chi/chi/v5"
 )
 
@@ -24,32 +30,93 @@ func NewController(lgr *logger.Logger, cfg *config.Config) *Controller {
 	}
 }
 
-// SetupHandlers functions is resonsible for app routing
-func (c *Controller) SetupHandlers(stor storage.Repository) chi.Router {
+// SetupHandlers functions is resonsible for app routing,
+// legacy routes stay as they are and versioned API under apierror.APIPrefix answers with JSON errors.
+// Updates accepted by stor are expected to be published to hub, stream is disabled if hub is nil.
+func (c *Controller) SetupHandlers(stor storage.Repository, hub *pubsub.Hub) chi.Router {
 	r := chi.NewRouter()
 
 	r.Use(middleware.WithTrustedSubnet(c.config.TrustedSubnet))
 	r.Use(middleware.Compressor)
 	r.Use(middleware.WithLog(c.logger))
-	r.Use(middleware.WithDecrypt(decryptor.NewDecryptor(c.config.CryptoKey)))
-	r.Use(middleware.WithSignature(c.config.Key))
+	secured := chi.Chain(
+		middleware.WithDecrypt(decryptor.NewDecryptor(c.config.CryptoKey)),
+		middleware.WithSignature(c.config.Key),
+	)
+	// NOTE: Prometheus, Telegraf and OTLP senders can neither encrypt nor sign bodies, so their routes skip both
+	// and are open with keys set only if UnsignedIngest allows it
+	unsigned := chi.Chain(
+		middleware.WithUnsignedIngest(c.config.UnsignedIngest || c.config.Key == "" && c.config.CryptoKey == nil),
+	)
 
-	rh := handlers.NewRequestHandler(stor)
+	rh := handlers.NewRequestHandler(stor, c.config.Validator)
+	sh := handlers.NewStreamHandler(hub)
+	wh := handlers.NewWriteHandler(stor, c.config.Validator, c.config.IngestRules)
+	oh := handlers.NewOTLPHandler(stor, c.config.Validator, c.config.IngestRules)
+	pw := handlers.NewRemoteWriteHandler(stor, c.config.Validator, c.config.IngestRules)
+	eh := handlers.NewExportHandler(stor)
+	ih := handlers.NewImportHandler(stor, c.config.Validator)
 
-	r.Get("/", rh.GetMetrics())
+	r.With(unsigned...).Post(handlers.OTLPMetricsPath, oh.ExportMetrics())
 
-	r.Route("/update/", func(r chi.Router) {
-		r.Post("/", rh.UpdateMetricJSON())
-		r.Post("/{type}/{name}/{value}", rh.UpdateMetric())
-	})
+	r.Group(func(r chi.Router) {
+		r.Use(secured...)
+
+		r.Get("/", rh.GetMetrics())
+
+		r.Route("/update/", func(r chi.Router) {
+			r.Post("/", rh.UpdateMetricJSON())
+			r.Post("/{type}/{name}/{value}", rh.UpdateMetric())
+		})
 
-	r.Route("/value/", func(r chi.Router) {
-		r.Post("/", rh.GetMetricJSON())
-		r.Get("/{type}/{name}", rh.GetMetric())
+		r.Route("/value/", func(r chi.Router) {
+			r.Post("/", rh.GetMetricJSON())
+			r.Get("/{type}/{name}", rh.GetMetric())
+		})
+
+		r.Get("/ping", rh.Ping())
+		r.Post("/updates/", rh.BulkUpdateMetricJSON())
+
+		r.Get(ui.Prefix, ui.Redirect)
+		r.Handle(ui.Prefix+"/*", ui.Handler())
 	})
 
-	r.Get("/ping", rh.Ping())
-	r.Post("/updates/", rh.BulkUpdateMetricJSON())
+	r.Route(apierror.APIPrefix, func(r chi.Router) {
+		r.NotFound(func(res http.ResponseWriter, req *http.Request) {
+			apierror.Write(res, req, http.StatusNotFound, apierror.CodeNotFound, "route not found", nil)
+		})
+		r.MethodNotAllowed(func(res http.ResponseWriter, req *http.Request) {
+			apierror.Write(res, req, http.StatusMethodNotAllowed, apierror.CodeMethodNotAllowed, "method not allowed", nil)
+		})
+
+		r.Group(func(r chi.Router) {
+			r.Use(unsigned...)
+
+			r.Post("/write", wh.Write())
+			r.With(middleware.WithContentType(handlers.ProtobufContentType)).Post("/prom/write", pw.RemoteWrite())
+		})
+
+		r.Group(func(r chi.Router) {
+			r.Use(secured...)
+
+			r.Get("/value/{type}/{name}", rh.GetMetric())
+			r.With(middleware.WithAccept(handlers.EventStreamContentType)).Get("/stream", sh.StreamMetrics())
+			r.Get("/export", eh.Export())
+
+			r.Group(func(r chi.Router) {
+				r.Use(middleware.WithAccept(domain.JSONContentType))
+
+				r.Get("/ping", rh.Ping())
+				r.Get("/metrics", rh.ListMetrics())
+				r.Post("/update/{type}/{name}/{value}", rh.UpdateMetric())
+
+				r.With(middleware.WithContentType(domain.JSONContentType)).Post("/update", rh.UpdateMetricJSON())
+				r.With(middleware.WithContentType(domain.JSONContentType)).Post("/updates", rh.BulkUpdateMetricJSON())
+				r.With(middleware.WithContentType(domain.JSONContentType)).Post("/value", rh.GetMetricJSON())
+				r.Post("/import", ih.Import())
+			})
+		})
+	})
 
 	return r
 }
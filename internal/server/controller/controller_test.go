@@ -0,0 +1,471 @@
+package controller
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/frolmr/metrics/internal/domain"
+	"github.com/frolmr/metrics/internal/server/apierror"
+	"github.com/frolmr/metrics/internal/server/config"
+	"github.com/frolmr/metrics/internal/server/handlers"
+	"github.com/frolmr/metrics/internal/server/logger"
+	"github.com/frolmr/metrics/internal/server/pubsub"
+	"github.com/frolmr/metrics/internal/server/storage"
+	otlp "github.com/frolmr/metrics/pkg/proto/otlp"
+	prompb "github.com/frolmr/metrics/pkg/proto/prompb"
+	"github.com/golang/snappy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+)
+
+func newTestServer(t *testing.T, cfg *config.Config) *httptest.Server {
+	t.Helper()
+
+	lgr := &logger.Logger{SugaredLogger: *zap.NewNop().Sugar()}
+	stor := storage.NewMemStorage()
+	stor.CounterMetrics["hits"] = 5
+	stor.GaugeMetrics["load"] = 1.5
+
+	ts := httptest.NewServer(NewController(lgr, cfg).SetupHandlers(stor, nil))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func doRequest(t *testing.T, ts *httptest.Server, method, path, body string, headers map[string]string) (*http.Response, string) {
+	t.Helper()
+
+	req, err := http.NewRequest(method, ts.URL+path, strings.NewReader(body))
+	require.NoError(t, err)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := ts.Client().Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp, string(respBody)
+}
+
+func TestAPIv1(t *testing.T) {
+	ts := newTestServer(t, &config.Config{})
+
+	jsonBody := map[string]string{"Content-Type": domain.JSONContentType}
+
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		body     string
+		headers  map[string]string
+		wantCode int
+		wantBody string
+		wantErr  string
+	}{
+		{
+			name:     "ping",
+			method:   http.MethodGet,
+			path:     "/api/v1/ping",
+			wantCode: http.StatusOK,
+			wantBody: `{"status":"ok"}`,
+		},
+		{
+			name:     "get metric as json",
+			method:   http.MethodGet,
+			path:     "/api/v1/value/counter/hits",
+			wantCode: http.StatusOK,
+			wantBody: `{"id":"hits","type":"counter","delta":5}`,
+		},
+		{
+			name:     "list metrics",
+			method:   http.MethodGet,
+			path:     "/api/v1/metrics?sort=-name",
+			wantCode: http.StatusOK,
+			wantBody: `{"metrics":[{"id":"load","type":"gauge","value":1.5},{"id":"hits","type":"counter","delta":5}]}`,
+		},
+		{
+			name:     "update metric by url",
+			method:   http.MethodPost,
+			path:     "/api/v1/update/gauge/load/2.5",
+			wantCode: http.StatusOK,
+			wantBody: `{"id":"load","type":"gauge","value":2.5}`,
+		},
+		{
+			name:     "update metric by json",
+			method:   http.MethodPost,
+			path:     "/api/v1/update",
+			body:     `{"id":"hits","type":"counter","delta":2}`,
+			headers:  jsonBody,
+			wantCode: http.StatusOK,
+			wantBody: `{"id":"hits","type":"counter","delta":7}`,
+		},
+		{
+			name:     "json update without content type",
+			method:   http.MethodPost,
+			path:     "/api/v1/update",
+			body:     `{"id":"hits","type":"counter","delta":2}`,
+			wantCode: http.StatusUnsupportedMediaType,
+			wantErr:  apierror.CodeUnsupportedMediaType,
+		},
+		{
+			name:     "unknown metric",
+			method:   http.MethodGet,
+			path:     "/api/v1/value/gauge/unknown",
+			wantCode: http.StatusNotFound,
+			wantErr:  apierror.CodeNotFound,
+		},
+		{
+			name:     "invalid value",
+			method:   http.MethodPost,
+			path:     "/api/v1/update/gauge/load/abc",
+			wantCode: http.StatusBadRequest,
+			wantErr:  apierror.CodeBadRequest,
+		},
+		{
+			name:     "invalid name",
+			method:   http.MethodPost,
+			path:     "/api/v1/update",
+			body:     `{"id":"bad name","type":"gauge","value":1}`,
+			headers:  jsonBody,
+			wantCode: http.StatusBadRequest,
+			wantErr:  apierror.CodeValidationFailed,
+		},
+		{
+			name:     "all metrics rejected",
+			method:   http.MethodPost,
+			path:     "/api/v1/updates",
+			body:     `[{"id":"x","type":"gauge"}]`,
+			headers:  jsonBody,
+			wantCode: http.StatusBadRequest,
+			wantErr:  apierror.CodeValidationFailed,
+		},
+		{
+			name:     "unknown route",
+			method:   http.MethodGet,
+			path:     "/api/v1/unknown",
+			wantCode: http.StatusNotFound,
+			wantErr:  apierror.CodeNotFound,
+		},
+		{
+			name:     "wrong method",
+			method:   http.MethodDelete,
+			path:     "/api/v1/ping",
+			wantCode: http.StatusMethodNotAllowed,
+			wantErr:  apierror.CodeMethodNotAllowed,
+		},
+		{
+			name:     "json not acceptable",
+			method:   http.MethodGet,
+			path:     "/api/v1/ping",
+			headers:  map[string]string{"Accept": "text/html"},
+			wantCode: http.StatusNotAcceptable,
+			wantErr:  apierror.CodeNotAcceptable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, body := doRequest(t, ts, tt.method, tt.path, tt.body, tt.headers)
+
+			assert.Equal(t, tt.wantCode, resp.StatusCode)
+			assert.Equal(t, domain.JSONContentType, resp.Header.Get("Content-Type"))
+
+			if tt.wantBody != "" {
+				assert.JSONEq(t, tt.wantBody, body)
+			}
+			if tt.wantErr != "" {
+				var apiErr apierror.Error
+				require.NoError(t, json.Unmarshal([]byte(body), &apiErr))
+				assert.Equal(t, tt.wantErr, apiErr.Code)
+				assert.NotEmpty(t, apiErr.Message)
+			}
+		})
+	}
+}
+
+func TestAPIv1_MetricAsText(t *testing.T) {
+	ts := newTestServer(t, &config.Config{})
+
+	resp, body := doRequest(t, ts, http.MethodGet, "/api/v1/value/gauge/load", "",
+		map[string]string{"Accept": domain.TextContentType})
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, domain.TextContentType, resp.Header.Get("Content-Type"))
+	assert.Equal(t, "1.5", body)
+}
+
+func TestLegacyRoutes(t *testing.T) {
+	ts := newTestServer(t, &config.Config{})
+
+	resp, _ := doRequest(t, ts, http.MethodPost, "/update/gauge/load/2.5", "", nil)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, domain.TextContentType, resp.Header.Get("Content-Type"))
+
+	resp, body := doRequest(t, ts, http.MethodGet, "/value/gauge/load", "", nil)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "2.5", body)
+
+	resp, body = doRequest(t, ts, http.MethodGet, "/value/gauge/unknown", "", nil)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, "Metric Not Found\n", body)
+}
+
+func TestInvalidSignature(t *testing.T) {
+	ts := newTestServer(t, &config.Config{Key: "secret"})
+
+	headers := map[string]string{
+		"Content-Type":         domain.JSONContentType,
+		domain.SignatureHeader: "deadbeef",
+	}
+
+	resp, body := doRequest(t, ts, http.MethodPost, "/api/v1/update", `{"id":"x","type":"gauge","value":1}`, headers)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.JSONEq(t, `{"code":"invalid_signature","message":"invalid signature"}`, body)
+
+	resp, body = doRequest(t, ts, http.MethodPost, "/update/", `{"id":"x","type":"gauge","value":1}`, headers)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, "invalid signature\n", body)
+}
+
+func TestIngestionWithKeys(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	remoteWrite, err := proto.Marshal(&prompb.WriteRequest{Timeseries: []*prompb.TimeSeries{{
+		Labels:  []*prompb.Label{{Name: "__name__", Value: "temperature"}},
+		Samples: []*prompb.Sample{{Value: 21, Timestamp: 1}},
+	}}})
+	require.NoError(t, err)
+	export, err := proto.Marshal(&otlp.ExportMetricsServiceRequest{})
+	require.NoError(t, err)
+
+	// ingest sends unsigned plain bodies to every route which can't be encrypted or signed and returns statuses
+	ingest := func(t *testing.T, ts *httptest.Server) []int {
+		t.Helper()
+
+		var statuses []int
+		resp, _ := doRequest(t, ts, http.MethodPost, "/api/v1/write", "load value=3.5\n", nil)
+		statuses = append(statuses, resp.StatusCode)
+		resp, _ = doRequest(t, ts, http.MethodPost, "/api/v1/prom/write", string(snappy.Encode(nil, remoteWrite)), map[string]string{
+			"Content-Type":     handlers.ProtobufContentType,
+			"Content-Encoding": handlers.SnappyEncoding,
+		})
+		statuses = append(statuses, resp.StatusCode)
+		resp, _ = doRequest(t, ts, http.MethodPost, "/v1/metrics", string(export), map[string]string{
+			"Content-Type": handlers.ProtobufContentType,
+		})
+		return append(statuses, resp.StatusCode)
+	}
+
+	t.Run("forbidden by default with crypto key", func(t *testing.T) {
+		ts := newTestServer(t, &config.Config{CryptoKey: privateKey})
+		assert.Equal(t, []int{http.StatusForbidden, http.StatusForbidden, http.StatusForbidden}, ingest(t, ts))
+	})
+
+	t.Run("forbidden by default with signing key", func(t *testing.T) {
+		ts := newTestServer(t, &config.Config{Key: "secret"})
+		assert.Equal(t, []int{http.StatusForbidden, http.StatusForbidden, http.StatusForbidden}, ingest(t, ts))
+	})
+
+	t.Run("allowed by unsigned ingest", func(t *testing.T) {
+		ts := newTestServer(t, &config.Config{CryptoKey: privateKey, UnsignedIngest: true})
+		assert.Equal(t, []int{http.StatusNoContent, http.StatusNoContent, http.StatusOK}, ingest(t, ts))
+
+		resp, body := doRequest(t, ts, http.MethodPost, "/api/v1/update", `{"id":"x","type":"gauge","value":1}`, map[string]string{
+			"Content-Type": domain.JSONContentType,
+		})
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode, "own API still requires encrypted body")
+		assert.Contains(t, body, `"code":"decryption_failed"`)
+	})
+
+	t.Run("open without keys", func(t *testing.T) {
+		ts := newTestServer(t, &config.Config{})
+		assert.Equal(t, []int{http.StatusNoContent, http.StatusNoContent, http.StatusOK}, ingest(t, ts))
+	})
+}
+
+func TestDashboard(t *testing.T) {
+	ts := newTestServer(t, &config.Config{})
+
+	resp, body := doRequest(t, ts, http.MethodGet, "/ui", "", nil)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "/ui/", resp.Request.URL.Path)
+	assert.Contains(t, body, "<title>Metrics</title>")
+}
+
+func TestStream(t *testing.T) {
+	hub := pubsub.NewHub(pubsub.DefaultBufferSize)
+	defer hub.Close()
+
+	lgr := &logger.Logger{SugaredLogger: *zap.NewNop().Sugar()}
+	stor := storage.NewPublishingStorage(storage.NewMemStorage(), hub)
+
+	ts := httptest.NewServer(NewController(lgr, &config.Config{}).SetupHandlers(stor, hub))
+	defer ts.Close()
+
+	// NOTE: default client asks for gzip, so this also checks that compressed stream is flushed
+	resp, err := ts.Client().Get(ts.URL + "/api/v1/stream?prefix=load")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Eventually(t, func() bool { return hub.Subscribers() == 1 }, time.Second, 5*time.Millisecond)
+
+	doRequest(t, ts, http.MethodPost, "/update/gauge/other/1", "", nil)
+	doRequest(t, ts, http.MethodPost, "/update/gauge/load/2.5", "", nil)
+
+	events := bufio.NewReader(resp.Body)
+	event, err := events.ReadString('\n')
+	require.NoError(t, err)
+	assert.Equal(t, "event: metric\n", event)
+
+	data, err := events.ReadString('\n')
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"id":"load","type":"gauge","value":2.5}`, strings.TrimPrefix(data, "data: "))
+
+	resp, _ = doRequest(t, ts, http.MethodGet, "/api/v1/stream", "", map[string]string{"Accept": domain.JSONContentType})
+	assert.Equal(t, http.StatusNotAcceptable, resp.StatusCode)
+}
+
+func TestWrite(t *testing.T) {
+	ts := newTestServer(t, &config.Config{})
+
+	// NOTE: Telegraf compresses line protocol with gzip by default
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	_, err := zw.Write([]byte("load value=3.5\nhits,host=a value=2i\n"))
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+
+	resp, body := doRequest(t, ts, http.MethodPost, "/api/v1/write", buf.String(), map[string]string{
+		"Content-Type":     "text/plain; charset=utf-8",
+		"Content-Encoding": "gzip",
+	})
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	assert.Empty(t, body)
+
+	_, body = doRequest(t, ts, http.MethodGet, "/api/v1/value/gauge/load_value", "", nil)
+	assert.JSONEq(t, `{"id":"load_value","type":"gauge","value":3.5}`, body)
+
+	_, body = doRequest(t, ts, http.MethodGet, "/api/v1/value/gauge/hits_value", "", nil)
+	assert.JSONEq(t, `{"id":"hits_value","type":"gauge","value":2}`, body)
+
+	resp, body = doRequest(t, ts, http.MethodPost, "/api/v1/write", "load", nil)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Contains(t, body, `"code":"validation_failed"`)
+}
+
+func TestOTLPMetrics(t *testing.T) {
+	ts := newTestServer(t, &config.Config{})
+
+	request := &otlp.ExportMetricsServiceRequest{
+		ResourceMetrics: []*otlp.ResourceMetrics{{ScopeMetrics: []*otlp.ScopeMetrics{{Metrics: []*otlp.Metric{{
+			Name: "hits",
+			Data: &otlp.Metric_Sum{Sum: &otlp.Sum{
+				DataPoints:             []*otlp.NumberDataPoint{{Value: &otlp.NumberDataPoint_AsInt{AsInt: 3}}},
+				AggregationTemporality: otlp.AggregationTemporality_AGGREGATION_TEMPORALITY_DELTA,
+				IsMonotonic:            true,
+			}},
+		}}}}}},
+	}
+	body, err := proto.Marshal(request)
+	require.NoError(t, err)
+
+	// NOTE: OTLP exporters compress requests with gzip by default
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	_, err = zw.Write(body)
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+
+	resp, _ := doRequest(t, ts, http.MethodPost, "/v1/metrics", buf.String(), map[string]string{
+		"Content-Type":     handlers.ProtobufContentType,
+		"Content-Encoding": "gzip",
+	})
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	_, respBody := doRequest(t, ts, http.MethodGet, "/api/v1/value/counter/hits", "", nil)
+	assert.JSONEq(t, `{"id":"hits","type":"counter","delta":8}`, respBody)
+}
+
+func TestPromRemoteWrite(t *testing.T) {
+	ts := newTestServer(t, &config.Config{})
+
+	request := func(value float64) string {
+		body, err := proto.Marshal(&prompb.WriteRequest{Timeseries: []*prompb.TimeSeries{{
+			Labels:  []*prompb.Label{{Name: "__name__", Value: "hits_total"}, {Name: "instance", Value: "a"}},
+			Samples: []*prompb.Sample{{Value: value, Timestamp: 1}},
+		}}})
+		require.NoError(t, err)
+		return string(snappy.Encode(nil, body))
+	}
+
+	headers := map[string]string{
+		"Content-Type":     handlers.ProtobufContentType,
+		"Content-Encoding": handlers.SnappyEncoding,
+	}
+	for _, value := range []float64{5, 8} {
+		resp, _ := doRequest(t, ts, http.MethodPost, "/api/v1/prom/write", request(value), headers)
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	}
+
+	_, respBody := doRequest(t, ts, http.MethodGet, "/api/v1/value/counter/hits_total", "", nil)
+	assert.JSONEq(t, `{"id":"hits_total","type":"counter","delta":3}`, respBody)
+
+	resp, _ := doRequest(t, ts, http.MethodPost, "/api/v1/prom/write", request(1), map[string]string{
+		"Content-Type":     "text/plain",
+		"Content-Encoding": handlers.SnappyEncoding,
+	})
+	assert.Equal(t, http.StatusUnsupportedMediaType, resp.StatusCode)
+}
+
+func TestExport(t *testing.T) {
+	ts := newTestServer(t, &config.Config{})
+
+	req, err := http.NewRequest(http.MethodGet, ts.URL+"/api/v1/export?format=csv", nil)
+	require.NoError(t, err)
+	req.Header.Set("Accept-Encoding", domain.CompressFormat)
+
+	// NOTE: transport decompresses only responses to requests it added Accept-Encoding to itself
+	res, err := ts.Client().Do(req)
+	require.NoError(t, err)
+	defer res.Body.Close()
+
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, domain.CompressFormat, res.Header.Get("Content-Encoding"))
+
+	zr, err := gzip.NewReader(res.Body)
+	require.NoError(t, err)
+	body, err := io.ReadAll(zr)
+	require.NoError(t, err)
+	assert.Equal(t, "id,type,value\nhits,counter,5\nload,gauge,1.5\n", string(body))
+}
+
+func TestImport(t *testing.T) {
+	ts := newTestServer(t, &config.Config{})
+
+	_, dump := doRequest(t, ts, http.MethodGet, "/api/v1/export?format=ndjson", "", nil)
+
+	resp, body := doRequest(t, ts, http.MethodPost, "/api/v1/import?counters=overwrite", dump, map[string]string{
+		"Content-Type": handlers.NDJSONContentType,
+	})
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.JSONEq(t, `{"imported":2,"rejected":0,"errors":[]}`, body)
+
+	_, body = doRequest(t, ts, http.MethodGet, "/api/v1/value/counter/hits", "", nil)
+	assert.JSONEq(t, `{"id":"hits","type":"counter","delta":5}`, body, "importing own export changes nothing")
+}
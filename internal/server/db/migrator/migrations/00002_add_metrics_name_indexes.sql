@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE INDEX IF NOT EXISTS counter_metrics_name_c_idx ON counter_metrics (name COLLATE "C");
+CREATE INDEX IF NOT EXISTS gauge_metrics_name_c_idx ON gauge_metrics (name COLLATE "C");
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS counter_metrics_name_c_idx;
+DROP INDEX IF EXISTS gauge_metrics_name_c_idx;
+-- +goose StatementEnd
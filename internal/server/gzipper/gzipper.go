@@ -12,6 +12,7 @@ type compressWriter struct {
 	w           http.ResponseWriter
 	zw          *gzip.Writer
 	wroteHeader bool
+	compressed  bool
 }
 
 func NewCompressWriter(w http.ResponseWriter) *compressWriter {
@@ -29,6 +30,10 @@ func (c *compressWriter) Write(p []byte) (int, error) {
 	if !c.wroteHeader {
 		c.WriteHeader(http.StatusOK)
 	}
+	// NOTE: error responses go uncompressed, they have no Content-Encoding header
+	if !c.compressed {
+		return c.w.Write(p)
+	}
 	return c.zw.Write(p)
 }
 
@@ -41,11 +46,34 @@ func (c *compressWriter) WriteHeader(statusCode int) {
 	c.wroteHeader = true
 
 	if statusCode < http.StatusMultipleChoices {
+		c.compressed = true
+		c.w.Header().Del("Content-Length")
 		c.w.Header().Set("Content-Encoding", domain.CompressFormat)
 	}
 }
 
+// FlushError sends compressed so far data to client, needed for streaming responses.
+func (c *compressWriter) FlushError() error {
+	if !c.wroteHeader {
+		c.WriteHeader(http.StatusOK)
+	}
+	if c.compressed {
+		if err := c.zw.Flush(); err != nil {
+			return err
+		}
+	}
+	return http.NewResponseController(c.w).Flush()
+}
+
+// Unwrap lets http.ResponseController reach features of the underlying writer other than flushing.
+func (c *compressWriter) Unwrap() http.ResponseWriter {
+	return c.w
+}
+
 func (c *compressWriter) Close() error {
+	if !c.compressed {
+		return nil
+	}
 	return c.zw.Close()
 }
 
@@ -69,6 +69,52 @@ func TestCompressWriter_EmptyData(t *testing.T) {
 	assert.Equal(t, []byte{}, decompressedData)
 }
 
+func TestCompressWriter_ContentLength(t *testing.T) {
+	recorder := httptest.NewRecorder()
+
+	cw := NewCompressWriter(recorder)
+	cw.Header().Set("Content-Length", "9")
+
+	cw.WriteHeader(http.StatusOK)
+
+	assert.Empty(t, recorder.Header().Get("Content-Length"))
+}
+
+func TestCompressWriter_Flush(t *testing.T) {
+	recorder := httptest.NewRecorder()
+
+	cw := NewCompressWriter(recorder)
+
+	_, err := cw.Write([]byte("event"))
+	assert.NoError(t, err)
+	assert.NoError(t, http.NewResponseController(cw).Flush())
+	assert.True(t, recorder.Flushed)
+
+	gr, err := gzip.NewReader(bytes.NewReader(recorder.Body.Bytes()))
+	assert.NoError(t, err)
+
+	data := make([]byte, 5)
+	_, err = io.ReadFull(gr, data)
+	assert.NoError(t, err)
+	assert.Equal(t, "event", string(data))
+}
+
+func TestCompressWriter_ErrorStatus(t *testing.T) {
+	recorder := httptest.NewRecorder()
+
+	cw := NewCompressWriter(recorder)
+
+	cw.WriteHeader(http.StatusBadRequest)
+	_, err := cw.Write([]byte("bad request"))
+	assert.NoError(t, err)
+
+	err = cw.Close()
+	assert.NoError(t, err)
+
+	assert.Empty(t, recorder.Header().Get("Content-Encoding"))
+	assert.Equal(t, "bad request", recorder.Body.String())
+}
+
 func TestCompressReader(t *testing.T) {
 	data := []byte("test data")
 
@@ -0,0 +1,225 @@
+package handlers
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"net/http"
+
+	"github.com/frolmr/metrics/internal/domain"
+	"github.com/frolmr/metrics/internal/server/apierror"
+	"github.com/frolmr/metrics/internal/server/storage"
+	"github.com/frolmr/metrics/pkg/formatter"
+)
+
+const (
+	// CSVContentType is the media type of CSV export.
+	CSVContentType = "text/csv"
+	// NDJSONContentType is the media type of newline delimited JSON export.
+	NDJSONContentType = "application/x-ndjson"
+)
+
+// Export formats accepted by format query parameter.
+const (
+	ExportFormatCSV    = "csv"
+	ExportFormatNDJSON = "ndjson"
+	ExportFormatJSON   = "json"
+)
+
+// ExportHandler dumps all metrics in formats suitable for spreadsheets and scripts.
+type ExportHandler struct {
+	repo storage.Repository
+}
+
+// NewExportHandler function is constructor for export handler.
+func NewExportHandler(repo storage.Repository) *ExportHandler {
+	return &ExportHandler{
+		repo: repo,
+	}
+}
+
+// exportEncoder writes metrics one by one between begin and end of the document.
+type exportEncoder interface {
+	begin() error
+	encode(m domain.Metrics) error
+	end() error
+}
+
+// Export streams current metric values page by page, so memory use doesn't depend on the number of metrics.
+// Metrics updated while export is running may appear with either value.
+// @Summary Export metrics
+// @Description Streams all metrics as CSV with id, type and value columns, as newline delimited domain.Metrics JSON
+// @Description or as JSON array of domain.Metrics which is accepted by /api/v1/updates.
+// @Description NaN and Inf gauges are written to CSV only, JSON formats skip them.
+// @Description Connection is aborted if storage fails in the middle of export.
+// @Tags metrics
+// @Produce text/csv,application/x-ndjson,json
+// @Param format query string false "Export format: csv, ndjson or json" default(json)
+// @Param type query string false "Type of the metrics (gauge or counter)"
+// @Param prefix query string false "Name prefix"
+// @Param sort query string false "Sort order: name, -name, type or -type" default(name)
+// @Success 200 {array} domain.Metrics "Metrics in requested format"
+// @Failure 400 {object} apierror.Error "Invalid query"
+// @Failure 500 {object} apierror.Error "Internal server error"
+// @Router /api/v1/export [get]
+func (eh *ExportHandler) Export() http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		values := req.URL.Query()
+
+		format := values.Get("format")
+		if format == "" {
+			format = ExportFormatJSON
+		}
+
+		query := domain.MetricsQuery{
+			MType:  values.Get("type"),
+			Prefix: values.Get("prefix"),
+			Sort:   domain.SortOrder(values.Get("sort")),
+			Limit:  domain.MaxListLimit,
+		}
+		if err := query.Validate(); err != nil {
+			apierror.Write(res, req, http.StatusBadRequest, apierror.CodeBadRequest, err.Error(), nil)
+			return
+		}
+
+		buf := bufio.NewWriter(res)
+		var (
+			enc         exportEncoder
+			contentType string
+		)
+		switch format {
+		case ExportFormatCSV:
+			enc, contentType = &csvEncoder{w: csv.NewWriter(buf)}, CSVContentType
+		case ExportFormatNDJSON:
+			enc, contentType = &jsonEncoder{w: buf, lines: true}, NDJSONContentType
+		case ExportFormatJSON:
+			enc, contentType = &jsonEncoder{w: buf}, domain.JSONContentType
+		default:
+			apierror.Write(res, req, http.StatusBadRequest, apierror.CodeBadRequest, "unknown export format "+format, nil)
+			return
+		}
+
+		// NOTE: the first page is read before response starts, so that storage failure can still be reported as 500
+		metrics, err := eh.repo.ListMetrics(query)
+		if err != nil {
+			apierror.Write(res, req, http.StatusInternalServerError, apierror.CodeInternal, err.Error(), nil)
+			return
+		}
+
+		res.Header().Set("Content-Type", contentType)
+		res.Header().Set("Content-Disposition", `attachment; filename="metrics.`+format+`"`)
+		res.WriteHeader(http.StatusOK)
+
+		rc := http.NewResponseController(res)
+		if err := enc.begin(); err != nil {
+			return
+		}
+		for {
+			for _, m := range metrics {
+				if err := enc.encode(m); err != nil {
+					return
+				}
+			}
+			if len(metrics) < query.Limit {
+				break
+			}
+
+			if err := buf.Flush(); err != nil {
+				return
+			}
+			if err := rc.Flush(); err != nil && !errors.Is(err, http.ErrNotSupported) {
+				return
+			}
+
+			last := metrics[len(metrics)-1]
+			query.After = &domain.MetricKey{ID: last.ID, MType: last.MType}
+			if metrics, err = eh.repo.ListMetrics(query); err != nil {
+				// NOTE: status is already sent, aborting connection is the only way to tell client that export is incomplete
+				panic(http.ErrAbortHandler)
+			}
+		}
+
+		if err := enc.end(); err != nil {
+			return
+		}
+		_ = buf.Flush()
+	}
+}
+
+type csvEncoder struct {
+	w *csv.Writer
+}
+
+func (e *csvEncoder) begin() error {
+	return e.w.Write([]string{"id", "type", "value"})
+}
+
+func (e *csvEncoder) encode(m domain.Metrics) error {
+	var value string
+	switch {
+	case m.Delta != nil:
+		value = formatter.IntToString(*m.Delta)
+	case m.Value != nil:
+		value = formatter.FloatToString(*m.Value)
+	}
+	if err := e.w.Write([]string{m.ID, m.MType, value}); err != nil {
+		return err
+	}
+	// NOTE: csv writer buffers on its own, flushing it hands records to the response buffer
+	e.w.Flush()
+	return e.w.Error()
+}
+
+func (e *csvEncoder) end() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+// jsonEncoder writes metrics as JSON array or, if lines is set, as one JSON object per line.
+type jsonEncoder struct {
+	w     io.Writer
+	lines bool
+	count int
+}
+
+func (e *jsonEncoder) begin() error {
+	if e.lines {
+		return nil
+	}
+	_, err := io.WriteString(e.w, "[")
+	return err
+}
+
+func (e *jsonEncoder) encode(m domain.Metrics) error {
+	// NOTE: NaN and Inf gauges allowed by validation rules can't be written as JSON, they are skipped
+	if m.Value != nil && (math.IsNaN(*m.Value) || math.IsInf(*m.Value, 0)) {
+		return nil
+	}
+
+	data, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+
+	if !e.lines && e.count > 0 {
+		data = append([]byte(",\n"), data...)
+	}
+	if e.lines {
+		data = append(data, '\n')
+	}
+	e.count++
+
+	_, err = e.w.Write(data)
+	return err
+}
+
+func (e *jsonEncoder) end() error {
+	if e.lines {
+		return nil
+	}
+	_, err := io.WriteString(e.w, "]\n")
+	return err
+}
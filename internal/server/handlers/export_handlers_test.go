@@ -0,0 +1,183 @@
+package handlers
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/frolmr/metrics/internal/domain"
+	"github.com/frolmr/metrics/internal/server/mocks"
+	"github.com/frolmr/metrics/internal/server/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func getExport(t *testing.T, ts *httptest.Server, query string) (*http.Response, string) {
+	t.Helper()
+
+	resp, err := ts.Client().Get(ts.URL + "?" + query)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp, string(body)
+}
+
+func TestExport(t *testing.T) {
+	ms := storage.NewMemStorage()
+	require.NoError(t, ms.UpdateCounterMetric("hits", 7))
+	require.NoError(t, ms.UpdateGaugeMetric("load", 0.25))
+	require.NoError(t, ms.UpdateGaugeMetric("a,b", 1))
+
+	ts := httptest.NewServer(NewExportHandler(ms).Export())
+	defer ts.Close()
+
+	t.Run("csv", func(t *testing.T) {
+		resp, body := getExport(t, ts, "format=csv")
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, CSVContentType, resp.Header.Get("Content-Type"))
+		assert.Contains(t, resp.Header.Get("Content-Disposition"), "metrics.csv")
+
+		records, err := csv.NewReader(strings.NewReader(body)).ReadAll()
+		require.NoError(t, err)
+		assert.Equal(t, [][]string{
+			{"id", "type", "value"},
+			{"a,b", "gauge", "1"},
+			{"hits", "counter", "7"},
+			{"load", "gauge", "0.25"},
+		}, records)
+	})
+
+	t.Run("ndjson", func(t *testing.T) {
+		resp, body := getExport(t, ts, "format=ndjson&type=gauge")
+		assert.Equal(t, NDJSONContentType, resp.Header.Get("Content-Type"))
+		assert.Equal(t, "{\"id\":\"a,b\",\"type\":\"gauge\",\"value\":1}\n{\"id\":\"load\",\"type\":\"gauge\",\"value\":0.25}\n", body)
+	})
+
+	t.Run("json", func(t *testing.T) {
+		resp, body := getExport(t, ts, "prefix=hi")
+		assert.Equal(t, domain.JSONContentType, resp.Header.Get("Content-Type"))
+		assert.JSONEq(t, `[{"id":"hits","type":"counter","delta":7}]`, body)
+
+		_, body = getExport(t, ts, "prefix=none")
+		assert.JSONEq(t, `[]`, body)
+	})
+
+	t.Run("invalid query", func(t *testing.T) {
+		resp, _ := getExport(t, ts, "format=xml")
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+		resp, _ = getExport(t, ts, "type=histogram")
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
+
+func TestExport_NonFinite(t *testing.T) {
+	ms := storage.NewMemStorage()
+	ms.GaugeMetrics["broken"] = math.NaN()
+	ms.GaugeMetrics["huge"] = math.Inf(1)
+	ms.GaugeMetrics["load"] = 0.25
+
+	ts := httptest.NewServer(NewExportHandler(ms).Export())
+	defer ts.Close()
+
+	resp, body := getExport(t, ts, "format=json")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	var metrics []domain.Metrics
+	require.NoError(t, json.Unmarshal([]byte(body), &metrics), "NaN and Inf are skipped, not cut the dump off")
+	require.Len(t, metrics, 1)
+	assert.Equal(t, "load", metrics[0].ID)
+
+	resp, body = getExport(t, ts, "format=ndjson")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, `{"id":"load","type":"gauge","value":0.25}`+"\n", body)
+
+	resp, body = getExport(t, ts, "format=csv")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, body, "broken,gauge,NaN")
+	assert.Contains(t, body, "load,gauge,0.25")
+}
+
+func TestExport_Pages(t *testing.T) {
+	ms := storage.NewMemStorage()
+	total := domain.MaxListLimit*2 + 1
+	for i := 0; i < total; i++ {
+		require.NoError(t, ms.UpdateGaugeMetric(fmt.Sprintf("m%05d", i), float64(i)))
+	}
+
+	ts := httptest.NewServer(NewExportHandler(ms).Export())
+	defer ts.Close()
+
+	resp, err := ts.Client().Get(ts.URL + "?format=ndjson")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	var (
+		count int
+		prev  string
+	)
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		var m domain.Metrics
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &m))
+		assert.Greater(t, m.ID, prev)
+		prev = m.ID
+		count++
+	}
+	require.NoError(t, scanner.Err())
+	assert.Equal(t, total, count)
+}
+
+func TestExport_StorageError(t *testing.T) {
+	t.Run("before response", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := mocks.NewMockRepository(ctrl)
+		mockRepo.EXPECT().ListMetrics(gomock.Any()).Return(nil, errors.New("db is down"))
+
+		ts := httptest.NewServer(NewExportHandler(mockRepo).Export())
+		defer ts.Close()
+
+		resp, _ := getExport(t, ts, "format=csv")
+		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+	})
+
+	t.Run("in the middle", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		page := make([]domain.Metrics, domain.MaxListLimit)
+		for i := range page {
+			delta := int64(i)
+			page[i] = domain.Metrics{ID: fmt.Sprintf("m%05d", i), MType: domain.CounterType, Delta: &delta}
+		}
+
+		mockRepo := mocks.NewMockRepository(ctrl)
+		gomock.InOrder(
+			mockRepo.EXPECT().ListMetrics(gomock.Any()).Return(page, nil),
+			mockRepo.EXPECT().ListMetrics(gomock.Any()).Return(nil, errors.New("db is down")),
+		)
+
+		ts := httptest.NewServer(NewExportHandler(mockRepo).Export())
+		defer ts.Close()
+
+		resp, err := ts.Client().Get(ts.URL + "?format=json")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		_, err = io.ReadAll(resp.Body)
+		assert.Error(t, err, "client must see that export is incomplete")
+	})
+}
@@ -2,22 +2,33 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/frolmr/metrics/internal/domain"
+	"github.com/frolmr/metrics/internal/server/apierror"
 	"github.com/frolmr/metrics/internal/server/storage"
 	"github.com/frolmr/metrics/pkg/formatter"
 	"github.com/go-chi/chi/v5"
 )
 
+var errWrongMetricValue = errors.New("Wrong metric value")
+
 type RequestHandler struct {
-	repo storage.Repository
+	repo      storage.Repository
+	validator *domain.Validator
 }
 
-// NewRequestHandler function is the constructor for handler object that has methods for hadnling requests for app
-func NewRequestHandler(repo storage.Repository) *RequestHandler {
+// NewRequestHandler function is the constructor for handler object that has methods for hadnling requests for app,
+// metrics are validated with default rules if validator is nil.
+func NewRequestHandler(repo storage.Repository, validator *domain.Validator) *RequestHandler {
+	if validator == nil {
+		validator = domain.DefaultValidator()
+	}
+
 	return &RequestHandler{
-		repo: repo,
+		repo:      repo,
+		validator: validator,
 	}
 }
 
@@ -28,6 +39,7 @@ type MetricsRequester interface {
 	GetMetric() http.HandlerFunc
 	GetMetricJSON() http.HandlerFunc
 	GetMetrics() http.HandlerFunc
+	ListMetrics() http.HandlerFunc
 }
 
 // Ping godoc
@@ -35,29 +47,43 @@ type MetricsRequester interface {
 // @Summary Request for API health check
 // @Success 200
 // @Failure 500
+// @Failure 503 {object} apierror.Error "Storage unavailable"
 // @Router /ping [get]
+// @Router /api/v1/ping [get]
 func (rh *RequestHandler) Ping() http.HandlerFunc {
 	return func(res http.ResponseWriter, req *http.Request) {
 		if err := rh.repo.Ping(); err != nil {
-			http.Error(res, "DB unavailable", http.StatusInternalServerError)
+			// NOTE: legacy route answers 500 as autotests expect
+			if apierror.IsAPIRequest(req) {
+				apierror.Write(res, req, http.StatusServiceUnavailable, apierror.CodeUnavailable, "DB unavailable", nil)
+			} else {
+				apierror.Write(res, req, http.StatusInternalServerError, apierror.CodeInternal, "DB unavailable", nil)
+			}
 			return
 		}
+
+		if apierror.IsAPIRequest(req) {
+			writeJSON(res, req, map[string]string{"status": "ok"})
+		}
 	}
 }
 
 // UpdateMetric updates a metric based on the provided URL parameters.
 // @Summary Update a metric
 // @Description Updates a metric with the provided type, name, and value in the URL path.
+// @Description Versioned route responds with updated metric as JSON.
 // @Tags metrics
 // @Accept plain
 // @Produce plain
+// @Produce json
 // @Param type path string true "Type of the metric (gauge or counter)"
 // @Param name path string true "Name of the metric"
 // @Param value path string true "Value of the metric"
 // @Success 200 {string} string "Metric updated successfully"
-// @Failure 400 {string} string "Invalid metric type or value"
-// @Failure 500 {string} string "Internal server error"
+// @Failure 400 {object} apierror.Error "Invalid metric type or value"
+// @Failure 500 {object} apierror.Error "Internal server error"
 // @Router /update/{type}/{name}/{value} [post]
+// @Router /api/v1/update/{type}/{name}/{value} [post]
 func (rh *RequestHandler) UpdateMetric() http.HandlerFunc {
 	return func(res http.ResponseWriter, req *http.Request) {
 		res.Header().Set("content-type", domain.TextContentType)
@@ -65,7 +91,7 @@ func (rh *RequestHandler) UpdateMetric() http.HandlerFunc {
 		metricType := chi.URLParam(req, "type")
 
 		if metricType != domain.GaugeType && metricType != domain.CounterType {
-			http.Error(res, "Wrong metric type", http.StatusBadRequest)
+			apierror.Write(res, req, http.StatusBadRequest, apierror.CodeBadRequest, "Wrong metric type", nil)
 			return
 		}
 
@@ -73,12 +99,22 @@ func (rh *RequestHandler) UpdateMetric() http.HandlerFunc {
 		metricValue := chi.URLParam(req, "value")
 
 		if err := rh.updateMetric(metricName, metricType, metricValue); err != nil {
-			http.Error(res, "Wrong metric value", http.StatusBadRequest)
+			writeUpdateError(res, req, err)
+			return
+		}
+
+		if apierror.IsAPIRequest(req) {
+			metricResponse, err := rh.prepareMetricsResponse(metricName, metricType)
+			if err != nil {
+				apierror.Write(res, req, http.StatusInternalServerError, apierror.CodeInternal, err.Error(), nil)
+				return
+			}
+			writeJSON(res, req, metricResponse)
 			return
 		}
 
 		if _, err := res.Write([]byte("Metric: " + metricName + " value: " + metricValue + " has added")); err != nil {
-			http.Error(res, err.Error(), http.StatusInternalServerError)
+			apierror.Write(res, req, http.StatusInternalServerError, apierror.CodeInternal, err.Error(), nil)
 			return
 		}
 	}
@@ -92,38 +128,60 @@ func (rh *RequestHandler) UpdateMetric() http.HandlerFunc {
 // @Produce plain
 // @Param type path string true "Type of the metric"
 // @Param name path string true "Name of the metric"
+// @Produce json
 // @Success 200 {string} string "The value of the metric"
-// @Failure 400 {string} string "Invalid request"
-// @Failure 404 {string} string "Metric not found"
-// @Router /metrics/{type}/{name} [get]
+// @Failure 400 {object} apierror.Error "Invalid request"
+// @Failure 404 {object} apierror.Error "Metric not found"
+// @Failure 406 {object} apierror.Error "Neither JSON nor plain text is acceptable"
+// @Failure 500 {object} apierror.Error "Internal server error"
+// @Router /value/{type}/{name} [get]
+// @Router /api/v1/value/{type}/{name} [get]
 func (rh *RequestHandler) GetMetric() http.HandlerFunc {
 	return func(res http.ResponseWriter, req *http.Request) {
-		res.Header().Set("content-type", domain.TextContentType)
+		contentType := domain.TextContentType
+		if apierror.IsAPIRequest(req) {
+			contentType = apierror.Negotiate(req, domain.JSONContentType, domain.TextContentType)
+			if contentType == "" {
+				apierror.Write(res, req, http.StatusNotAcceptable, apierror.CodeNotAcceptable,
+					"metric can be returned as "+domain.JSONContentType+" or "+domain.TextContentType, nil)
+				return
+			}
+		}
+		res.Header().Set("content-type", contentType)
 
 		metricType := chi.URLParam(req, "type")
 		metricName := chi.URLParam(req, "name")
 
-		switch metricType {
-		case domain.CounterType:
-			if value, err := rh.repo.GetCounterMetric(metricName); err != nil {
-				http.Error(res, "Metric Not Found", http.StatusNotFound)
-			} else {
-				if _, err := res.Write([]byte(formatter.IntToString(value))); err != nil {
-					http.Error(res, err.Error(), http.StatusInternalServerError)
-					return
-				}
-			}
-		case domain.GaugeType:
-			if value, err := rh.repo.GetGaugeMetric(metricName); err != nil {
-				http.Error(res, "Metric Not Found", http.StatusNotFound)
-			} else {
-				if _, err := res.Write([]byte(formatter.FloatToString(value))); err != nil {
-					http.Error(res, err.Error(), http.StatusInternalServerError)
-					return
-				}
-			}
-		default:
-			http.Error(res, "Wrong metric type", http.StatusBadRequest)
+		if metricType != domain.GaugeType && metricType != domain.CounterType {
+			apierror.Write(res, req, http.StatusBadRequest, apierror.CodeBadRequest, "Wrong metric type", nil)
+			return
+		}
+
+		metricResponse, err := rh.prepareMetricsResponse(metricName, metricType)
+		if errors.Is(err, storage.ErrMetricNotFound) {
+			apierror.Write(res, req, http.StatusNotFound, apierror.CodeNotFound, "Metric Not Found", nil)
+			return
+		}
+		if err != nil {
+			apierror.Write(res, req, http.StatusInternalServerError, apierror.CodeInternal, "error reading metric", nil)
+			return
+		}
+
+		if contentType == domain.JSONContentType {
+			writeJSON(res, req, metricResponse)
+			return
+		}
+
+		var value string
+		if metricType == domain.CounterType {
+			value = formatter.IntToString(*metricResponse.Delta)
+		} else {
+			value = formatter.FloatToString(*metricResponse.Value)
+		}
+
+		if _, err := res.Write([]byte(value)); err != nil {
+			apierror.Write(res, req, http.StatusInternalServerError, apierror.CodeInternal, err.Error(), nil)
+			return
 		}
 	}
 }
@@ -148,26 +206,26 @@ func (rh *RequestHandler) GetMetrics() http.HandlerFunc {
 
 		counterMetrics, err := rh.repo.GetCounterMetrics()
 		if err != nil {
-			http.Error(res, err.Error(), http.StatusInternalServerError)
+			apierror.Write(res, req, http.StatusInternalServerError, apierror.CodeInternal, err.Error(), nil)
 			return
 		}
 
 		gaugeMetrics, err := rh.repo.GetGaugeMetrics()
 		if err != nil {
-			http.Error(res, err.Error(), http.StatusInternalServerError)
+			apierror.Write(res, req, http.StatusInternalServerError, apierror.CodeInternal, err.Error(), nil)
 			return
 		}
 
 		for name, value := range counterMetrics {
 			if _, err := res.Write([]byte(name + " " + formatter.IntToString(value) + "\n")); err != nil {
-				http.Error(res, err.Error(), http.StatusInternalServerError)
+				apierror.Write(res, req, http.StatusInternalServerError, apierror.CodeInternal, err.Error(), nil)
 				return
 			}
 		}
 
 		for name, value := range gaugeMetrics {
 			if _, err := res.Write([]byte(name + " " + formatter.FloatToString(value) + "\n")); err != nil {
-				http.Error(res, err.Error(), http.StatusInternalServerError)
+				apierror.Write(res, req, http.StatusInternalServerError, apierror.CodeInternal, err.Error(), nil)
 				return
 			}
 		}
@@ -175,24 +233,42 @@ func (rh *RequestHandler) GetMetrics() http.HandlerFunc {
 }
 
 func (rh *RequestHandler) updateMetric(metricName, metricType, metricValue string) error {
-	if metricType == domain.GaugeType {
+	metric := domain.Metrics{ID: metricName, MType: metricType}
+
+	switch metricType {
+	case domain.GaugeType:
 		value, err := formatter.StringToFloat(metricValue)
 		if err != nil {
-			return err
-		}
-		if err := rh.repo.UpdateGaugeMetric(metricName, value); err != nil {
-			return err
+			return errWrongMetricValue
 		}
-	}
-
-	if metricType == domain.CounterType {
+		metric.Value = &value
+	case domain.CounterType:
 		value, err := formatter.StringToInt(metricValue)
 		if err != nil {
-			return err
-		}
-		if err := rh.repo.UpdateCounterMetric(metricName, value); err != nil {
-			return err
+			return errWrongMetricValue
 		}
+		metric.Delta = &value
+	}
+
+	metric, err := rh.validator.ValidateUpdate(metric, rh.repo.GetCounterMetric)
+	if err != nil {
+		return err
+	}
+
+	if metricType == domain.GaugeType {
+		return rh.repo.UpdateGaugeMetric(metricName, *metric.Value)
+	}
+	return rh.repo.UpdateCounterMetric(metricName, *metric.Delta)
+}
+
+// writeUpdateError tells client errors from storage failures.
+func writeUpdateError(res http.ResponseWriter, req *http.Request, err error) {
+	switch {
+	case errors.Is(err, errWrongMetricValue):
+		apierror.Write(res, req, http.StatusBadRequest, apierror.CodeBadRequest, err.Error(), nil)
+	case domain.IsValidationError(err):
+		apierror.Write(res, req, http.StatusBadRequest, apierror.CodeValidationFailed, err.Error(), nil)
+	default:
+		apierror.Write(res, req, http.StatusInternalServerError, apierror.CodeInternal, "error updating metric", nil)
 	}
-	return nil
 }
@@ -6,6 +6,7 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/frolmr/metrics/internal/server/mocks"
@@ -35,12 +36,12 @@ func testRequest(t *testing.T, ts *httptest.Server, method, path string, content
 }
 
 func TestMetricsUpdate(t *testing.T) {
-	ms := storage.MemStorage{
+	ms := &storage.MemStorage{
 		CounterMetrics: make(map[string]int64),
 		GaugeMetrics:   make(map[string]float64),
 	}
 
-	rh := NewRequestHandler(ms)
+	rh := NewRequestHandler(ms, nil)
 
 	r := chi.NewRouter()
 	r.Post("/update/{type}/{name}/{value}", rh.UpdateMetric())
@@ -112,6 +113,42 @@ func TestMetricsUpdate(t *testing.T) {
 				statusCode: http.StatusBadRequest,
 			},
 		},
+		{
+			name:        "fail too long metric name request",
+			path:        "/update/gauge/" + strings.Repeat("a", 51) + "/25",
+			method:      http.MethodPost,
+			contentType: "text/plain",
+			want: want{
+				statusCode: http.StatusBadRequest,
+			},
+		},
+		{
+			name:        "fail invalid metric name request",
+			path:        "/update/gauge/te%20st/25",
+			method:      http.MethodPost,
+			contentType: "text/plain",
+			want: want{
+				statusCode: http.StatusBadRequest,
+			},
+		},
+		{
+			name:        "fail NaN gauge request",
+			path:        "/update/gauge/test/NaN",
+			method:      http.MethodPost,
+			contentType: "text/plain",
+			want: want{
+				statusCode: http.StatusBadRequest,
+			},
+		},
+		{
+			name:        "fail counter overflow request",
+			path:        "/update/counter/test/9223372036854775807",
+			method:      http.MethodPost,
+			contentType: "text/plain",
+			want: want{
+				statusCode: http.StatusBadRequest,
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -121,12 +158,12 @@ func TestMetricsUpdate(t *testing.T) {
 }
 
 func TestGetMetricHandler(t *testing.T) {
-	ms := storage.MemStorage{
+	ms := &storage.MemStorage{
 		CounterMetrics: map[string]int64{"cTest1": 200, "cTest2": 128},
 		GaugeMetrics:   map[string]float64{"gTest1": 2.12, "gTest2": 0.54},
 	}
 
-	rh := NewRequestHandler(ms)
+	rh := NewRequestHandler(ms, nil)
 
 	r := chi.NewRouter()
 	r.Get("/value/{type}/{name}", rh.GetMetric())
@@ -215,11 +252,11 @@ func TestGetMetricHandler(t *testing.T) {
 }
 
 func TestGetMetricsHandler(t *testing.T) {
-	ms := storage.MemStorage{
+	ms := &storage.MemStorage{
 		CounterMetrics: map[string]int64{"cTest1": 200, "cTest2": 128},
 		GaugeMetrics:   map[string]float64{"gTest1": 2.12, "gTest2": 0.54},
 	}
-	rh := NewRequestHandler(ms)
+	rh := NewRequestHandler(ms, nil)
 
 	r := chi.NewRouter()
 	r.Use(middleware.ContentCharset("UTF-8"))
@@ -260,11 +297,11 @@ func TestGetMetricsHandler(t *testing.T) {
 }
 
 func TestPingHandler(t *testing.T) {
-	ms := storage.MemStorage{
+	ms := &storage.MemStorage{
 		CounterMetrics: map[string]int64{},
 		GaugeMetrics:   map[string]float64{},
 	}
-	rh := NewRequestHandler(ms)
+	rh := NewRequestHandler(ms, nil)
 
 	r := chi.NewRouter()
 	r.Use(middleware.ContentCharset("UTF-8"))
@@ -304,12 +341,17 @@ func TestUpdateMetric_RepoError(t *testing.T) {
 		Return(errors.New("repo error")).
 		Times(1)
 
+	mockRepo.EXPECT().
+		GetCounterMetric("test").
+		Return(int64(0), errors.New("value not found")).
+		Times(1)
+
 	mockRepo.EXPECT().
 		UpdateCounterMetric(gomock.Any(), gomock.Any()).
 		Return(errors.New("repo error")).
 		Times(1)
 
-	rh := NewRequestHandler(mockRepo)
+	rh := NewRequestHandler(mockRepo, nil)
 
 	r := chi.NewRouter()
 	r.Post("/update/{type}/{name}/{value}", rh.UpdateMetric())
@@ -329,14 +371,14 @@ func TestUpdateMetric_RepoError(t *testing.T) {
 			path:        "/update/gauge/test/25",
 			method:      http.MethodPost,
 			contentType: "text/plain",
-			want:        http.StatusBadRequest,
+			want:        http.StatusInternalServerError,
 		},
 		{
 			name:        "repo error for counter",
 			path:        "/update/counter/test/25",
 			method:      http.MethodPost,
 			contentType: "text/plain",
-			want:        http.StatusBadRequest,
+			want:        http.StatusInternalServerError,
 		},
 	}
 
@@ -361,10 +403,10 @@ func TestGetMetric_RepoError(t *testing.T) {
 
 	mockRepo.EXPECT().
 		GetGaugeMetric(gomock.Any()).
-		Return(float64(0), errors.New("repo error")).
+		Return(float64(0), storage.ErrMetricNotFound).
 		Times(1)
 
-	rh := NewRequestHandler(mockRepo)
+	rh := NewRequestHandler(mockRepo, nil)
 
 	r := chi.NewRouter()
 	r.Get("/value/{type}/{name}", rh.GetMetric())
@@ -380,7 +422,7 @@ func TestGetMetric_RepoError(t *testing.T) {
 		want        int
 	}{
 		{
-			name:        "repo error for gauge",
+			name:        "missing gauge",
 			path:        "/value/gauge/test",
 			method:      http.MethodGet,
 			contentType: "text/plain;charset=utf-8",
@@ -391,7 +433,7 @@ func TestGetMetric_RepoError(t *testing.T) {
 			path:        "/value/counter/test",
 			method:      http.MethodGet,
 			contentType: "text/plain;charset=utf-8",
-			want:        http.StatusNotFound,
+			want:        http.StatusInternalServerError,
 		},
 	}
 
@@ -414,7 +456,7 @@ func TestPing_RepoError(t *testing.T) {
 		Return(errors.New("repo error")).
 		Times(1)
 
-	rh := NewRequestHandler(mockRepo)
+	rh := NewRequestHandler(mockRepo, nil)
 
 	r := chi.NewRouter()
 	r.Use(middleware.ContentCharset("UTF-8"))
@@ -459,7 +501,7 @@ func TestGetMetrics_CounterMetricsError(t *testing.T) {
 		Return(nil, errors.New("repo error")).
 		Times(1)
 
-	rh := NewRequestHandler(mockRepo)
+	rh := NewRequestHandler(mockRepo, nil)
 
 	r := chi.NewRouter()
 	r.Use(middleware.ContentCharset("UTF-8"))
@@ -489,7 +531,7 @@ func TestGetMetrics_GaugeMetricsError(t *testing.T) {
 		Return(nil, errors.New("repo error")).
 		Times(1)
 
-	rh := NewRequestHandler(mockRepo)
+	rh := NewRequestHandler(mockRepo, nil)
 
 	r := chi.NewRouter()
 	r.Use(middleware.ContentCharset("UTF-8"))
@@ -505,13 +547,13 @@ func TestGetMetrics_GaugeMetricsError(t *testing.T) {
 
 // ExampleRequestHandler_UpdateMetric demonstrates how to use the UpdateMetric handler.
 func ExampleRequestHandler_UpdateMetric() {
-	ms := storage.MemStorage{
+	ms := &storage.MemStorage{
 		CounterMetrics: make(map[string]int64),
 		GaugeMetrics:   make(map[string]float64),
 	}
 
 	// Create a new RequestHandler
-	rh := NewRequestHandler(ms)
+	rh := NewRequestHandler(ms, nil)
 
 	// Create a new HTTP request with URL parameters
 	req := httptest.NewRequest(http.MethodPost, "/update/gauge/cpu_usage/3.14", nil)
@@ -534,13 +576,13 @@ func ExampleRequestHandler_UpdateMetric() {
 
 // ExampleRequestHandler_GetMetric demonstrates how to use the GetMetric handler.
 func ExampleRequestHandler_GetMetric() {
-	ms := storage.MemStorage{
+	ms := &storage.MemStorage{
 		CounterMetrics: make(map[string]int64),
 		GaugeMetrics:   map[string]float64{"cpu_usage": 3.14, "memory_usage": 2.71},
 	}
 
 	// Create a new RequestHandler
-	rh := NewRequestHandler(ms)
+	rh := NewRequestHandler(ms, nil)
 
 	// Create a new HTTP request with URL parameters
 	req := httptest.NewRequest(http.MethodGet, "/value/gauge/cpu_usage", nil)
@@ -563,13 +605,13 @@ func ExampleRequestHandler_GetMetric() {
 
 // ExampleRequestHandler_GetMetrics demonstrates how to use the GetMetrics handler.
 func ExampleRequestHandler_GetMetrics() {
-	ms := storage.MemStorage{
+	ms := &storage.MemStorage{
 		CounterMetrics: make(map[string]int64),
 		GaugeMetrics:   map[string]float64{"cpu_usage": 3.14, "memory_usage": 2.71},
 	}
 
 	// Create a new RequestHandler
-	rh := NewRequestHandler(ms)
+	rh := NewRequestHandler(ms, nil)
 
 	// Create a new HTTP request
 	req := httptest.NewRequest(http.MethodGet, "/", nil)
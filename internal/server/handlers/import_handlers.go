@@ -0,0 +1,257 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+
+	"github.com/frolmr/metrics/internal/domain"
+	"github.com/frolmr/metrics/internal/server/apierror"
+	"github.com/frolmr/metrics/internal/server/storage"
+)
+
+// Counter modes accepted by counters query parameter of import.
+const (
+	// ImportCountersAdd adds imported values to existing counters, as updates do.
+	ImportCountersAdd = "add"
+	// ImportCountersOverwrite sets counters to imported values, as snapshot restore does.
+	ImportCountersOverwrite = "overwrite"
+)
+
+const (
+	// importChunkSize is the most metrics saved by one UpdateMetrics call.
+	importChunkSize = 1000
+	// maxImportErrors limits rejected metrics listed in import result, the rest are only counted.
+	maxImportErrors = 100
+)
+
+// ImportHandler loads metrics dumps produced by snapshots and export.
+type ImportHandler struct {
+	repo      storage.Repository
+	validator *domain.Validator
+}
+
+// ImportResult tells how many metrics were imported and why the others were rejected.
+// @Description Result of import, metrics before failed one are saved even if import fails.
+type ImportResult struct {
+	// Imported is the number of saved metrics.
+	Imported int `json:"imported"`
+	// Rejected is the number of metrics failed validation.
+	Rejected int `json:"rejected"`
+	// Errors lists the first rejected metrics, index is the position of metric in the dump.
+	Errors []domain.MetricStatus `json:"errors"`
+}
+
+// NewImportHandler function is constructor for import handler, nil validator means default rules.
+func NewImportHandler(repo storage.Repository, validator *domain.Validator) *ImportHandler {
+	if validator == nil {
+		validator = domain.DefaultValidator()
+	}
+	return &ImportHandler{
+		repo:      repo,
+		validator: validator,
+	}
+}
+
+// Import saves metrics of JSON array or NDJSON dump in chunks, so dumps of any size can be imported.
+// Import is not atomic: chunks saved before a failure stay saved.
+// @Summary Import metrics
+// @Description Accepts JSON array of domain.Metrics as written by snapshots and export, or NDJSON with one metric per line.
+// @Description Gauges are overwritten, counters are added to existing values or overwritten depending on counters parameter.
+// @Tags metrics
+// @Accept json,application/x-ndjson
+// @Produce json
+// @Param counters query string false "Counter mode: add or overwrite" default(add)
+// @Param metrics body []domain.Metrics true "Metrics dump"
+// @Success 200 {object} ImportResult "Import result, at least one metric imported"
+// @Failure 400 {object} apierror.Error "Invalid dump or all metrics rejected, details are ImportResult"
+// @Failure 415 {object} apierror.Error "Body is neither JSON nor NDJSON"
+// @Failure 500 {object} apierror.Error "Internal server error, details are ImportResult"
+// @Router /api/v1/import [post]
+func (ih *ImportHandler) Import() http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		isArray := apierror.HasContentType(req, domain.JSONContentType)
+		if !isArray && !apierror.HasContentType(req, NDJSONContentType) {
+			apierror.Write(res, req, http.StatusUnsupportedMediaType, apierror.CodeUnsupportedMediaType,
+				"content type must be "+domain.JSONContentType+" or "+NDJSONContentType, nil)
+			return
+		}
+
+		mode := req.URL.Query().Get("counters")
+		switch mode {
+		case "":
+			mode = ImportCountersAdd
+		case ImportCountersAdd, ImportCountersOverwrite:
+		default:
+			apierror.Write(res, req, http.StatusBadRequest, apierror.CodeBadRequest, "unknown counters mode "+mode, nil)
+			return
+		}
+
+		result := &ImportResult{Errors: make([]domain.MetricStatus, 0)}
+		chunk := make([]domain.Metrics, 0, importChunkSize)
+		offset := 0
+
+		save := func() error {
+			if len(chunk) == 0 {
+				return nil
+			}
+			if err := ih.saveChunk(chunk, offset, mode == ImportCountersOverwrite, result); err != nil {
+				return err
+			}
+			offset += len(chunk)
+			chunk = chunk[:0]
+			return nil
+		}
+
+		var saveErr error
+		decodeErr := ih.decode(req, isArray, func(m domain.Metrics) error {
+			chunk = append(chunk, m)
+			if len(chunk) < importChunkSize {
+				return nil
+			}
+			saveErr = save()
+			return saveErr
+		})
+		// NOTE: metrics decoded before broken one are saved too, so that client can resume from its index
+		if saveErr == nil {
+			saveErr = save()
+		}
+
+		switch {
+		case saveErr != nil:
+			apierror.Write(res, req, http.StatusInternalServerError, apierror.CodeInternal, "error updating metrics", result)
+		case decodeErr != nil:
+			apierror.Write(res, req, http.StatusBadRequest, apierror.CodeBadRequest,
+				fmt.Sprintf("invalid dump at index %d: %s", offset, decodeErr.Error()), result)
+		case result.Imported == 0 && result.Rejected != 0:
+			apierror.Write(res, req, http.StatusBadRequest, apierror.CodeValidationFailed, "all metrics rejected", result)
+		default:
+			writeJSON(res, req, result)
+		}
+	}
+}
+
+// decode passes metrics of the body to fn one by one, error of fn stops decoding and is returned as is.
+func (ih *ImportHandler) decode(req *http.Request, isArray bool, fn func(m domain.Metrics) error) error {
+	dec := json.NewDecoder(req.Body)
+
+	if isArray {
+		token, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		if token != json.Delim('[') {
+			return fmt.Errorf("expected array, got %v", token)
+		}
+	}
+
+	for dec.More() {
+		var m domain.Metrics
+		if err := dec.Decode(&m); err != nil {
+			return err
+		}
+		if err := fn(m); err != nil {
+			return err
+		}
+	}
+
+	if isArray {
+		if _, err := dec.Token(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// saveChunk validates and saves chunk of metrics starting at offset of the dump and adds the outcome to result.
+func (ih *ImportHandler) saveChunk(chunk []domain.Metrics, offset int, overwrite bool, result *ImportResult) error {
+	var (
+		rejected []domain.MetricStatus
+		indexes  []int
+	)
+	if overwrite {
+		var err error
+		chunk, indexes, rejected, err = ih.overwriteCounters(chunk)
+		if err != nil {
+			return err
+		}
+	}
+
+	valid, batch := ih.validator.ValidateBatch(chunk, ih.repo.GetCounterMetric)
+	if len(valid) != 0 {
+		if err := ih.repo.UpdateMetrics(valid); err != nil {
+			return err
+		}
+	}
+
+	for _, status := range batch.Rejected {
+		if indexes != nil {
+			status.Index = indexes[status.Index]
+		}
+		rejected = append(rejected, status)
+	}
+	sort.Slice(rejected, func(i, j int) bool {
+		return rejected[i].Index < rejected[j].Index
+	})
+
+	result.Imported += len(valid)
+	result.Rejected += len(rejected)
+	for _, status := range rejected {
+		if len(result.Errors) == maxImportErrors {
+			break
+		}
+		status.Index += offset
+		result.Errors = append(result.Errors, status)
+	}
+	return nil
+}
+
+// overwriteCounters replaces counter values with deltas to them from current values, indexes map returned
+// metrics to positions in chunk. Counters which value can't be reached without overflow are returned as rejected.
+// Error reading current value fails the whole chunk, as counter can't be set without knowing it.
+func (ih *ImportHandler) overwriteCounters(chunk []domain.Metrics) ([]domain.Metrics, []int, []domain.MetricStatus, error) {
+	var (
+		metrics  = make([]domain.Metrics, 0, len(chunk))
+		indexes  = make([]int, 0, len(chunk))
+		rejected []domain.MetricStatus
+		expected = make(map[string]int64)
+	)
+
+	for i, m := range chunk {
+		if m.MType != domain.CounterType || m.Delta == nil {
+			metrics = append(metrics, m)
+			indexes = append(indexes, i)
+			continue
+		}
+
+		current, ok := expected[m.ID]
+		if !ok {
+			// NOTE: missing counter starts from zero, concurrent updates of the counter may be lost
+			var err error
+			current, err = ih.repo.GetCounterMetric(m.ID)
+			switch {
+			case errors.Is(err, storage.ErrMetricNotFound):
+				current = 0
+			case err != nil:
+				return nil, nil, nil, err
+			}
+		}
+
+		target := *m.Delta
+		if (current > 0 && target < math.MinInt64+current) || (current < 0 && target > math.MaxInt64+current) {
+			rejected = append(rejected, domain.MetricStatus{Index: i, ID: m.ID, MType: m.MType, Reason: domain.ErrCounterOverflow.Error()})
+			continue
+		}
+
+		delta := target - current
+		expected[m.ID] = target
+		m.Delta = &delta
+		metrics = append(metrics, m)
+		indexes = append(indexes, i)
+	}
+
+	return metrics, indexes, rejected, nil
+}
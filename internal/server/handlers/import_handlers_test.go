@@ -0,0 +1,200 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/frolmr/metrics/internal/domain"
+	"github.com/frolmr/metrics/internal/server/apierror"
+	"github.com/frolmr/metrics/internal/server/mocks"
+	"github.com/frolmr/metrics/internal/server/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func postImport(t *testing.T, ts *httptest.Server, query, contentType, body string) (*http.Response, []byte) {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodPost, ts.URL+"?"+query, strings.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("Accept", domain.JSONContentType)
+
+	resp, err := ts.Client().Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp, respBody
+}
+
+type importError struct {
+	Code    string       `json:"code"`
+	Message string       `json:"message"`
+	Details ImportResult `json:"details"`
+}
+
+func TestImport(t *testing.T) {
+	t.Run("snapshot format", func(t *testing.T) {
+		ms := storage.NewMemStorage()
+		ms.CounterMetrics["hits"] = 5
+		ts := httptest.NewServer(NewImportHandler(ms, nil).Import())
+		defer ts.Close()
+
+		body := `[
+ {"id":"hits","type":"counter","delta":3},
+ {"id":"load","type":"gauge","value":0.5},
+ {"id":"","type":"gauge","value":1}
+]`
+		resp, respBody := postImport(t, ts, "", domain.JSONContentType, body)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var result ImportResult
+		require.NoError(t, json.Unmarshal(respBody, &result))
+		assert.Equal(t, 2, result.Imported)
+		assert.Equal(t, 1, result.Rejected)
+		require.Len(t, result.Errors, 1)
+		assert.Equal(t, 2, result.Errors[0].Index)
+
+		assert.Equal(t, int64(8), ms.CounterMetrics["hits"], "counters are added by default")
+		assert.InDelta(t, 0.5, ms.GaugeMetrics["load"], 1e-9)
+	})
+
+	t.Run("ndjson with overwrite", func(t *testing.T) {
+		ms := storage.NewMemStorage()
+		ms.CounterMetrics["hits"] = 5
+		ms.CounterMetrics["low"] = -10
+		ts := httptest.NewServer(NewImportHandler(ms, nil).Import())
+		defer ts.Close()
+
+		body := fmt.Sprintf(`{"id":"hits","type":"counter","delta":2}
+
+{"id":"low","type":"counter","delta":%d}
+{"id":"fresh","type":"counter","delta":4}
+{"id":"hits","type":"counter","delta":3}
+`, int64(math.MaxInt64))
+		resp, respBody := postImport(t, ts, "counters=overwrite", NDJSONContentType, body)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var result ImportResult
+		require.NoError(t, json.Unmarshal(respBody, &result))
+		assert.Equal(t, 3, result.Imported)
+		require.Len(t, result.Errors, 1)
+		assert.Equal(t, 1, result.Errors[0].Index)
+		assert.Equal(t, "low", result.Errors[0].ID)
+
+		assert.Equal(t, int64(3), ms.CounterMetrics["hits"], "the last value wins")
+		assert.Equal(t, int64(4), ms.CounterMetrics["fresh"])
+		assert.Equal(t, int64(-10), ms.CounterMetrics["low"])
+	})
+
+	t.Run("chunks", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var chunks []int
+		mockRepo := mocks.NewMockRepository(ctrl)
+		mockRepo.EXPECT().UpdateMetrics(gomock.Any()).DoAndReturn(func(metrics []domain.Metrics) error {
+			chunks = append(chunks, len(metrics))
+			return nil
+		}).Times(3)
+
+		ts := httptest.NewServer(NewImportHandler(mockRepo, nil).Import())
+		defer ts.Close()
+
+		var body strings.Builder
+		for i := 0; i < importChunkSize*2+1; i++ {
+			fmt.Fprintf(&body, "{\"id\":\"m%d\",\"type\":\"gauge\",\"value\":%d}\n", i, i)
+		}
+
+		resp, _ := postImport(t, ts, "", NDJSONContentType, body.String())
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, []int{importChunkSize, importChunkSize, 1}, chunks)
+	})
+
+	t.Run("broken dump", func(t *testing.T) {
+		ms := storage.NewMemStorage()
+		ts := httptest.NewServer(NewImportHandler(ms, nil).Import())
+		defer ts.Close()
+
+		body := `[{"id":"load","type":"gauge","value":0.5},{"id":"x","type":"gauge","value":"oops"}]`
+		resp, respBody := postImport(t, ts, "", domain.JSONContentType, body)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+		var apiErr importError
+		require.NoError(t, json.Unmarshal(respBody, &apiErr))
+		assert.Equal(t, apierror.CodeBadRequest, apiErr.Code)
+		assert.Contains(t, apiErr.Message, "index 1")
+		assert.Equal(t, 1, apiErr.Details.Imported)
+		assert.InDelta(t, 0.5, ms.GaugeMetrics["load"], 1e-9, "metrics before broken one are saved")
+
+		resp, _ = postImport(t, ts, "", domain.JSONContentType, `{"id":"load"}`)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode, "snapshot is an array")
+	})
+
+	t.Run("all rejected", func(t *testing.T) {
+		ts := httptest.NewServer(NewImportHandler(storage.NewMemStorage(), nil).Import())
+		defer ts.Close()
+
+		resp, respBody := postImport(t, ts, "", domain.JSONContentType, `[{"id":"x","type":"histogram"}]`)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+		var apiErr importError
+		require.NoError(t, json.Unmarshal(respBody, &apiErr))
+		assert.Equal(t, apierror.CodeValidationFailed, apiErr.Code)
+		assert.Equal(t, 1, apiErr.Details.Rejected)
+	})
+
+	t.Run("invalid request", func(t *testing.T) {
+		ts := httptest.NewServer(NewImportHandler(storage.NewMemStorage(), nil).Import())
+		defer ts.Close()
+
+		resp, _ := postImport(t, ts, "", domain.TextContentType, "hits 1")
+		assert.Equal(t, http.StatusUnsupportedMediaType, resp.StatusCode)
+
+		resp, _ = postImport(t, ts, "counters=max", domain.JSONContentType, "[]")
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("storage error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := mocks.NewMockRepository(ctrl)
+		mockRepo.EXPECT().UpdateMetrics(gomock.Any()).Return(errors.New("db is down"))
+
+		ts := httptest.NewServer(NewImportHandler(mockRepo, nil).Import())
+		defer ts.Close()
+
+		resp, _ := postImport(t, ts, "", domain.JSONContentType, `[{"id":"load","type":"gauge","value":1}]`)
+		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+	})
+	t.Run("storage error reading counter to overwrite", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// NOTE: no UpdateMetrics is expected, counter must not be added to when its current value is unknown
+		mockRepo := mocks.NewMockRepository(ctrl)
+		mockRepo.EXPECT().GetCounterMetric("hits").Return(int64(0), errors.New("db is down"))
+
+		ts := httptest.NewServer(NewImportHandler(mockRepo, nil).Import())
+		defer ts.Close()
+
+		resp, body := postImport(t, ts, "counters=overwrite", domain.JSONContentType, `[{"id":"hits","type":"counter","delta":5}]`)
+		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+
+		var apiErr importError
+		require.NoError(t, json.Unmarshal(body, &apiErr))
+		assert.Equal(t, apierror.CodeInternal, apiErr.Code)
+		assert.Equal(t, 0, apiErr.Details.Imported)
+	})
+}
@@ -4,9 +4,14 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
 
 	"github.com/frolmr/metrics/internal/domain"
+	"github.com/frolmr/metrics/internal/server/apierror"
+	"github.com/frolmr/metrics/internal/server/storage"
 )
 
 // UpdateMetricJSON updates a metric based on the provided JSON payload.
@@ -17,82 +22,111 @@ import (
 // @Produce json
 // @Param metrics body domain.Metrics true "Metric data to update"
 // @Success 200 {object} domain.Metrics "Updated metric"
-// @Failure 400 {string} string "Invalid request payload or metric type"
-// @Failure 500 {string} string "Internal server error"
+// @Failure 400 {object} apierror.Error "Invalid request payload, metric type or value"
+// @Failure 415 {object} apierror.Error "Body is not JSON"
+// @Failure 500 {object} apierror.Error "Internal server error"
 // @Router /update [post]
+// @Router /api/v1/update [post]
 func (rh *RequestHandler) UpdateMetricJSON() http.HandlerFunc {
 	return func(res http.ResponseWriter, req *http.Request) {
 		res.Header().Set("Content-Type", domain.JSONContentType)
 
 		metricsRequest, err := rh.readPayloadToMetrics(req)
 		if err != nil {
-			http.Error(res, err.Error(), http.StatusBadRequest)
+			apierror.Write(res, req, http.StatusBadRequest, apierror.CodeBadRequest, err.Error(), nil)
 			return
 		}
 
 		if metricsRequest.MType != domain.GaugeType && metricsRequest.MType != domain.CounterType {
-			http.Error(res, "wrong metric type", http.StatusBadRequest)
+			apierror.Write(res, req, http.StatusBadRequest, apierror.CodeBadRequest, "wrong metric type", nil)
 			return
 		}
 
-		if metricsRequest.Delta != nil {
-			if updateErr := rh.repo.UpdateCounterMetric(metricsRequest.ID, *metricsRequest.Delta); updateErr != nil {
-				http.Error(res, "error updating metric", http.StatusBadRequest)
-				return
-			}
-		} else {
-			if updateErr := rh.repo.UpdateGaugeMetric(metricsRequest.ID, *metricsRequest.Value); updateErr != nil {
-				http.Error(res, "error updating metric", http.StatusBadRequest)
-				return
-			}
-		}
-
-		metricResponse, err := rh.prepareMetricsResponse(metricsRequest.ID, metricsRequest.MType)
+		metricsRequest, err = rh.validator.ValidateUpdate(metricsRequest, rh.repo.GetCounterMetric)
 		if err != nil {
-			http.Error(res, err.Error(), http.StatusInternalServerError)
+			writeUpdateError(res, req, err)
 			return
 		}
 
-		resp, err := json.Marshal(metricResponse)
+		if metricsRequest.MType == domain.CounterType {
+			err = rh.repo.UpdateCounterMetric(metricsRequest.ID, *metricsRequest.Delta)
+		} else {
+			err = rh.repo.UpdateGaugeMetric(metricsRequest.ID, *metricsRequest.Value)
+		}
 		if err != nil {
-			http.Error(res, err.Error(), http.StatusInternalServerError)
+			writeUpdateError(res, req, err)
 			return
 		}
 
-		if _, err := res.Write(resp); err != nil {
-			http.Error(res, err.Error(), http.StatusInternalServerError)
+		metricResponse, err := rh.prepareMetricsResponse(metricsRequest.ID, metricsRequest.MType)
+		if err != nil {
+			apierror.Write(res, req, http.StatusInternalServerError, apierror.CodeInternal, err.Error(), nil)
 			return
 		}
+
+		writeJSON(res, req, metricResponse)
 	}
 }
 
 // BulkUpdateMetricJSON updates multiple metrics based on the provided JSON payload.
+// Every metric is validated separately, valid ones are saved even if some others are rejected.
 // @Summary Update multiple metrics
-// @Description Updates multiple metrics with the provided JSON payload.
+// @Description Updates multiple metrics with the provided JSON payload and reports accepted and rejected metrics.
 // @Tags metrics
 // @Accept json
 // @Produce json
 // @Param metrics body []domain.Metrics true "List of metrics to update"
-// @Success 200 {string} string "Metrics updated successfully"
-// @Failure 400 {string} string "Invalid request payload"
-// @Failure 500 {string} string "Internal server error"
+// @Success 200 {object} domain.UpdateResult "Per-metric result, at least one metric accepted"
+// @Failure 400 {object} domain.UpdateResult "Invalid request payload or all metrics rejected, versioned route wraps result into apierror.Error details"
+// @Failure 415 {object} apierror.Error "Body is not JSON"
+// @Failure 500 {object} domain.UpdateResult "Internal server error, versioned route wraps result into apierror.Error details"
 // @Router /updates [post]
+// @Router /api/v1/updates [post]
 func (rh *RequestHandler) BulkUpdateMetricJSON() http.HandlerFunc {
 	return func(res http.ResponseWriter, req *http.Request) {
 		res.Header().Set("Content-Type", domain.JSONContentType)
 
 		metricsSlice, err := rh.readPayloadToMetricsSlice(req)
 		if err != nil {
-			http.Error(res, err.Error(), http.StatusBadRequest)
+			apierror.Write(res, req, http.StatusBadRequest, apierror.CodeBadRequest, err.Error(), nil)
 			return
 		}
 
-		if err := rh.repo.UpdateMetrics(metricsSlice); err != nil {
-			http.Error(res, err.Error(), http.StatusBadRequest)
+		valid, result := rh.validator.ValidateBatch(metricsSlice, rh.repo.GetCounterMetric)
+
+		statusCode := http.StatusOK
+		if len(valid) == 0 && len(result.Rejected) != 0 {
+			statusCode = http.StatusBadRequest
+		}
+
+		if len(valid) != 0 {
+			if err := rh.repo.UpdateMetrics(valid); err != nil {
+				result.RejectAll(err.Error())
+				statusCode = http.StatusInternalServerError
+			}
+		}
+
+		// NOTE: versioned API wraps failed result into error, legacy clients get bare result
+		if statusCode != http.StatusOK && apierror.IsAPIRequest(req) {
+			if statusCode == http.StatusBadRequest {
+				apierror.Write(res, req, statusCode, apierror.CodeValidationFailed, "all metrics rejected", result)
+			} else {
+				apierror.Write(res, req, statusCode, apierror.CodeInternal, "error updating metrics", result)
+			}
+			return
+		}
+
+		resp, err := json.Marshal(result)
+		if err != nil {
+			apierror.Write(res, req, http.StatusInternalServerError, apierror.CodeInternal, err.Error(), nil)
 			return
 		}
 
-		res.WriteHeader(http.StatusOK)
+		res.WriteHeader(statusCode)
+		if _, err := res.Write(resp); err != nil {
+			apierror.Write(res, req, http.StatusInternalServerError, apierror.CodeInternal, err.Error(), nil)
+			return
+		}
 	}
 }
 
@@ -104,41 +138,122 @@ func (rh *RequestHandler) BulkUpdateMetricJSON() http.HandlerFunc {
 // @Produce json
 // @Param metrics body domain.Metrics true "Metric data to retrieve"
 // @Success 200 {object} domain.Metrics "Requested metric"
-// @Failure 400 {string} string "Invalid request payload or metric type"
-// @Failure 404 {string} string "Metric not found"
-// @Failure 500 {string} string "Internal server error"
+// @Failure 400 {object} apierror.Error "Invalid request payload or metric type"
+// @Failure 404 {object} apierror.Error "Metric not found"
+// @Failure 415 {object} apierror.Error "Body is not JSON"
+// @Failure 500 {object} apierror.Error "Internal server error"
 // @Router /value [post]
+// @Router /api/v1/value [post]
 func (rh *RequestHandler) GetMetricJSON() http.HandlerFunc {
 	return func(res http.ResponseWriter, req *http.Request) {
 		res.Header().Set("Content-Type", domain.JSONContentType)
 
 		metricsRequest, err := rh.readPayloadToMetrics(req)
 		if err != nil {
-			http.Error(res, err.Error(), http.StatusBadRequest)
+			apierror.Write(res, req, http.StatusBadRequest, apierror.CodeBadRequest, err.Error(), nil)
 			return
 		}
 
 		if metricsRequest.MType != domain.GaugeType && metricsRequest.MType != domain.CounterType {
-			http.Error(res, "wrong metric type", http.StatusBadRequest)
+			apierror.Write(res, req, http.StatusBadRequest, apierror.CodeBadRequest, "wrong metric type", nil)
 			return
 		}
 
 		metricResponse, err := rh.prepareMetricsResponse(metricsRequest.ID, metricsRequest.MType)
+		if errors.Is(err, storage.ErrMetricNotFound) {
+			apierror.Write(res, req, http.StatusNotFound, apierror.CodeNotFound, "metric not found", nil)
+			return
+		}
 		if err != nil {
-			http.Error(res, err.Error(), http.StatusNotFound)
+			apierror.Write(res, req, http.StatusInternalServerError, apierror.CodeInternal, "error reading metric", nil)
 			return
 		}
 
-		resp, err := json.Marshal(metricResponse)
+		writeJSON(res, req, metricResponse)
+	}
+}
+
+// ListMetrics returns a page of metrics as JSON.
+// @Summary List metrics
+// @Description Returns metrics filtered by type and name prefix in stable order, next page is requested with next_cursor of the previous one.
+// @Tags metrics
+// @Produce json
+// @Param type query string false "Type of the metrics (gauge or counter)"
+// @Param prefix query string false "Name prefix"
+// @Param limit query int false "Page size, 100 by default, 1000 at most"
+// @Param cursor query string false "Cursor from the previous page"
+// @Param sort query string false "Sort order: name, -name, type or -type" default(name)
+// @Success 200 {object} domain.MetricsPage "Page of metrics"
+// @Failure 400 {object} apierror.Error "Invalid query"
+// @Failure 500 {object} apierror.Error "Internal server error"
+// @Router /api/v1/metrics [get]
+func (rh *RequestHandler) ListMetrics() http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		query, err := parseMetricsQuery(req.URL.Query())
 		if err != nil {
-			http.Error(res, err.Error(), http.StatusInternalServerError)
+			apierror.Write(res, req, http.StatusBadRequest, apierror.CodeBadRequest, err.Error(), nil)
 			return
 		}
 
-		if _, err := res.Write(resp); err != nil {
-			http.Error(res, err.Error(), http.StatusInternalServerError)
+		// NOTE: one extra metric tells whether there is a next page
+		limit := query.Limit
+		query.Limit++
+
+		metrics, err := rh.repo.ListMetrics(query)
+		if err != nil {
+			apierror.Write(res, req, http.StatusInternalServerError, apierror.CodeInternal, err.Error(), nil)
 			return
 		}
+
+		page := domain.MetricsPage{Metrics: metrics}
+		if len(metrics) > limit {
+			page.Metrics = metrics[:limit]
+			last := page.Metrics[limit-1]
+			page.NextCursor = domain.EncodeCursor(domain.MetricKey{ID: last.ID, MType: last.MType})
+		}
+
+		writeJSON(res, req, page)
+	}
+}
+
+func parseMetricsQuery(values url.Values) (domain.MetricsQuery, error) {
+	query := domain.MetricsQuery{
+		MType:  values.Get("type"),
+		Prefix: values.Get("prefix"),
+		Sort:   domain.SortOrder(values.Get("sort")),
+	}
+
+	if limit := values.Get("limit"); limit != "" {
+		value, err := strconv.Atoi(limit)
+		if err != nil || value == 0 {
+			return query, fmt.Errorf("%w: %q", domain.ErrInvalidLimit, limit)
+		}
+		query.Limit = value
+	}
+
+	if cursor := values.Get("cursor"); cursor != "" {
+		after, err := domain.DecodeCursor(cursor)
+		if err != nil {
+			return query, err
+		}
+		query.After = after
+	}
+
+	err := query.Validate()
+	return query, err
+}
+
+func writeJSON(res http.ResponseWriter, req *http.Request, v any) {
+	resp, err := json.Marshal(v)
+	if err != nil {
+		apierror.Write(res, req, http.StatusInternalServerError, apierror.CodeInternal, err.Error(), nil)
+		return
+	}
+
+	res.Header().Set("Content-Type", domain.JSONContentType)
+	if _, err := res.Write(resp); err != nil {
+		apierror.Write(res, req, http.StatusInternalServerError, apierror.CodeInternal, err.Error(), nil)
+		return
 	}
 }
 
@@ -175,13 +290,13 @@ func (rh *RequestHandler) prepareMetricsResponse(metricName, metricType string)
 	case domain.CounterType:
 		metricValue, err := rh.repo.GetCounterMetric(metricName)
 		if err != nil {
-			return domain.Metrics{}, errors.New("metric not found")
+			return domain.Metrics{}, err
 		}
 		return domain.Metrics{ID: metricName, MType: metricType, Delta: &metricValue, Value: nil}, nil
 	case domain.GaugeType:
 		metricValue, err := rh.repo.GetGaugeMetric(metricName)
 		if err != nil {
-			return domain.Metrics{}, errors.New("metric not found")
+			return domain.Metrics{}, err
 		}
 		return domain.Metrics{ID: metricName, MType: metricType, Delta: nil, Value: &metricValue}, nil
 	default:
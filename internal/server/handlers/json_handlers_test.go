@@ -46,12 +46,12 @@ func prepareBodySlice(t *testing.T, metrics []domain.Metrics) []byte {
 }
 
 func TestUpdateJSONMetricHandler(t *testing.T) {
-	ms := storage.MemStorage{
+	ms := &storage.MemStorage{
 		CounterMetrics: make(map[string]int64),
 		GaugeMetrics:   make(map[string]float64),
 	}
 
-	rh := NewRequestHandler(ms)
+	rh := NewRequestHandler(ms, nil)
 
 	r := chi.NewRouter()
 	r.Post("/update", rh.UpdateMetricJSON())
@@ -108,6 +108,15 @@ func TestUpdateJSONMetricHandler(t *testing.T) {
 				responseBody: []byte("wrong metric type\n"),
 			},
 		},
+		{
+			name:   "fail counter without delta request",
+			method: http.MethodPost,
+			body:   prepareBody(t, domain.Metrics{ID: "tstCounter", MType: "counter"}),
+			want: want{
+				statusCode:   http.StatusBadRequest,
+				responseBody: []byte("counter metric without delta\n"),
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -121,12 +130,12 @@ func TestGetJSONMetricHandler(t *testing.T) {
 	gaugeVal := 1.1
 	var counterVal int64 = 1
 
-	ms := storage.MemStorage{
+	ms := &storage.MemStorage{
 		CounterMetrics: map[string]int64{"cTest1": counterVal},
 		GaugeMetrics:   map[string]float64{"gTest1": gaugeVal},
 	}
 
-	rh := NewRequestHandler(ms)
+	rh := NewRequestHandler(ms, nil)
 
 	r := chi.NewRouter()
 	r.Post("/value", rh.GetMetricJSON())
@@ -196,12 +205,12 @@ func prepareBody(t *testing.T, metrics domain.Metrics) []byte {
 }
 
 func TestBulkUpdateJSONMetricHandler(t *testing.T) {
-	ms := storage.MemStorage{
+	ms := &storage.MemStorage{
 		CounterMetrics: make(map[string]int64),
 		GaugeMetrics:   make(map[string]float64),
 	}
 
-	rh := NewRequestHandler(ms)
+	rh := NewRequestHandler(ms, nil)
 
 	r := chi.NewRouter()
 	r.Post("/updates", rh.BulkUpdateMetricJSON())
@@ -230,8 +239,35 @@ func TestBulkUpdateJSONMetricHandler(t *testing.T) {
 				{ID: "tstCounter", MType: "counter", Delta: &counterVal},
 			}),
 			want: want{
-				statusCode:   http.StatusOK,
-				responseBody: []byte(""),
+				statusCode: http.StatusOK,
+				responseBody: []byte(`{"accepted":[{"index":0,"id":"tstGauge","type":"gauge"},` +
+					`{"index":1,"id":"tstCounter","type":"counter"}],"rejected":[]}`),
+			},
+		},
+		{
+			name:   "partial bulk update request",
+			method: http.MethodPost,
+			body: prepareBodySlice(t, []domain.Metrics{
+				{ID: "tstGauge", MType: "gauge", Value: &gaugeVal},
+				{ID: "tstCounter", MType: "counter"},
+				{ID: "tstUnknown", MType: "unknown", Value: &gaugeVal},
+			}),
+			want: want{
+				statusCode: http.StatusOK,
+				responseBody: []byte(`{"accepted":[{"index":0,"id":"tstGauge","type":"gauge"}],` +
+					`"rejected":[{"index":1,"id":"tstCounter","type":"counter","reason":"counter metric without delta"},` +
+					`{"index":2,"id":"tstUnknown","type":"unknown","reason":"unknown metric type"}]}`),
+			},
+		},
+		{
+			name:   "fail all metrics rejected",
+			method: http.MethodPost,
+			body: prepareBodySlice(t, []domain.Metrics{
+				{MType: "gauge", Value: &gaugeVal},
+			}),
+			want: want{
+				statusCode:   http.StatusBadRequest,
+				responseBody: []byte(`{"accepted":[],"rejected":[{"index":0,"id":"","type":"gauge","reason":"empty metric name"}]}`),
 			},
 		},
 		{
@@ -265,6 +301,55 @@ func TestBulkUpdateJSONMetricHandler(t *testing.T) {
 	}
 }
 
+func TestBulkUpdateJSONMetricHandler_RepositoryError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	gaugeVal := 1.1
+	var counterVal int64 = 1
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	mockRepo.EXPECT().
+		UpdateMetrics([]domain.Metrics{{ID: "tstGauge", MType: "gauge", Value: &gaugeVal}}).
+		Return(errors.New("repo error")).
+		Times(1)
+
+	rh := NewRequestHandler(mockRepo, nil)
+
+	r := chi.NewRouter()
+	r.Post("/updates", rh.BulkUpdateMetricJSON())
+
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	body, code := testJSONRequest(t, ts, http.MethodPost, "/updates", prepareBodySlice(t, []domain.Metrics{
+		{ID: "tstGauge", MType: "gauge", Value: &gaugeVal},
+		{ID: "tstCounter", MType: "gauge", Delta: &counterVal},
+	}))
+	assert.Equal(t, http.StatusInternalServerError, code)
+	assert.Equal(t, `{"accepted":[],"rejected":[`+
+		`{"index":0,"id":"tstGauge","type":"gauge","reason":"repo error"},`+
+		`{"index":1,"id":"tstCounter","type":"gauge","reason":"gauge metric without value"}]}`, string(body))
+}
+
+func TestGetMetricJSON_RepositoryError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	mockRepo.EXPECT().GetGaugeMetric("load").Return(float64(0), errors.New("repo error"))
+
+	r := chi.NewRouter()
+	r.Post("/value", NewRequestHandler(mockRepo, nil).GetMetricJSON())
+
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	body, code := testJSONRequest(t, ts, http.MethodPost, "/value", prepareBody(t, domain.Metrics{ID: "load", MType: "gauge"}))
+	assert.Equal(t, http.StatusInternalServerError, code, "storage failure is not a missing metric")
+	assert.Equal(t, "error reading metric\n", string(body))
+}
+
 func TestUpdateJSONMetricHandler_ErrorScenarios(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -276,7 +361,7 @@ func TestUpdateJSONMetricHandler_ErrorScenarios(t *testing.T) {
 		Return(errors.New("repo error")).
 		Times(1)
 
-	rh := NewRequestHandler(mockRepo)
+	rh := NewRequestHandler(mockRepo, nil)
 
 	r := chi.NewRouter()
 	r.Post("/update", rh.UpdateMetricJSON())
@@ -301,7 +386,7 @@ func TestUpdateJSONMetricHandler_ErrorScenarios(t *testing.T) {
 			method: http.MethodPost,
 			body:   prepareBody(t, domain.Metrics{ID: "tstGauge", MType: "gauge", Value: &gaugeVal}),
 			want: want{
-				statusCode:   http.StatusBadRequest,
+				statusCode:   http.StatusInternalServerError,
 				responseBody: []byte("error updating metric\n"),
 			},
 		},
@@ -317,12 +402,12 @@ func TestUpdateJSONMetricHandler_ErrorScenarios(t *testing.T) {
 }
 
 func TestGetJSONMetricHandler_ErrorScenarios(t *testing.T) {
-	ms := storage.MemStorage{
+	ms := &storage.MemStorage{
 		CounterMetrics: make(map[string]int64),
 		GaugeMetrics:   make(map[string]float64),
 	}
 
-	rh := NewRequestHandler(ms)
+	rh := NewRequestHandler(ms, nil)
 
 	r := chi.NewRouter()
 	r.Post("/value", rh.GetMetricJSON())
@@ -371,13 +456,13 @@ func TestGetJSONMetricHandler_ErrorScenarios(t *testing.T) {
 
 // ExampleRequestHandler_UpdateMetricJSON demonstrates how to use the UpdateMetricJSON handler.
 func ExampleRequestHandler_UpdateMetricJSON() {
-	ms := storage.MemStorage{
+	ms := &storage.MemStorage{
 		CounterMetrics: make(map[string]int64),
 		GaugeMetrics:   make(map[string]float64),
 	}
 
 	// Create a new RequestHandler
-	rh := NewRequestHandler(ms)
+	rh := NewRequestHandler(ms, nil)
 
 	// Create a JSON payload for the request
 	payload := `{"id": "cpu_usage", "type": "gauge", "value": 3.14}`
@@ -399,13 +484,13 @@ func ExampleRequestHandler_UpdateMetricJSON() {
 
 // ExampleRequestHandler_BulkUpdateMetricJSON demonstrates how to use the BulkUpdateMetricJSON handler.
 func ExampleRequestHandler_BulkUpdateMetricJSON() {
-	ms := storage.MemStorage{
+	ms := &storage.MemStorage{
 		CounterMetrics: make(map[string]int64),
 		GaugeMetrics:   make(map[string]float64),
 	}
 
 	// Create a new RequestHandler
-	rh := NewRequestHandler(ms)
+	rh := NewRequestHandler(ms, nil)
 
 	// Create a JSON payload for the request
 	payload := `[{"id": "cpu_usage", "type": "gauge", "value": 3.14}, {"id": "memory_usage", "type": "gauge", "value": 2.71}]`
@@ -427,13 +512,13 @@ func ExampleRequestHandler_BulkUpdateMetricJSON() {
 
 // ExampleRequestHandler_GetMetricJSON demonstrates how to use the GetMetricJSON handler.
 func ExampleRequestHandler_GetMetricJSON() {
-	ms := storage.MemStorage{
+	ms := &storage.MemStorage{
 		CounterMetrics: make(map[string]int64),
 		GaugeMetrics:   map[string]float64{"cpu_usage": 3.14, "memory_usage": 2.71},
 	}
 
 	// Create a new RequestHandler
-	rh := NewRequestHandler(ms)
+	rh := NewRequestHandler(ms, nil)
 
 	// Create a JSON payload for the request
 	payload := `{"id": "cpu_usage", "type": "gauge"}`
@@ -452,3 +537,77 @@ func ExampleRequestHandler_GetMetricJSON() {
 	// Output:
 	// {"id":"cpu_usage","type":"gauge","value":3.14}
 }
+
+func TestListMetricsHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	first, second := 1.5, 2.5
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	mockRepo.EXPECT().
+		ListMetrics(domain.MetricsQuery{MType: "gauge", Prefix: "mem", Sort: domain.SortByName, Limit: 2}).
+		Return([]domain.Metrics{
+			{ID: "mem_a", MType: "gauge", Value: &first},
+			{ID: "mem_b", MType: "gauge", Value: &second},
+		}, nil).
+		Times(1)
+	mockRepo.EXPECT().
+		ListMetrics(domain.MetricsQuery{
+			Sort:  domain.SortByName,
+			After: &domain.MetricKey{ID: "mem_a", MType: "gauge"},
+			Limit: domain.DefaultListLimit + 1,
+		}).
+		Return([]domain.Metrics{{ID: "mem_b", MType: "gauge", Value: &second}}, nil).
+		Times(1)
+	mockRepo.EXPECT().
+		ListMetrics(gomock.Any()).
+		Return(nil, errors.New("repo error")).
+		Times(1)
+
+	rh := NewRequestHandler(mockRepo, nil)
+
+	r := chi.NewRouter()
+	r.Get("/metrics", rh.ListMetrics())
+
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	cursor := domain.EncodeCursor(domain.MetricKey{ID: "mem_a", MType: "gauge"})
+
+	tests := []struct {
+		name     string
+		query    string
+		wantCode int
+		wantBody string
+	}{
+		{
+			name:     "first page",
+			query:    "?type=gauge&prefix=mem&limit=1",
+			wantCode: http.StatusOK,
+			wantBody: `{"metrics":[{"id":"mem_a","type":"gauge","value":1.5}],"next_cursor":"` + cursor + `"}`,
+		},
+		{
+			name:     "last page",
+			query:    "?cursor=" + cursor,
+			wantCode: http.StatusOK,
+			wantBody: `{"metrics":[{"id":"mem_b","type":"gauge","value":2.5}]}`,
+		},
+		{name: "bad limit", query: "?limit=abc", wantCode: http.StatusBadRequest},
+		{name: "zero limit", query: "?limit=0", wantCode: http.StatusBadRequest},
+		{name: "bad cursor", query: "?cursor=abc", wantCode: http.StatusBadRequest},
+		{name: "bad sort", query: "?sort=value", wantCode: http.StatusBadRequest},
+		{name: "bad type", query: "?type=histogram", wantCode: http.StatusBadRequest},
+		{name: "repo error", query: "", wantCode: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, code := testJSONRequest(t, ts, http.MethodGet, "/metrics"+tt.query, nil)
+			assert.Equal(t, tt.wantCode, code)
+			if tt.wantBody != "" {
+				assert.JSONEq(t, tt.wantBody, string(body))
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/frolmr/metrics/internal/domain"
+	"github.com/frolmr/metrics/internal/server/apierror"
+	"github.com/frolmr/metrics/internal/server/ingest"
+	"github.com/frolmr/metrics/internal/server/storage"
+	otlp "github.com/frolmr/metrics/pkg/proto/otlp"
+)
+
+const (
+	// ProtobufContentType is the media type of OTLP/HTTP binary encoding.
+	ProtobufContentType = "application/x-protobuf"
+
+	// OTLPMetricsPath is the default path of OTLP/HTTP metrics exporters.
+	OTLPMetricsPath = "/v1/metrics"
+
+	maxOTLPBodySize = 16 << 20
+)
+
+// OTLPHandler receives metrics from OpenTelemetry exporters.
+type OTLPHandler struct {
+	repo      storage.Repository
+	validator *domain.Validator
+	converter *ingest.OTLPConverter
+}
+
+// NewOTLPHandler function is constructor for OTLP handler, nil validator means default rules.
+func NewOTLPHandler(repo storage.Repository, validator *domain.Validator, rules *ingest.Rules) *OTLPHandler {
+	if validator == nil {
+		validator = domain.DefaultValidator()
+	}
+	return &OTLPHandler{
+		repo:      repo,
+		validator: validator,
+		converter: ingest.NewOTLPConverter(rules),
+	}
+}
+
+// ExportMetrics saves metrics of OTLP/HTTP export request.
+// Data points which can't be converted or fail validation are reported as partial success.
+// @Summary Receive OpenTelemetry metrics
+// @Description Accepts OTLP/HTTP ExportMetricsServiceRequest in binary protobuf or JSON encoding.
+// @Description Gauges and non-monotonic cumulative sums become gauges, monotonic and delta sums become counters,
+// @Description cumulative values are converted to deltas starting from the second point of every series.
+// @Description Histograms become name_count counter and name_mean, name_min and name_max gauges.
+// @Description Bodies are neither decrypted nor signature checked, so with KEY or CRYPTO_KEY set the route is forbidden
+// @Description unless UNSIGNED_INGEST is enabled.
+// @Tags metrics
+// @Accept application/x-protobuf,json
+// @Produce application/x-protobuf,json
+// @Param request body string true "ExportMetricsServiceRequest"
+// @Success 200 {string} string "ExportMetricsServiceResponse, partial_success is set if some data points are rejected"
+// @Failure 400 {string} string "Request can't be decoded"
+// @Failure 403 {object} apierror.Error "Unsigned writes are disabled while KEY or CRYPTO_KEY is set"
+// @Failure 413 {string} string "Request is too large"
+// @Failure 415 {string} string "Unsupported content type"
+// @Failure 500 {string} string "Internal server error"
+// @Router /v1/metrics [post]
+func (oh *OTLPHandler) ExportMetrics() http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		isJSON := apierror.HasContentType(req, domain.JSONContentType)
+		if !isJSON && !apierror.HasContentType(req, ProtobufContentType) {
+			apierror.Write(res, req, http.StatusUnsupportedMediaType, apierror.CodeUnsupportedMediaType,
+				"content type must be "+ProtobufContentType+" or "+domain.JSONContentType, nil)
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(res, req.Body, maxOTLPBodySize))
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				apierror.Write(res, req, http.StatusRequestEntityTooLarge, apierror.CodeBadRequest, "request is too large", nil)
+				return
+			}
+			apierror.Write(res, req, http.StatusBadRequest, apierror.CodeBadRequest, err.Error(), nil)
+			return
+		}
+
+		var exportRequest otlp.ExportMetricsServiceRequest
+		if isJSON {
+			err = protojson.UnmarshalOptions{DiscardUnknown: true}.Unmarshal(body, &exportRequest)
+		} else {
+			err = proto.Unmarshal(body, &exportRequest)
+		}
+		if err != nil {
+			apierror.Write(res, req, http.StatusBadRequest, apierror.CodeBadRequest, "invalid export request: "+err.Error(), nil)
+			return
+		}
+
+		metrics, convErrs := oh.converter.Convert(&exportRequest)
+		valid, result := oh.validator.ValidateBatch(metrics, oh.repo.GetCounterMetric)
+		if len(valid) != 0 {
+			if err := oh.repo.UpdateMetrics(valid); err != nil {
+				apierror.Write(res, req, http.StatusInternalServerError, apierror.CodeInternal, "error updating metrics", nil)
+				return
+			}
+		}
+
+		exportResponse := &otlp.ExportMetricsServiceResponse{}
+		if rejected := len(convErrs) + len(result.Rejected); rejected != 0 {
+			var message string
+			if len(convErrs) != 0 {
+				message = convErrs[0].Error()
+			} else {
+				message = result.Rejected[0].ID + ": " + result.Rejected[0].Reason
+			}
+			exportResponse.PartialSuccess = &otlp.ExportMetricsPartialSuccess{
+				RejectedDataPoints: int64(rejected),
+				ErrorMessage:       message,
+			}
+		}
+
+		var resp []byte
+		if isJSON {
+			resp, err = protojson.Marshal(exportResponse)
+		} else {
+			resp, err = proto.Marshal(exportResponse)
+		}
+		if err != nil {
+			apierror.Write(res, req, http.StatusInternalServerError, apierror.CodeInternal, err.Error(), nil)
+			return
+		}
+
+		if isJSON {
+			res.Header().Set("Content-Type", domain.JSONContentType)
+		} else {
+			res.Header().Set("Content-Type", ProtobufContentType)
+		}
+		if _, err := res.Write(resp); err != nil {
+			apierror.Write(res, req, http.StatusInternalServerError, apierror.CodeInternal, err.Error(), nil)
+			return
+		}
+	}
+}
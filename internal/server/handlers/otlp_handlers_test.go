@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/frolmr/metrics/internal/domain"
+	"github.com/frolmr/metrics/internal/server/mocks"
+	"github.com/frolmr/metrics/internal/server/storage"
+	otlp "github.com/frolmr/metrics/pkg/proto/otlp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+func postOTLP(t *testing.T, ts *httptest.Server, contentType string, body []byte) (*http.Response, []byte) {
+	t.Helper()
+
+	resp, err := ts.Client().Post(ts.URL+OTLPMetricsPath, contentType, bytes.NewReader(body))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp, respBody
+}
+
+func gaugeRequest(points ...float64) *otlp.ExportMetricsServiceRequest {
+	metrics := make([]*otlp.Metric, 0, len(points))
+	for i, v := range points {
+		name := "load"
+		if i > 0 {
+			name = "bad name"
+		}
+		metrics = append(metrics, &otlp.Metric{Name: name, Data: &otlp.Metric_Gauge{Gauge: &otlp.Gauge{
+			DataPoints: []*otlp.NumberDataPoint{{Value: &otlp.NumberDataPoint_AsDouble{AsDouble: v}}},
+		}}})
+	}
+	return &otlp.ExportMetricsServiceRequest{
+		ResourceMetrics: []*otlp.ResourceMetrics{{ScopeMetrics: []*otlp.ScopeMetrics{{Metrics: metrics}}}},
+	}
+}
+
+func TestExportMetrics(t *testing.T) {
+	t.Run("protobuf", func(t *testing.T) {
+		ms := storage.NewMemStorage()
+		ts := httptest.NewServer(NewOTLPHandler(ms, nil, nil).ExportMetrics())
+		defer ts.Close()
+
+		body, err := proto.Marshal(gaugeRequest(1.5))
+		require.NoError(t, err)
+
+		resp, respBody := postOTLP(t, ts, ProtobufContentType, body)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, ProtobufContentType, resp.Header.Get("Content-Type"))
+
+		var exportResponse otlp.ExportMetricsServiceResponse
+		require.NoError(t, proto.Unmarshal(respBody, &exportResponse))
+		assert.Nil(t, exportResponse.GetPartialSuccess())
+		assert.InDelta(t, 1.5, ms.GaugeMetrics["load"], 1e-9)
+	})
+
+	t.Run("json with partial success", func(t *testing.T) {
+		ms := storage.NewMemStorage()
+		ts := httptest.NewServer(NewOTLPHandler(ms, nil, nil).ExportMetrics())
+		defer ts.Close()
+
+		body, err := protojson.Marshal(gaugeRequest(2.5, 1))
+		require.NoError(t, err)
+
+		resp, respBody := postOTLP(t, ts, domain.JSONContentType, body)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, domain.JSONContentType, resp.Header.Get("Content-Type"))
+
+		var exportResponse otlp.ExportMetricsServiceResponse
+		require.NoError(t, protojson.Unmarshal(respBody, &exportResponse))
+		assert.Equal(t, int64(1), exportResponse.GetPartialSuccess().GetRejectedDataPoints())
+		assert.Contains(t, exportResponse.GetPartialSuccess().GetErrorMessage(), "bad name")
+		assert.InDelta(t, 2.5, ms.GaugeMetrics["load"], 1e-9)
+	})
+
+	t.Run("unsupported content type", func(t *testing.T) {
+		ts := httptest.NewServer(NewOTLPHandler(storage.NewMemStorage(), nil, nil).ExportMetrics())
+		defer ts.Close()
+
+		resp, _ := postOTLP(t, ts, "text/plain", []byte("load 1"))
+		assert.Equal(t, http.StatusUnsupportedMediaType, resp.StatusCode)
+	})
+
+	t.Run("invalid body", func(t *testing.T) {
+		ts := httptest.NewServer(NewOTLPHandler(storage.NewMemStorage(), nil, nil).ExportMetrics())
+		defer ts.Close()
+
+		resp, _ := postOTLP(t, ts, ProtobufContentType, []byte{0xff, 0xff})
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("storage error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := mocks.NewMockRepository(ctrl)
+		mockRepo.EXPECT().UpdateMetrics(gomock.Any()).Return(errors.New("db is down"))
+
+		ts := httptest.NewServer(NewOTLPHandler(mockRepo, nil, nil).ExportMetrics())
+		defer ts.Close()
+
+		body, err := proto.Marshal(gaugeRequest(1.5))
+		require.NoError(t, err)
+
+		resp, _ := postOTLP(t, ts, ProtobufContentType, body)
+		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+	})
+}
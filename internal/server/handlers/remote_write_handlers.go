@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/golang/snappy"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/frolmr/metrics/internal/domain"
+	"github.com/frolmr/metrics/internal/server/apierror"
+	"github.com/frolmr/metrics/internal/server/ingest"
+	"github.com/frolmr/metrics/internal/server/storage"
+	prompb "github.com/frolmr/metrics/pkg/proto/prompb"
+)
+
+const (
+	// SnappyEncoding is content encoding of Prometheus remote write requests.
+	SnappyEncoding = "snappy"
+
+	maxRemoteWriteBodySize = 32 << 20
+)
+
+// RemoteWriteHandler receives samples from Prometheus remote write.
+type RemoteWriteHandler struct {
+	repo      storage.Repository
+	validator *domain.Validator
+	converter *ingest.PromConverter
+}
+
+// RemoteWriteErrors describes series and metrics rejected by remote write.
+// @Description Series failed to convert and metrics rejected by validation, the rest of the request is saved.
+type RemoteWriteErrors struct {
+	Series   []string              `json:"series"`
+	Rejected []domain.MetricStatus `json:"rejected"`
+}
+
+// NewRemoteWriteHandler function is constructor for Prometheus remote write handler, nil validator means default rules.
+func NewRemoteWriteHandler(repo storage.Repository, validator *domain.Validator, rules *ingest.Rules) *RemoteWriteHandler {
+	if validator == nil {
+		validator = domain.DefaultValidator()
+	}
+	return &RemoteWriteHandler{
+		repo:      repo,
+		validator: validator,
+		converter: ingest.NewPromConverter(rules),
+	}
+}
+
+// RemoteWrite saves samples of Prometheus remote write request.
+// @Summary Receive Prometheus remote write
+// @Description Accepts snappy compressed WriteRequest of Prometheus remote write protocol 1.0.
+// @Description Metric name is __name__ label with values of labels listed in ingest rules tags.
+// @Description Counters are recognized by ingest rules, metadata and _total suffix and converted to deltas
+// @Description starting from the second sample of every series, all the other series are gauges.
+// @Description Bodies are neither decrypted nor signature checked, so with KEY or CRYPTO_KEY set the route is forbidden
+// @Description unless UNSIGNED_INGEST is enabled.
+// @Tags metrics
+// @Accept application/x-protobuf
+// @Produce json
+// @Param request body string true "Snappy compressed WriteRequest"
+// @Success 204 "All series saved"
+// @Failure 400 {object} apierror.Error "Request can't be decoded or some series rejected, details are RemoteWriteErrors"
+// @Failure 403 {object} apierror.Error "Unsigned writes are disabled while KEY or CRYPTO_KEY is set"
+// @Failure 413 {object} apierror.Error "Request is too large"
+// @Failure 415 {object} apierror.Error "Request is not snappy compressed protobuf"
+// @Failure 500 {object} apierror.Error "Internal server error"
+// @Router /api/v1/prom/write [post]
+func (rw *RemoteWriteHandler) RemoteWrite() http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		if !strings.EqualFold(req.Header.Get("Content-Encoding"), SnappyEncoding) {
+			apierror.Write(res, req, http.StatusUnsupportedMediaType, apierror.CodeUnsupportedMediaType,
+				"content encoding must be "+SnappyEncoding, nil)
+			return
+		}
+
+		compressed, err := io.ReadAll(http.MaxBytesReader(res, req.Body, maxRemoteWriteBodySize))
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				apierror.Write(res, req, http.StatusRequestEntityTooLarge, apierror.CodeBadRequest, "request is too large", nil)
+				return
+			}
+			apierror.Write(res, req, http.StatusBadRequest, apierror.CodeBadRequest, err.Error(), nil)
+			return
+		}
+
+		if size, err := snappy.DecodedLen(compressed); err != nil || size > maxRemoteWriteBodySize {
+			apierror.Write(res, req, http.StatusBadRequest, apierror.CodeBadRequest, "invalid snappy body", nil)
+			return
+		}
+		body, err := snappy.Decode(nil, compressed)
+		if err != nil {
+			apierror.Write(res, req, http.StatusBadRequest, apierror.CodeBadRequest, "invalid snappy body", nil)
+			return
+		}
+
+		var writeRequest prompb.WriteRequest
+		if err := proto.Unmarshal(body, &writeRequest); err != nil {
+			apierror.Write(res, req, http.StatusBadRequest, apierror.CodeBadRequest, "invalid write request: "+err.Error(), nil)
+			return
+		}
+
+		metrics, convErrs := rw.converter.Convert(&writeRequest)
+		valid, result := rw.validator.ValidateBatch(metrics, rw.repo.GetCounterMetric)
+		if len(valid) != 0 {
+			if err := rw.repo.UpdateMetrics(valid); err != nil {
+				apierror.Write(res, req, http.StatusInternalServerError, apierror.CodeInternal, "error updating metrics", nil)
+				return
+			}
+		}
+
+		if len(convErrs) != 0 || len(result.Rejected) != 0 {
+			details := RemoteWriteErrors{
+				Series:   make([]string, 0, len(convErrs)),
+				Rejected: result.Rejected,
+			}
+			for _, err := range convErrs {
+				details.Series = append(details.Series, err.Error())
+			}
+			apierror.Write(res, req, http.StatusBadRequest, apierror.CodeValidationFailed, "some series rejected", details)
+			return
+		}
+
+		res.WriteHeader(http.StatusNoContent)
+	}
+}
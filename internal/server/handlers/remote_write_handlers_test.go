@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/frolmr/metrics/internal/domain"
+	"github.com/frolmr/metrics/internal/server/apierror"
+	"github.com/frolmr/metrics/internal/server/mocks"
+	"github.com/frolmr/metrics/internal/server/storage"
+	prompb "github.com/frolmr/metrics/pkg/proto/prompb"
+	"github.com/golang/snappy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/protobuf/proto"
+)
+
+func postRemoteWrite(t *testing.T, ts *httptest.Server, encoding string, body []byte) (*http.Response, []byte) {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodPost, ts.URL, bytes.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", ProtobufContentType)
+	req.Header.Set("Content-Encoding", encoding)
+	req.Header.Set("Accept", domain.JSONContentType)
+
+	resp, err := ts.Client().Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp, respBody
+}
+
+func remoteWriteBody(t *testing.T, names ...string) []byte {
+	t.Helper()
+
+	request := &prompb.WriteRequest{}
+	for _, name := range names {
+		request.Timeseries = append(request.Timeseries, &prompb.TimeSeries{
+			Labels:  []*prompb.Label{{Name: "__name__", Value: name}},
+			Samples: []*prompb.Sample{{Value: 1.5, Timestamp: 1}},
+		})
+	}
+
+	body, err := proto.Marshal(request)
+	require.NoError(t, err)
+	return snappy.Encode(nil, body)
+}
+
+func TestRemoteWrite(t *testing.T) {
+	t.Run("saves gauges", func(t *testing.T) {
+		ms := storage.NewMemStorage()
+		ts := httptest.NewServer(NewRemoteWriteHandler(ms, nil, nil).RemoteWrite())
+		defer ts.Close()
+
+		resp, _ := postRemoteWrite(t, ts, SnappyEncoding, remoteWriteBody(t, "load"))
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+		assert.InDelta(t, 1.5, ms.GaugeMetrics["load"], 1e-9)
+	})
+
+	t.Run("rejected series", func(t *testing.T) {
+		ms := storage.NewMemStorage()
+		ts := httptest.NewServer(NewRemoteWriteHandler(ms, nil, nil).RemoteWrite())
+		defer ts.Close()
+
+		resp, respBody := postRemoteWrite(t, ts, SnappyEncoding, remoteWriteBody(t, "load", "bad name", ""))
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+		var apiErr struct {
+			Code    string            `json:"code"`
+			Details RemoteWriteErrors `json:"details"`
+		}
+		require.NoError(t, json.Unmarshal(respBody, &apiErr))
+		assert.Equal(t, apierror.CodeValidationFailed, apiErr.Code)
+		assert.Len(t, apiErr.Details.Series, 1)
+		require.Len(t, apiErr.Details.Rejected, 1)
+		assert.Equal(t, "bad name", apiErr.Details.Rejected[0].ID)
+		assert.InDelta(t, 1.5, ms.GaugeMetrics["load"], 1e-9, "valid series are saved")
+	})
+
+	t.Run("not snappy encoded", func(t *testing.T) {
+		ts := httptest.NewServer(NewRemoteWriteHandler(storage.NewMemStorage(), nil, nil).RemoteWrite())
+		defer ts.Close()
+
+		resp, _ := postRemoteWrite(t, ts, "identity", remoteWriteBody(t, "load"))
+		assert.Equal(t, http.StatusUnsupportedMediaType, resp.StatusCode)
+	})
+
+	t.Run("invalid body", func(t *testing.T) {
+		ts := httptest.NewServer(NewRemoteWriteHandler(storage.NewMemStorage(), nil, nil).RemoteWrite())
+		defer ts.Close()
+
+		resp, _ := postRemoteWrite(t, ts, SnappyEncoding, []byte{0xff, 0xff})
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+		resp, _ = postRemoteWrite(t, ts, SnappyEncoding, snappy.Encode(nil, []byte{0xff, 0xff}))
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("storage error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := mocks.NewMockRepository(ctrl)
+		mockRepo.EXPECT().UpdateMetrics(gomock.Any()).Return(errors.New("db is down"))
+
+		ts := httptest.NewServer(NewRemoteWriteHandler(mockRepo, nil, nil).RemoteWrite())
+		defer ts.Close()
+
+		resp, _ := postRemoteWrite(t, ts, SnappyEncoding, remoteWriteBody(t, "load"))
+		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+	})
+}
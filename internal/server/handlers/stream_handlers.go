@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/frolmr/metrics/internal/domain"
+	"github.com/frolmr/metrics/internal/server/apierror"
+	"github.com/frolmr/metrics/internal/server/pubsub"
+)
+
+const (
+	// EventStreamContentType is the media type of server-sent events.
+	EventStreamContentType = "text/event-stream"
+
+	defaultHeartbeatInterval = 15 * time.Second
+	streamWriteTimeout       = 10 * time.Second
+)
+
+// StreamHandler pushes accepted metric updates to clients as server-sent events.
+type StreamHandler struct {
+	hub       *pubsub.Hub
+	heartbeat time.Duration
+}
+
+// NewStreamHandler function is constructor for stream handler, stream is unavailable if hub is nil.
+func NewStreamHandler(hub *pubsub.Hub) *StreamHandler {
+	return &StreamHandler{
+		hub:       hub,
+		heartbeat: defaultHeartbeatInterval,
+	}
+}
+
+// droppedEvent tells client how many updates it missed being too slow.
+type droppedEvent struct {
+	Dropped int64 `json:"dropped"`
+}
+
+// StreamMetrics streams accepted updates until client goes away.
+// @Summary Stream metric updates
+// @Description Pushes every accepted update as "metric" server-sent event with domain.Metrics JSON data.
+// @Description Updates that don't fit into subscriber buffer are dropped, client is told about it by "dropped" event with total number of dropped updates.
+// @Tags metrics
+// @Produce text/event-stream
+// @Param type query string false "Type of the metrics (gauge or counter)"
+// @Param prefix query []string false "Name prefix, may be repeated to watch several prefixes" collectionFormat(multi)
+// @Success 200 {object} domain.Metrics "Stream of metric events"
+// @Failure 400 {object} apierror.Error "Invalid metric type"
+// @Failure 406 {object} apierror.Error "Event stream is not acceptable"
+// @Failure 503 {object} apierror.Error "Stream is not enabled"
+// @Router /api/v1/stream [get]
+func (sh *StreamHandler) StreamMetrics() http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		if sh.hub == nil {
+			apierror.Write(res, req, http.StatusServiceUnavailable, apierror.CodeUnavailable, "metrics stream is not enabled", nil)
+			return
+		}
+
+		mType := req.URL.Query().Get("type")
+		if mType != "" && mType != domain.GaugeType && mType != domain.CounterType {
+			apierror.Write(res, req, http.StatusBadRequest, apierror.CodeBadRequest, "wrong metric type", nil)
+			return
+		}
+		prefixes := req.URL.Query()["prefix"]
+
+		sub := sh.hub.Subscribe(func(m domain.Metrics) bool {
+			return (mType == "" || m.MType == mType) && hasAnyPrefix(m.ID, prefixes)
+		})
+		defer sub.Close()
+
+		rc := http.NewResponseController(res)
+
+		res.Header().Set("Content-Type", EventStreamContentType)
+		res.Header().Set("Cache-Control", "no-cache")
+		res.Header().Set("X-Accel-Buffering", "no")
+		res.WriteHeader(http.StatusOK)
+		if err := rc.Flush(); err != nil {
+			return
+		}
+
+		heartbeat := time.NewTicker(sh.heartbeat)
+		defer heartbeat.Stop()
+
+		var dropped int64
+		for {
+			var err error
+
+			select {
+			case m, ok := <-sub.Updates():
+				if !ok {
+					return
+				}
+				if dropped, err = reportDropped(rc, res, sub, dropped); err == nil {
+					err = writeEvent(rc, res, "metric", m)
+				}
+			case <-heartbeat.C:
+				if dropped, err = reportDropped(rc, res, sub, dropped); err == nil {
+					err = writeRaw(rc, res, ": heartbeat\n\n")
+				}
+			case <-req.Context().Done():
+				return
+			}
+
+			if err != nil {
+				return
+			}
+		}
+	}
+}
+
+func hasAnyPrefix(name string, prefixes []string) bool {
+	if len(prefixes) == 0 {
+		return true
+	}
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// reportDropped sends dropped event if subscriber lost updates since the last report.
+func reportDropped(rc *http.ResponseController, res http.ResponseWriter, sub *pubsub.Subscription, reported int64) (int64, error) {
+	dropped := sub.Dropped()
+	if dropped == reported {
+		return reported, nil
+	}
+	return dropped, writeEvent(rc, res, "dropped", droppedEvent{Dropped: dropped})
+}
+
+func writeEvent(rc *http.ResponseController, res http.ResponseWriter, event string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		// NOTE: NaN and Inf gauges allowed by validation rules can't be sent as JSON, they are skipped
+		return nil
+	}
+	return writeRaw(rc, res, fmt.Sprintf("event: %s\ndata: %s\n\n", event, data))
+}
+
+// writeRaw gives up on clients which don't read for streamWriteTimeout, so that they don't hold the handler forever.
+func writeRaw(rc *http.ResponseController, res http.ResponseWriter, s string) error {
+	if err := rc.SetWriteDeadline(time.Now().Add(streamWriteTimeout)); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return err
+	}
+	if _, err := res.Write([]byte(s)); err != nil {
+		return err
+	}
+	return rc.Flush()
+}
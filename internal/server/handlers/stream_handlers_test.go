@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/frolmr/metrics/internal/domain"
+	"github.com/frolmr/metrics/internal/server/pubsub"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func openStream(t *testing.T, ts *httptest.Server, query string) (*http.Response, *bufio.Reader) {
+	t.Helper()
+
+	resp, err := ts.Client().Get(ts.URL + "/stream" + query)
+	require.NoError(t, err)
+	t.Cleanup(func() { resp.Body.Close() })
+
+	return resp, bufio.NewReader(resp.Body)
+}
+
+func readEvent(t *testing.T, r *bufio.Reader) string {
+	t.Helper()
+
+	var lines []string
+	for {
+		line, err := r.ReadString('\n')
+		require.NoError(t, err)
+		if line == "\n" {
+			return strings.Join(lines, "")
+		}
+		lines = append(lines, line)
+	}
+}
+
+func waitSubscribers(t *testing.T, hub *pubsub.Hub, n int) {
+	t.Helper()
+	require.Eventually(t, func() bool { return hub.Subscribers() == n }, time.Second, 5*time.Millisecond)
+}
+
+func TestStreamMetrics(t *testing.T) {
+	hub := pubsub.NewHub(pubsub.DefaultBufferSize)
+	defer hub.Close()
+
+	ts := httptest.NewServer(NewStreamHandler(hub).StreamMetrics())
+	t.Cleanup(ts.Close)
+
+	resp, events := openStream(t, ts, "?type=gauge&prefix=cpu&prefix=mem")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, EventStreamContentType, resp.Header.Get("Content-Type"))
+	waitSubscribers(t, hub, 1)
+
+	cpu, disk, mem := 0.5, 10.0, 20.0
+	var memCount int64 = 3
+	hub.Publish([]domain.Metrics{
+		{ID: "cpu", MType: domain.GaugeType, Value: &cpu},
+		{ID: "disk", MType: domain.GaugeType, Value: &disk},
+		{ID: "mem_count", MType: domain.CounterType, Delta: &memCount},
+		{ID: "mem_used", MType: domain.GaugeType, Value: &mem},
+	})
+
+	assert.Equal(t, "event: metric\ndata: {\"id\":\"cpu\",\"type\":\"gauge\",\"value\":0.5}\n", readEvent(t, events))
+	assert.Equal(t, "event: metric\ndata: {\"id\":\"mem_used\",\"type\":\"gauge\",\"value\":20}\n", readEvent(t, events))
+
+	resp.Body.Close()
+	waitSubscribers(t, hub, 0)
+}
+
+func TestStreamMetrics_Heartbeat(t *testing.T) {
+	hub := pubsub.NewHub(pubsub.DefaultBufferSize)
+	defer hub.Close()
+
+	sh := NewStreamHandler(hub)
+	sh.heartbeat = 10 * time.Millisecond
+
+	ts := httptest.NewServer(sh.StreamMetrics())
+	t.Cleanup(ts.Close)
+
+	_, events := openStream(t, ts, "")
+	assert.Equal(t, ": heartbeat\n", readEvent(t, events))
+}
+
+func TestStreamMetrics_HubClosed(t *testing.T) {
+	hub := pubsub.NewHub(pubsub.DefaultBufferSize)
+
+	ts := httptest.NewServer(NewStreamHandler(hub).StreamMetrics())
+	t.Cleanup(ts.Close)
+
+	_, events := openStream(t, ts, "")
+	waitSubscribers(t, hub, 1)
+
+	hub.Close()
+
+	_, err := events.ReadString('\n')
+	assert.Error(t, err)
+}
+
+func TestStreamMetrics_Errors(t *testing.T) {
+	t.Run("stream disabled", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		NewStreamHandler(nil).StreamMetrics()(rec, httptest.NewRequest(http.MethodGet, "/api/v1/stream", nil))
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	})
+
+	t.Run("wrong type", func(t *testing.T) {
+		hub := pubsub.NewHub(1)
+		defer hub.Close()
+
+		rec := httptest.NewRecorder()
+		NewStreamHandler(hub).StreamMetrics()(rec, httptest.NewRequest(http.MethodGet, "/api/v1/stream?type=histogram", nil))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, 0, hub.Subscribers())
+	})
+}
+
+func TestReportDropped(t *testing.T) {
+	hub := pubsub.NewHub(1)
+	defer hub.Close()
+
+	sub := hub.Subscribe(nil)
+	value := 1.0
+	m := domain.Metrics{ID: "cpu", MType: domain.GaugeType, Value: &value}
+	hub.Publish([]domain.Metrics{m, m, m})
+
+	rec := httptest.NewRecorder()
+	rc := http.NewResponseController(rec)
+
+	dropped, err := reportDropped(rc, rec, sub, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), dropped)
+	assert.Equal(t, "event: dropped\ndata: {\"dropped\":2}\n\n", rec.Body.String())
+
+	dropped, err = reportDropped(rc, rec, sub, dropped)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), dropped)
+	assert.Equal(t, "event: dropped\ndata: {\"dropped\":2}\n\n", rec.Body.String())
+}
@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/frolmr/metrics/internal/domain"
+	"github.com/frolmr/metrics/internal/server/apierror"
+	"github.com/frolmr/metrics/internal/server/ingest"
+	"github.com/frolmr/metrics/internal/server/storage"
+)
+
+// WriteHandler accepts metrics in InfluxDB line protocol.
+type WriteHandler struct {
+	repo      storage.Repository
+	validator *domain.Validator
+	converter *ingest.LineProtocolConverter
+}
+
+// WriteErrors describes lines and metrics rejected by write.
+// @Description Lines failed to parse and metrics rejected by validation, the rest of the write is saved.
+type WriteErrors struct {
+	Lines    []ingest.LineError    `json:"lines"`
+	Rejected []domain.MetricStatus `json:"rejected"`
+}
+
+// NewWriteHandler function is constructor for line protocol handler, nil validator means default rules.
+func NewWriteHandler(repo storage.Repository, validator *domain.Validator, rules *ingest.Rules) *WriteHandler {
+	if validator == nil {
+		validator = domain.DefaultValidator()
+	}
+	return &WriteHandler{
+		repo:      repo,
+		validator: validator,
+		converter: ingest.NewLineProtocolConverter(rules),
+	}
+}
+
+// Write saves metrics sent in InfluxDB line protocol.
+// Every numeric field becomes metric named measurement[_tag values]_field, float fields are gauges
+// and integer fields are typed by ingest rules. Integer counters are cumulative, so the first point of a series
+// only sets the baseline. Timestamps are accepted but not stored.
+// @Summary Write metrics in line protocol
+// @Description Saves metrics sent in InfluxDB line protocol, compatible with Telegraf InfluxDB outputs.
+// @Description Lines which fail to parse or to validate are reported, all the other lines are saved.
+// @Description Bodies are neither decrypted nor signature checked, so with KEY or CRYPTO_KEY set the route is forbidden
+// @Description unless UNSIGNED_INGEST is enabled.
+// @Tags metrics
+// @Accept plain
+// @Produce json
+// @Param precision query string false "Timestamp precision (ns, us, ms, s), timestamps are not stored"
+// @Param lines body string true "Metrics in line protocol"
+// @Success 204 "All lines saved"
+// @Failure 400 {object} apierror.Error "Unknown precision or some lines rejected, details are WriteErrors"
+// @Failure 403 {object} apierror.Error "Unsigned writes are disabled while KEY or CRYPTO_KEY is set"
+// @Failure 500 {object} apierror.Error "Internal server error"
+// @Router /api/v1/write [post]
+func (wh *WriteHandler) Write() http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		if err := ingest.CheckPrecision(req.URL.Query().Get("precision")); err != nil {
+			apierror.Write(res, req, http.StatusBadRequest, apierror.CodeBadRequest, err.Error(), nil)
+			return
+		}
+
+		points, lineErrors, err := ingest.ParseLineProtocol(req.Body)
+		if err != nil {
+			apierror.Write(res, req, http.StatusBadRequest, apierror.CodeBadRequest, err.Error(), nil)
+			return
+		}
+
+		metrics := make([]domain.Metrics, 0, len(points))
+		for _, p := range points {
+			pointMetrics, err := wh.converter.PointMetrics(p)
+			if err != nil {
+				lineErrors = append(lineErrors, ingest.LineError{Line: p.Line, Error: err.Error()})
+				continue
+			}
+			metrics = append(metrics, pointMetrics...)
+		}
+
+		valid, result := wh.validator.ValidateBatch(metrics, wh.repo.GetCounterMetric)
+		if len(valid) != 0 {
+			if err := wh.repo.UpdateMetrics(valid); err != nil {
+				apierror.Write(res, req, http.StatusInternalServerError, apierror.CodeInternal, "error updating metrics", nil)
+				return
+			}
+		}
+
+		if len(lineErrors) != 0 || len(result.Rejected) != 0 {
+			details := WriteErrors{
+				Lines:    lineErrors,
+				Rejected: result.Rejected,
+			}
+			if details.Lines == nil {
+				details.Lines = []ingest.LineError{}
+			}
+			apierror.Write(res, req, http.StatusBadRequest, apierror.CodeValidationFailed, "some lines rejected", details)
+			return
+		}
+
+		res.WriteHeader(http.StatusNoContent)
+	}
+}
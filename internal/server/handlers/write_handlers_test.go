@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/frolmr/metrics/internal/domain"
+	"github.com/frolmr/metrics/internal/server/apierror"
+	"github.com/frolmr/metrics/internal/server/ingest"
+	"github.com/frolmr/metrics/internal/server/mocks"
+	"github.com/frolmr/metrics/internal/server/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func postLines(t *testing.T, ts *httptest.Server, query, body string) (int, string) {
+	t.Helper()
+
+	resp, err := ts.Client().Post(ts.URL+"/api/v1/write"+query, "text/plain; charset=utf-8", strings.NewReader(body))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp.StatusCode, string(respBody)
+}
+
+func TestWrite(t *testing.T) {
+	rules := &ingest.Rules{
+		Rules: []ingest.Rule{{Match: "_requests$", Type: domain.CounterType}},
+		Tags:  []string{"host"},
+	}
+	require.NoError(t, rules.Compile())
+
+	t.Run("all lines saved", func(t *testing.T) {
+		ms := storage.NewMemStorage()
+		ms.CounterMetrics["http_web1_requests"] = 10
+
+		ts := httptest.NewServer(NewWriteHandler(ms, nil, rules).Write())
+		defer ts.Close()
+
+		code, body := postLines(t, ts, "?precision=s",
+			"http,host=web1 requests=5i,latency=0.25 1700000000\ncpu usage=0.5\n")
+		assert.Equal(t, http.StatusNoContent, code)
+		assert.Empty(t, body)
+
+		code, _ = postLines(t, ts, "?precision=s", "http,host=web1 requests=5i 1700000010\n")
+		assert.Equal(t, http.StatusNoContent, code)
+		code, _ = postLines(t, ts, "?precision=s", "http,host=web1 requests=8i 1700000020\n")
+		assert.Equal(t, http.StatusNoContent, code)
+
+		assert.Equal(t, int64(13), ms.CounterMetrics["http_web1_requests"], "cumulative counter adds only its increase")
+		assert.InDelta(t, 0.25, ms.GaugeMetrics["http_web1_latency"], 1e-9)
+		assert.InDelta(t, 0.5, ms.GaugeMetrics["cpu_usage"], 1e-9)
+	})
+
+	t.Run("partial write", func(t *testing.T) {
+		ms := storage.NewMemStorage()
+
+		ts := httptest.NewServer(NewWriteHandler(ms, nil, rules).Write())
+		defer ts.Close()
+
+		code, body := postLines(t, ts, "", "cpu usage=0.5\ncpu usage\nbad\\ name value=1\n")
+		assert.Equal(t, http.StatusBadRequest, code)
+
+		var apiErr struct {
+			Code    string      `json:"code"`
+			Details WriteErrors `json:"details"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(body), &apiErr))
+		assert.Equal(t, apierror.CodeValidationFailed, apiErr.Code)
+		require.Len(t, apiErr.Details.Lines, 1)
+		assert.Equal(t, 2, apiErr.Details.Lines[0].Line)
+		require.Len(t, apiErr.Details.Rejected, 1)
+		assert.Equal(t, "bad name_value", apiErr.Details.Rejected[0].ID)
+
+		assert.InDelta(t, 0.5, ms.GaugeMetrics["cpu_usage"], 1e-9)
+	})
+
+	t.Run("unknown precision", func(t *testing.T) {
+		ts := httptest.NewServer(NewWriteHandler(storage.NewMemStorage(), nil, rules).Write())
+		defer ts.Close()
+
+		code, _ := postLines(t, ts, "?precision=d", "cpu usage=0.5\n")
+		assert.Equal(t, http.StatusBadRequest, code)
+	})
+
+	t.Run("storage error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := mocks.NewMockRepository(ctrl)
+		mockRepo.EXPECT().UpdateMetrics(gomock.Any()).Return(errors.New("db is down"))
+
+		ts := httptest.NewServer(NewWriteHandler(mockRepo, nil, rules).Write())
+		defer ts.Close()
+
+		code, _ := postLines(t, ts, "", "cpu usage=0.5\n")
+		assert.Equal(t, http.StatusInternalServerError, code)
+	})
+}
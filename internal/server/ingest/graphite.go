@@ -0,0 +1,189 @@
+package ingest
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/frolmr/metrics/internal/domain"
+)
+
+// Graphite template parts with special meaning, any other non-empty part is a tag key.
+const (
+	// TemplateMeasurement puts path part into metric name.
+	TemplateMeasurement = "measurement"
+	// TemplateMeasurementRest puts path part and all the following parts into metric name.
+	TemplateMeasurementRest = "measurement*"
+)
+
+var ErrInvalidGraphite = errors.New("invalid graphite metric")
+
+// GraphiteTemplate maps parts of dotted Graphite path to metric name and tags, e.g.
+// template "servers.host.measurement*" turns servers.web1.cpu.load into cpu.load with tag host=web1.
+// Empty template part skips path part, path parts beyond template go into name.
+type GraphiteTemplate struct {
+	// Filter is a dotted pattern matched against path prefix part by part, parts may use path.Match wildcards.
+	// Empty filter matches any path.
+	Filter string `json:"filter"`
+	// Template is dotted list of template parts.
+	Template string `json:"template"`
+
+	filter []string
+	parts  []string
+}
+
+// GraphiteSample is a parsed Graphite plaintext line path value [timestamp].
+type GraphiteSample struct {
+	Path string
+	// Tags are tags of Graphite tagged series path;tag=value.
+	Tags  []Tag
+	Value float64
+	// Timestamp is -1 if line has no timestamp.
+	Timestamp int64
+}
+
+func (t *GraphiteTemplate) compile() error {
+	if t.Template == "" {
+		return errors.New("empty template")
+	}
+
+	t.parts = strings.Split(t.Template, ".")
+	for i, part := range t.parts {
+		if part == TemplateMeasurementRest && i != len(t.parts)-1 {
+			return fmt.Errorf("%q has to be the last part", TemplateMeasurementRest)
+		}
+	}
+
+	t.filter = nil
+	if t.Filter != "" {
+		t.filter = strings.Split(t.Filter, ".")
+		for _, part := range t.filter {
+			if _, err := path.Match(part, ""); err != nil {
+				return fmt.Errorf("filter %q: %w", t.Filter, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (t *GraphiteTemplate) match(parts []string) bool {
+	if len(t.filter) > len(parts) {
+		return false
+	}
+	for i, pattern := range t.filter {
+		if ok, _ := path.Match(pattern, parts[i]); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func (t *GraphiteTemplate) apply(parts []string) ([]string, []Tag) {
+	var (
+		name []string
+		tags []Tag
+	)
+
+	for i, part := range parts {
+		if i >= len(t.parts) {
+			name = append(name, part)
+			continue
+		}
+
+		switch t.parts[i] {
+		case TemplateMeasurement:
+			name = append(name, part)
+		case TemplateMeasurementRest:
+			return append(name, parts[i:]...), tags
+		case "":
+		default:
+			tags = append(tags, Tag{Key: t.parts[i], Value: part})
+		}
+	}
+	return name, tags
+}
+
+// ParseGraphiteLine parses single line of Graphite plaintext protocol.
+func ParseGraphiteLine(line string) (GraphiteSample, error) {
+	s := GraphiteSample{Timestamp: -1}
+
+	fields := strings.Fields(line)
+	if len(fields) != 2 && len(fields) != 3 {
+		return s, fmt.Errorf("%w: %q: expected path, value and timestamp", ErrInvalidGraphite, line)
+	}
+
+	metricPath, tags, _ := strings.Cut(fields[0], ";")
+	if metricPath == "" {
+		return s, fmt.Errorf("%w: %q: empty path", ErrInvalidGraphite, line)
+	}
+	s.Path = metricPath
+
+	for tags != "" {
+		var tag string
+		tag, tags, _ = strings.Cut(tags, ";")
+		key, value, ok := strings.Cut(tag, "=")
+		if !ok || key == "" || value == "" {
+			return s, fmt.Errorf("%w: %q: bad tag %q", ErrInvalidGraphite, line, tag)
+		}
+		s.Tags = append(s.Tags, Tag{Key: key, Value: value})
+	}
+
+	value, err := strconv.ParseFloat(fields[1], 64)
+	if err != nil {
+		return s, fmt.Errorf("%w: %q: bad value", ErrInvalidGraphite, line)
+	}
+	s.Value = value
+
+	// NOTE: carbon accepts -1 and fractional timestamps
+	if len(fields) == 3 {
+		ts, err := strconv.ParseFloat(fields[2], 64)
+		if err != nil || math.IsNaN(ts) || math.IsInf(ts, 0) {
+			return s, fmt.Errorf("%w: %q: bad timestamp", ErrInvalidGraphite, line)
+		}
+		s.Timestamp = int64(ts)
+	}
+
+	return s, nil
+}
+
+// GraphiteMetric maps sample to metric named by the first matching template or by the whole path.
+// Values are gauges unless rules make metric a counter, counter value has to be an integer.
+func (r *Rules) GraphiteMetric(s GraphiteSample) (domain.Metrics, error) {
+	parts := strings.Split(s.Path, ".")
+	tags := s.Tags
+
+	if t := r.graphiteTemplate(parts); t != nil {
+		var templateTags []Tag
+		parts, templateTags = t.apply(parts)
+		tags = append(templateTags, tags...)
+	}
+	if len(parts) == 0 {
+		return domain.Metrics{}, fmt.Errorf("%w: %q: template leaves empty name", ErrInvalidGraphite, s.Path)
+	}
+
+	name := r.taggedName(strings.Join(parts, "."), tags)
+	if r.TypeOf(name) != domain.CounterType {
+		return gauge(name, s.Value), nil
+	}
+
+	if s.Value != math.Trunc(s.Value) || s.Value >= math.MaxInt64 || s.Value < math.MinInt64 {
+		return domain.Metrics{}, fmt.Errorf("%w: %s", ErrCounterValue, name)
+	}
+	return counter(name, int64(s.Value)), nil
+}
+
+func (r *Rules) graphiteTemplate(parts []string) *GraphiteTemplate {
+	if r == nil {
+		return nil
+	}
+
+	for i := range r.GraphiteTemplates {
+		if t := &r.GraphiteTemplates[i]; t.parts != nil && t.match(parts) {
+			return t
+		}
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package ingest
+
+import (
+	"testing"
+
+	"github.com/frolmr/metrics/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseGraphiteLine(t *testing.T) {
+	tests := []struct {
+		name    string
+		line    string
+		want    GraphiteSample
+		wantErr bool
+	}{
+		{
+			name: "path value timestamp",
+			line: "servers.web1.cpu.load 0.75 1700000000\n",
+			want: GraphiteSample{Path: "servers.web1.cpu.load", Value: 0.75, Timestamp: 1700000000},
+		},
+		{
+			name: "without timestamp",
+			line: "app.requests 10",
+			want: GraphiteSample{Path: "app.requests", Value: 10, Timestamp: -1},
+		},
+		{
+			name: "tagged series",
+			line: "cpu.load;host=web1;dc=eu 1.5 -1",
+			want: GraphiteSample{
+				Path:      "cpu.load",
+				Tags:      []Tag{{Key: "host", Value: "web1"}, {Key: "dc", Value: "eu"}},
+				Value:     1.5,
+				Timestamp: -1,
+			},
+		},
+		{name: "missing value", line: "app.requests", wantErr: true},
+		{name: "extra fields", line: "app.requests 1 2 3", wantErr: true},
+		{name: "bad value", line: "app.requests x 1700000000", wantErr: true},
+		{name: "bad timestamp", line: "app.requests 1 now", wantErr: true},
+		{name: "bad tag", line: "app.requests;host 1", wantErr: true},
+		{name: "empty path", line: ";host=a 1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseGraphiteLine(tt.line)
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrInvalidGraphite)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRules_GraphiteMetric(t *testing.T) {
+	rules := &Rules{
+		Rules: []Rule{{Match: `\.requests(_|$)`, Type: domain.CounterType}},
+		Tags:  []string{"host"},
+		GraphiteTemplates: []GraphiteTemplate{
+			{Filter: "servers.*", Template: ".host.measurement*"},
+			{Filter: "stats.??", Template: "..measurement.env"},
+		},
+	}
+	require.NoError(t, rules.Compile())
+
+	tests := []struct {
+		name    string
+		sample  GraphiteSample
+		want    domain.Metrics
+		wantErr error
+	}{
+		{
+			name:   "template with rest of path",
+			sample: GraphiteSample{Path: "servers.web1.cpu.load", Value: 0.5},
+			want:   gauge("cpu.load_web1", 0.5),
+		},
+		{
+			name:   "template with glob and extra parts",
+			sample: GraphiteSample{Path: "stats.eu.app.prod.latency", Value: 12},
+			want:   gauge("app.latency", 12),
+		},
+		{
+			name:   "no matching template",
+			sample: GraphiteSample{Path: "stats.europe.app.prod", Value: 1},
+			want:   gauge("stats.europe.app.prod", 1),
+		},
+		{
+			name:   "counter by rule with series tag",
+			sample: GraphiteSample{Path: "app.requests", Tags: []Tag{{Key: "host", Value: "web2"}}, Value: 3},
+			want:   counter("app.requests_web2", 3),
+		},
+		{
+			name:    "fractional counter",
+			sample:  GraphiteSample{Path: "app.requests", Value: 1.5},
+			wantErr: ErrCounterValue,
+		},
+		{
+			name:    "template leaves no name",
+			sample:  GraphiteSample{Path: "servers.web1", Value: 1},
+			wantErr: ErrInvalidGraphite,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := rules.GraphiteMetric(tt.sample)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestGraphiteTemplate_Compile(t *testing.T) {
+	for _, tmpl := range []GraphiteTemplate{
+		{Template: ""},
+		{Template: "measurement*.host"},
+		{Filter: "servers.[", Template: "measurement"},
+	} {
+		rules := &Rules{GraphiteTemplates: []GraphiteTemplate{tmpl}}
+		assert.ErrorIs(t, rules.Compile(), ErrInvalidRules, tmpl.Template)
+	}
+}
@@ -0,0 +1,295 @@
+package ingest
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/frolmr/metrics/internal/domain"
+)
+
+var (
+	ErrInvalidLine      = errors.New("invalid line protocol")
+	ErrCounterValue     = errors.New("counter value out of range")
+	ErrUnknownPrecision = errors.New("unknown precision")
+)
+
+// maxLineSize limits a single line of line protocol.
+const maxLineSize = 64 * 1024
+
+// Tag is a key-value pair of line protocol tag set.
+type Tag struct {
+	Key   string
+	Value string
+}
+
+// Field is a key-value pair of line protocol field set, Value is float64, int64, uint64, string or bool.
+type Field struct {
+	Key   string
+	Value any
+}
+
+// Point is a parsed line of InfluxDB line protocol.
+type Point struct {
+	Measurement string
+	Tags        []Tag
+	Fields      []Field
+	// Timestamp is zero if line has no timestamp.
+	Timestamp int64
+	// Line is the number of input line set by ParseLineProtocol.
+	Line int
+}
+
+// LineError is a line protocol line that failed to parse or map.
+type LineError struct {
+	Line  int    `json:"line"`
+	Error string `json:"error"`
+}
+
+// CheckPrecision checks precision query parameter of InfluxDB write API.
+func CheckPrecision(precision string) error {
+	switch precision {
+	case "", "ns", "n", "us", "u", "ms", "s", "m", "h":
+		return nil
+	default:
+		return fmt.Errorf("%w: %q", ErrUnknownPrecision, precision)
+	}
+}
+
+// ParseLineProtocol reads points line by line, lines that fail to parse are reported
+// and don't stop parsing of the rest. Empty lines and comments are skipped.
+func ParseLineProtocol(r io.Reader) ([]Point, []LineError, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 4096), maxLineSize)
+
+	var (
+		points []Point
+		errs   []LineError
+	)
+
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		p, err := ParseLine(line)
+		if err != nil {
+			errs = append(errs, LineError{Line: n, Error: err.Error()})
+			continue
+		}
+		p.Line = n
+		points = append(points, p)
+	}
+
+	return points, errs, scanner.Err()
+}
+
+// ParseLine parses single line of line protocol.
+func ParseLine(line string) (Point, error) {
+	var p Point
+
+	measurement, rest, sep := scanUntil(line, ", ")
+	if measurement == "" {
+		return p, fmt.Errorf("%w: missing measurement", ErrInvalidLine)
+	}
+	p.Measurement = unescape(measurement)
+
+	if sep == ',' {
+		var tags string
+		tags, rest, _ = scanUntil(rest, " ")
+		for tags != "" {
+			var pair string
+			pair, tags, _ = scanUntil(tags, ",")
+			key, value, ok := splitPair(pair)
+			if !ok || value == "" {
+				return p, fmt.Errorf("%w: bad tag %q", ErrInvalidLine, pair)
+			}
+			p.Tags = append(p.Tags, Tag{Key: key, Value: unescape(value)})
+		}
+	}
+
+	fields, timestamp := splitFields(rest)
+	if fields == "" {
+		return p, fmt.Errorf("%w: missing fields", ErrInvalidLine)
+	}
+
+	for fields != "" {
+		var pair string
+		pair, fields = scanField(fields)
+		key, raw, ok := splitPair(pair)
+		if !ok {
+			return p, fmt.Errorf("%w: bad field %q", ErrInvalidLine, pair)
+		}
+
+		value, err := parseFieldValue(raw)
+		if err != nil {
+			return p, fmt.Errorf("%w: field %q: %w", ErrInvalidLine, key, err)
+		}
+		p.Fields = append(p.Fields, Field{Key: key, Value: value})
+	}
+
+	if timestamp != "" {
+		ts, err := strconv.ParseInt(timestamp, 10, 64)
+		if err != nil {
+			return p, fmt.Errorf("%w: bad timestamp %q", ErrInvalidLine, timestamp)
+		}
+		p.Timestamp = ts
+	}
+
+	return p, nil
+}
+
+// LineProtocolConverter maps line protocol points to domain metrics.
+// Integer fields typed as counters are cumulative in Telegraf, so they are converted to deltas
+// starting from the second point of every series.
+type LineProtocolConverter struct {
+	rules   *Rules
+	tracker *CumulativeTracker
+}
+
+// NewLineProtocolConverter function is constructor for line protocol converter.
+func NewLineProtocolConverter(rules *Rules) *LineProtocolConverter {
+	return &LineProtocolConverter{
+		rules:   rules,
+		tracker: NewCumulativeTracker(),
+	}
+}
+
+// PointMetrics maps numeric fields of point to metrics named measurement[_tag values]_field.
+// Float fields are gauges, integer fields get type from rules. String and boolean fields are skipped.
+func (c *LineProtocolConverter) PointMetrics(p Point) ([]domain.Metrics, error) {
+	prefix := c.rules.taggedName(p.Measurement, p.Tags) + "_"
+
+	metrics := make([]domain.Metrics, 0, len(p.Fields))
+	for _, f := range p.Fields {
+		name := prefix + f.Key
+
+		var integer int64
+		switch v := f.Value.(type) {
+		case float64:
+			metrics = append(metrics, gauge(name, v))
+			continue
+		case int64:
+			integer = v
+		case uint64:
+			if c.rules.TypeOf(name) == domain.GaugeType {
+				metrics = append(metrics, gauge(name, float64(v)))
+				continue
+			}
+			if v > math.MaxInt64 {
+				return nil, fmt.Errorf("%w: %s", ErrCounterValue, name)
+			}
+			integer = int64(v)
+		default:
+			continue
+		}
+
+		if c.rules.TypeOf(name) != domain.CounterType {
+			metrics = append(metrics, gauge(name, float64(integer)))
+			continue
+		}
+		if delta, ok := c.tracker.Delta(name, 0, float64(integer)); ok {
+			metrics = append(metrics, counter(name, roundDelta(delta)))
+		}
+	}
+	return metrics, nil
+}
+
+func gauge(name string, value float64) domain.Metrics {
+	return domain.Metrics{ID: name, MType: domain.GaugeType, Value: &value}
+}
+
+func counter(name string, delta int64) domain.Metrics {
+	return domain.Metrics{ID: name, MType: domain.CounterType, Delta: &delta}
+}
+
+// scanUntil returns still escaped part of s before the first unescaped separator, the rest after it and the separator.
+func scanUntil(s, separators string) (string, string, byte) {
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && i+1 < len(s):
+			i++
+		case strings.IndexByte(separators, s[i]) >= 0:
+			return s[:i], s[i+1:], s[i]
+		}
+	}
+	return s, "", 0
+}
+
+// splitFields separates field set from optional timestamp, spaces inside quoted strings are kept.
+func splitFields(s string) (string, string) {
+	quoted := false
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && i+1 < len(s):
+			i++
+		case s[i] == '"':
+			quoted = !quoted
+		case s[i] == ' ' && !quoted:
+			return s[:i], strings.TrimSpace(s[i+1:])
+		}
+	}
+	return s, ""
+}
+
+// scanField returns the first field of field set, commas inside quoted strings are kept.
+func scanField(s string) (string, string) {
+	quoted := false
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && i+1 < len(s):
+			i++
+		case s[i] == '"':
+			quoted = !quoted
+		case s[i] == ',' && !quoted:
+			return s[:i], s[i+1:]
+		}
+	}
+	return s, ""
+}
+
+// splitPair splits key=value by the first unescaped equal sign, key is unescaped.
+func splitPair(s string) (string, string, bool) {
+	key, value, sep := scanUntil(s, "=")
+	if key == "" || sep == 0 {
+		return "", "", false
+	}
+	return unescape(key), value, true
+}
+
+func parseFieldValue(raw string) (any, error) {
+	switch {
+	case raw == "":
+		return nil, errors.New("empty value")
+	case strings.HasPrefix(raw, `"`):
+		if len(raw) < 2 || !strings.HasSuffix(raw, `"`) {
+			return nil, errors.New("unterminated string")
+		}
+		return strings.NewReplacer(`\"`, `"`, `\\`, `\`).Replace(raw[1 : len(raw)-1]), nil
+	case strings.HasSuffix(raw, "i"):
+		return strconv.ParseInt(raw[:len(raw)-1], 10, 64)
+	case strings.HasSuffix(raw, "u"):
+		return strconv.ParseUint(raw[:len(raw)-1], 10, 64)
+	}
+
+	switch raw {
+	case "t", "T", "true", "True", "TRUE":
+		return true, nil
+	case "f", "F", "false", "False", "FALSE":
+		return false, nil
+	}
+
+	return strconv.ParseFloat(raw, 64)
+}
+
+func unescape(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	return strings.NewReplacer(`\,`, ",", `\ `, " ", `\=`, "=", `\\`, `\`).Replace(s)
+}
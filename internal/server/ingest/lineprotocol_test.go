@@ -0,0 +1,146 @@
+package ingest
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/frolmr/metrics/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLine(t *testing.T) {
+	tests := []struct {
+		name    string
+		line    string
+		want    Point
+		wantErr bool
+	}{
+		{
+			name: "fields only",
+			line: "cpu usage=0.5",
+			want: Point{Measurement: "cpu", Fields: []Field{{Key: "usage", Value: 0.5}}},
+		},
+		{
+			name: "tags fields and timestamp",
+			line: "mem,host=a,region=eu used=10i,free=20u,ok=true 1700000000000000000",
+			want: Point{
+				Measurement: "mem",
+				Tags:        []Tag{{Key: "host", Value: "a"}, {Key: "region", Value: "eu"}},
+				Fields: []Field{
+					{Key: "used", Value: int64(10)},
+					{Key: "free", Value: uint64(20)},
+					{Key: "ok", Value: true},
+				},
+				Timestamp: 1700000000000000000,
+			},
+		},
+		{
+			name: "escapes and quoted strings",
+			line: `disk\ io,path=C:\\data\,x,label=a\=b msg="hello, \"world\" x=1",value=2`,
+			want: Point{
+				Measurement: "disk io",
+				Tags:        []Tag{{Key: "path", Value: `C:\data,x`}, {Key: "label", Value: "a=b"}},
+				Fields: []Field{
+					{Key: "msg", Value: `hello, "world" x=1`},
+					{Key: "value", Value: 2.0},
+				},
+			},
+		},
+		{name: "missing fields", line: "cpu", wantErr: true},
+		{name: "missing fields with tags", line: "cpu,host=a", wantErr: true},
+		{name: "bad tag", line: "cpu,host usage=1", wantErr: true},
+		{name: "empty tag value", line: "cpu,host= usage=1", wantErr: true},
+		{name: "bad field", line: "cpu usage", wantErr: true},
+		{name: "bad value", line: "cpu usage=abc", wantErr: true},
+		{name: "bad integer", line: "cpu usage=1.5i", wantErr: true},
+		{name: "unterminated string", line: `cpu msg="abc`, wantErr: true},
+		{name: "bad timestamp", line: "cpu usage=1 now", wantErr: true},
+		{name: "missing measurement", line: ",host=a usage=1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseLine(tt.line)
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrInvalidLine)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseLineProtocol(t *testing.T) {
+	input := "# comment\ncpu usage=0.5\n\nbad line\nmem used=1i 1700000000\n"
+
+	points, errs, err := ParseLineProtocol(strings.NewReader(input))
+	require.NoError(t, err)
+
+	require.Len(t, points, 2)
+	assert.Equal(t, 2, points[0].Line)
+	assert.Equal(t, 5, points[1].Line)
+	assert.Equal(t, int64(1700000000), points[1].Timestamp)
+
+	require.Len(t, errs, 1)
+	assert.Equal(t, 4, errs[0].Line)
+	assert.NotEmpty(t, errs[0].Error)
+}
+
+func TestCheckPrecision(t *testing.T) {
+	for _, p := range []string{"", "ns", "us", "ms", "s"} {
+		assert.NoError(t, CheckPrecision(p))
+	}
+	assert.ErrorIs(t, CheckPrecision("d"), ErrUnknownPrecision)
+}
+
+func TestLineProtocolConverter_PointMetrics(t *testing.T) {
+	rules := &Rules{
+		Rules: []Rule{{Match: "_requests$", Type: domain.CounterType}},
+		Tags:  []string{"host", "missing"},
+	}
+	require.NoError(t, rules.Compile())
+	c := NewLineProtocolConverter(rules)
+
+	p, err := ParseLine(`http,host=web1,path=/ requests=5i,latency=0.25,in_flight=3i,version="1.2",up=true`)
+	require.NoError(t, err)
+
+	metrics, err := c.PointMetrics(p)
+	require.NoError(t, err)
+
+	latency, inFlight := 0.25, 3.0
+	assert.Equal(t, []domain.Metrics{
+		{ID: "http_web1_latency", MType: domain.GaugeType, Value: &latency},
+		{ID: "http_web1_in_flight", MType: domain.GaugeType, Value: &inFlight},
+	}, metrics, "the first point of counter is the baseline")
+
+	t.Run("cumulative counter", func(t *testing.T) {
+		c := NewLineProtocolConverter(rules)
+		requests := func(value int64) []domain.Metrics {
+			metrics, err := c.PointMetrics(Point{Measurement: "http", Fields: []Field{{Key: "requests", Value: value}}})
+			require.NoError(t, err)
+			return metrics
+		}
+
+		assert.Empty(t, requests(100))
+		assert.Equal(t, []domain.Metrics{counter("http_requests", 0)}, requests(100), "the same total adds nothing")
+		assert.Equal(t, []domain.Metrics{counter("http_requests", 20)}, requests(120))
+		assert.Equal(t, []domain.Metrics{counter("http_requests", 7)}, requests(7), "decreased total is a restart")
+	})
+
+	t.Run("counter out of range", func(t *testing.T) {
+		p := Point{Measurement: "http", Fields: []Field{{Key: "requests", Value: uint64(math.MaxUint64)}}}
+		_, err := c.PointMetrics(p)
+		require.ErrorIs(t, err, ErrCounterValue)
+	})
+
+	t.Run("nil rules", func(t *testing.T) {
+		metrics, err := NewLineProtocolConverter(nil).PointMetrics(Point{Measurement: "cpu", Fields: []Field{{Key: "n", Value: int64(2)}}})
+		require.NoError(t, err)
+		require.Len(t, metrics, 1)
+		assert.Equal(t, domain.GaugeType, metrics[0].MType)
+		assert.Equal(t, "cpu_n", metrics[0].ID)
+	})
+}
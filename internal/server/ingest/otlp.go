@@ -0,0 +1,244 @@
+package ingest
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/frolmr/metrics/internal/domain"
+	otlp "github.com/frolmr/metrics/pkg/proto/otlp"
+)
+
+// otlpNoRecordedValue is OTLP data point flag telling that point has no value.
+const otlpNoRecordedValue = 1
+
+var ErrUnsupportedOTLP = errors.New("unsupported otlp metric")
+
+// CumulativeTracker turns cumulative values into deltas, it keeps the last value of every series.
+type CumulativeTracker struct {
+	mu     sync.Mutex
+	series map[string]cumulativePoint
+}
+
+type cumulativePoint struct {
+	start uint64
+	value float64
+}
+
+// NewCumulativeTracker function is constructor for cumulative tracker.
+func NewCumulativeTracker() *CumulativeTracker {
+	return &CumulativeTracker{
+		series: make(map[string]cumulativePoint),
+	}
+}
+
+// Delta returns change of series value since its previous point, ok is false for the first point of series
+// as there is nothing to compare it with. Changed start time or decreased value mean that series was restarted,
+// so the whole value is the delta.
+func (t *CumulativeTracker) Delta(key string, start uint64, value float64) (float64, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	prev, ok := t.series[key]
+	t.series[key] = cumulativePoint{start: start, value: value}
+
+	switch {
+	case !ok:
+		return 0, false
+	case start != prev.start || value < prev.value:
+		return value, true
+	default:
+		return value - prev.value, true
+	}
+}
+
+// OTLPConverter translates OTLP metrics to domain metrics.
+// Gauges and non-monotonic cumulative sums become gauges, monotonic sums and delta sums become counters.
+// Histogram becomes name_count counter together with name_mean, name_min and name_max gauges.
+type OTLPConverter struct {
+	rules   *Rules
+	tracker *CumulativeTracker
+}
+
+// NewOTLPConverter function is constructor for OTLP converter, rules add attribute values to metric names.
+func NewOTLPConverter(rules *Rules) *OTLPConverter {
+	return &OTLPConverter{
+		rules:   rules,
+		tracker: NewCumulativeTracker(),
+	}
+}
+
+// Convert returns metrics of request and errors of data points which can't be converted.
+func (c *OTLPConverter) Convert(req *otlp.ExportMetricsServiceRequest) ([]domain.Metrics, []error) {
+	var (
+		metrics []domain.Metrics
+		errs    []error
+	)
+
+	for _, rm := range req.GetResourceMetrics() {
+		resource := attributeTags(rm.GetResource().GetAttributes())
+		for _, sm := range rm.GetScopeMetrics() {
+			for _, m := range sm.GetMetrics() {
+				converted, convErrs := c.convertMetric(m, resource)
+				metrics = append(metrics, converted...)
+				errs = append(errs, convErrs...)
+			}
+		}
+	}
+
+	return metrics, errs
+}
+
+func (c *OTLPConverter) convertMetric(m *otlp.Metric, resource []Tag) ([]domain.Metrics, []error) {
+	var (
+		metrics []domain.Metrics
+		errs    []error
+	)
+
+	switch data := m.GetData().(type) {
+	case *otlp.Metric_Gauge:
+		for _, p := range data.Gauge.GetDataPoints() {
+			if p.GetFlags()&otlpNoRecordedValue != 0 {
+				continue
+			}
+			name, _ := c.series(m.GetName(), p.GetAttributes(), resource)
+			metrics = append(metrics, gauge(name, numberValue(p)))
+		}
+	case *otlp.Metric_Sum:
+		for _, p := range data.Sum.GetDataPoints() {
+			if p.GetFlags()&otlpNoRecordedValue != 0 {
+				continue
+			}
+			metric, ok, err := c.convertSum(m.GetName(), data.Sum, p, resource)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			if ok {
+				metrics = append(metrics, metric)
+			}
+		}
+	case *otlp.Metric_Histogram:
+		for _, p := range data.Histogram.GetDataPoints() {
+			if p.GetFlags()&otlpNoRecordedValue != 0 {
+				continue
+			}
+			histogram, err := c.convertHistogram(m.GetName(), data.Histogram.GetAggregationTemporality(), p, resource)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			metrics = append(metrics, histogram...)
+		}
+	default:
+		errs = append(errs, fmt.Errorf("%w: %s: unknown data kind", ErrUnsupportedOTLP, m.GetName()))
+	}
+
+	return metrics, errs
+}
+
+func (c *OTLPConverter) convertSum(name string, sum *otlp.Sum, p *otlp.NumberDataPoint, resource []Tag) (domain.Metrics, bool, error) {
+	name, key := c.series(name, p.GetAttributes(), resource)
+
+	value := numberValue(p)
+	if !sum.GetIsMonotonic() && sum.GetAggregationTemporality() == otlp.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE {
+		return gauge(name, value), true, nil
+	}
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return domain.Metrics{}, false, fmt.Errorf("%w: %s", ErrCounterValue, name)
+	}
+
+	switch sum.GetAggregationTemporality() {
+	case otlp.AggregationTemporality_AGGREGATION_TEMPORALITY_DELTA:
+		if v, ok := p.GetValue().(*otlp.NumberDataPoint_AsInt); ok {
+			return counter(name, v.AsInt), true, nil
+		}
+		return counter(name, roundDelta(value)), true, nil
+	case otlp.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE:
+		delta, ok := c.tracker.Delta(key, p.GetStartTimeUnixNano(), value)
+		return counter(name, roundDelta(delta)), ok, nil
+	default:
+		return domain.Metrics{}, false, fmt.Errorf("%w: %s: unspecified temporality", ErrUnsupportedOTLP, name)
+	}
+}
+
+func (c *OTLPConverter) convertHistogram(name string, temporality otlp.AggregationTemporality,
+	p *otlp.HistogramDataPoint, resource []Tag) ([]domain.Metrics, error) {
+	name, key := c.series(name, p.GetAttributes(), resource)
+
+	count, sum := float64(p.GetCount()), p.GetSum()
+	switch temporality {
+	case otlp.AggregationTemporality_AGGREGATION_TEMPORALITY_DELTA:
+	case otlp.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE:
+		// NOTE: the first point of series gives zero deltas, so only min and max are reported for it
+		count, _ = c.tracker.Delta(key+"\x00count", p.GetStartTimeUnixNano(), count)
+		sum, _ = c.tracker.Delta(key+"\x00sum", p.GetStartTimeUnixNano(), sum)
+	default:
+		return nil, fmt.Errorf("%w: %s: unspecified temporality", ErrUnsupportedOTLP, name)
+	}
+
+	var metrics []domain.Metrics
+	if count != 0 {
+		metrics = append(metrics, counter(name+"_count", roundDelta(count)))
+		if p.Sum != nil {
+			metrics = append(metrics, gauge(name+"_mean", sum/count))
+		}
+	}
+	if p.Min != nil {
+		metrics = append(metrics, gauge(name+"_min", p.GetMin()))
+	}
+	if p.Max != nil {
+		metrics = append(metrics, gauge(name+"_max", p.GetMax()))
+	}
+	return metrics, nil
+}
+
+// series returns metric name with tag values and key identifying series by all its attributes.
+func (c *OTLPConverter) series(name string, attributes []*otlp.KeyValue, resource []Tag) (string, string) {
+	// NOTE: data point attributes go first so that they win over resource ones with the same key
+	tags := append(attributeTags(attributes), resource...)
+
+	pairs := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		pairs = append(pairs, tag.Key+"="+tag.Value)
+	}
+	sort.Strings(pairs)
+
+	return c.rules.taggedName(name, tags), name + "\x00" + strings.Join(pairs, "\x00")
+}
+
+func numberValue(p *otlp.NumberDataPoint) float64 {
+	if v, ok := p.GetValue().(*otlp.NumberDataPoint_AsInt); ok {
+		return float64(v.AsInt)
+	}
+	return p.GetAsDouble()
+}
+
+// attributeTags converts scalar attributes to tags, arrays, maps and bytes are skipped.
+func attributeTags(attributes []*otlp.KeyValue) []Tag {
+	tags := make([]Tag, 0, len(attributes))
+	for _, kv := range attributes {
+		var value string
+		switch v := kv.GetValue().GetValue().(type) {
+		case *otlp.AnyValue_StringValue:
+			value = v.StringValue
+		case *otlp.AnyValue_BoolValue:
+			value = strconv.FormatBool(v.BoolValue)
+		case *otlp.AnyValue_IntValue:
+			value = strconv.FormatInt(v.IntValue, 10)
+		case *otlp.AnyValue_DoubleValue:
+			if math.IsNaN(v.DoubleValue) || math.IsInf(v.DoubleValue, 0) {
+				continue
+			}
+			value = strconv.FormatFloat(v.DoubleValue, 'f', -1, 64)
+		default:
+			continue
+		}
+		tags = append(tags, Tag{Key: kv.GetKey(), Value: value})
+	}
+	return tags
+}
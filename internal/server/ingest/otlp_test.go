@@ -0,0 +1,176 @@
+package ingest
+
+import (
+	"math"
+	"testing"
+
+	"github.com/frolmr/metrics/internal/domain"
+	otlp "github.com/frolmr/metrics/pkg/proto/otlp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func stringAttr(key, value string) *otlp.KeyValue {
+	return &otlp.KeyValue{Key: key, Value: &otlp.AnyValue{Value: &otlp.AnyValue_StringValue{StringValue: value}}}
+}
+
+func intPoint(start uint64, value int64, attrs ...*otlp.KeyValue) *otlp.NumberDataPoint {
+	return &otlp.NumberDataPoint{
+		Attributes:        attrs,
+		StartTimeUnixNano: start,
+		Value:             &otlp.NumberDataPoint_AsInt{AsInt: value},
+	}
+}
+
+func doublePoint(value float64, attrs ...*otlp.KeyValue) *otlp.NumberDataPoint {
+	return &otlp.NumberDataPoint{Attributes: attrs, Value: &otlp.NumberDataPoint_AsDouble{AsDouble: value}}
+}
+
+func exportRequest(resource []*otlp.KeyValue, metrics ...*otlp.Metric) *otlp.ExportMetricsServiceRequest {
+	return &otlp.ExportMetricsServiceRequest{
+		ResourceMetrics: []*otlp.ResourceMetrics{{
+			Resource:     &otlp.Resource{Attributes: resource},
+			ScopeMetrics: []*otlp.ScopeMetrics{{Metrics: metrics}},
+		}},
+	}
+}
+
+func cumulativeSum(name string, monotonic bool, points ...*otlp.NumberDataPoint) *otlp.Metric {
+	return &otlp.Metric{Name: name, Data: &otlp.Metric_Sum{Sum: &otlp.Sum{
+		DataPoints:             points,
+		AggregationTemporality: otlp.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE,
+		IsMonotonic:            monotonic,
+	}}}
+}
+
+func TestCumulativeTracker(t *testing.T) {
+	tracker := NewCumulativeTracker()
+
+	_, ok := tracker.Delta("a", 1, 10)
+	assert.False(t, ok, "first point has nothing to compare with")
+
+	delta, ok := tracker.Delta("a", 1, 15)
+	assert.True(t, ok)
+	assert.InDelta(t, 5.0, delta, 1e-9)
+
+	delta, _ = tracker.Delta("a", 1, 3)
+	assert.InDelta(t, 3.0, delta, 1e-9, "decreased value means reset")
+
+	delta, _ = tracker.Delta("a", 2, 4)
+	assert.InDelta(t, 4.0, delta, 1e-9, "new start time means reset")
+
+	_, ok = tracker.Delta("b", 1, 10)
+	assert.False(t, ok, "series are tracked separately")
+}
+
+func TestOTLPConverter(t *testing.T) {
+	rules := &Rules{Tags: []string{"service.name", "method"}}
+	c := NewOTLPConverter(rules)
+
+	resource := []*otlp.KeyValue{stringAttr("service.name", "api")}
+	sum := cumulativeSum("http.requests", true,
+		intPoint(1, 10, stringAttr("method", "GET")),
+		intPoint(1, 4, stringAttr("method", "POST")))
+
+	request := exportRequest(resource,
+		&otlp.Metric{Name: "cpu.load", Data: &otlp.Metric_Gauge{Gauge: &otlp.Gauge{DataPoints: []*otlp.NumberDataPoint{
+			doublePoint(0.5),
+			{Flags: otlpNoRecordedValue},
+		}}}},
+		sum,
+		cumulativeSum("queue.size", false, intPoint(1, 7)),
+		&otlp.Metric{Name: "jobs.done", Data: &otlp.Metric_Sum{Sum: &otlp.Sum{
+			DataPoints:             []*otlp.NumberDataPoint{doublePoint(2.6)},
+			AggregationTemporality: otlp.AggregationTemporality_AGGREGATION_TEMPORALITY_DELTA,
+			IsMonotonic:            true,
+		}}},
+		&otlp.Metric{Name: "empty"},
+	)
+
+	metrics, errs := c.Convert(request)
+	require.Len(t, errs, 1)
+	assert.ErrorIs(t, errs[0], ErrUnsupportedOTLP)
+
+	load, queue, jobs := 0.5, 7.0, int64(3)
+	assert.Equal(t, []domain.Metrics{
+		{ID: "cpu.load_api", MType: domain.GaugeType, Value: &load},
+		{ID: "queue.size_api", MType: domain.GaugeType, Value: &queue},
+		{ID: "jobs.done_api", MType: domain.CounterType, Delta: &jobs},
+	}, metrics, "cumulative counters are reported from the second point")
+
+	sum.GetSum().DataPoints = []*otlp.NumberDataPoint{
+		intPoint(1, 25, stringAttr("method", "GET")),
+		intPoint(1, 4, stringAttr("method", "POST")),
+	}
+	metrics, errs = c.Convert(exportRequest(resource, sum))
+	require.Empty(t, errs)
+
+	get, post := int64(15), int64(0)
+	assert.Equal(t, []domain.Metrics{
+		{ID: "http.requests_api_GET", MType: domain.CounterType, Delta: &get},
+		{ID: "http.requests_api_POST", MType: domain.CounterType, Delta: &post},
+	}, metrics)
+}
+
+func TestOTLPConverter_Histogram(t *testing.T) {
+	c := NewOTLPConverter(nil)
+
+	histogram := func(temporality otlp.AggregationTemporality, count uint64, sum float64) *otlp.Metric {
+		return &otlp.Metric{Name: "latency", Data: &otlp.Metric_Histogram{Histogram: &otlp.Histogram{
+			AggregationTemporality: temporality,
+			DataPoints: []*otlp.HistogramDataPoint{{
+				StartTimeUnixNano: 1,
+				Count:             count,
+				Sum:               proto.Float64(sum),
+				Min:               proto.Float64(1),
+				Max:               proto.Float64(9),
+			}},
+		}}}
+	}
+
+	metricsByID := func(metrics []domain.Metrics) map[string]domain.Metrics {
+		byID := make(map[string]domain.Metrics, len(metrics))
+		for _, m := range metrics {
+			byID[m.ID] = m
+		}
+		return byID
+	}
+
+	delta := otlp.AggregationTemporality_AGGREGATION_TEMPORALITY_DELTA
+	metrics, errs := c.Convert(exportRequest(nil, histogram(delta, 4, 20)))
+	require.Empty(t, errs)
+	got := metricsByID(metrics)
+	require.Len(t, got, 4)
+	assert.Equal(t, int64(4), *got["latency_count"].Delta)
+	assert.InDelta(t, 5.0, *got["latency_mean"].Value, 1e-9)
+	assert.InDelta(t, 1.0, *got["latency_min"].Value, 1e-9)
+	assert.InDelta(t, 9.0, *got["latency_max"].Value, 1e-9)
+
+	cumulative := otlp.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE
+	metrics, errs = c.Convert(exportRequest(nil, histogram(cumulative, 4, 20)))
+	require.Empty(t, errs)
+	assert.Len(t, metrics, 2, "first cumulative point has only min and max")
+
+	metrics, errs = c.Convert(exportRequest(nil, histogram(cumulative, 6, 40)))
+	require.Empty(t, errs)
+	got = metricsByID(metrics)
+	assert.Equal(t, int64(2), *got["latency_count"].Delta)
+	assert.InDelta(t, 10.0, *got["latency_mean"].Value, 1e-9)
+
+	_, errs = c.Convert(exportRequest(nil, histogram(otlp.AggregationTemporality_AGGREGATION_TEMPORALITY_UNSPECIFIED, 1, 1)))
+	require.Len(t, errs, 1)
+	assert.ErrorIs(t, errs[0], ErrUnsupportedOTLP)
+}
+
+func TestOTLPConverter_NonFiniteCounter(t *testing.T) {
+	c := NewOTLPConverter(nil)
+
+	_, errs := c.Convert(exportRequest(nil, &otlp.Metric{Name: "jobs", Data: &otlp.Metric_Sum{Sum: &otlp.Sum{
+		DataPoints:             []*otlp.NumberDataPoint{doublePoint(math.Inf(1))},
+		AggregationTemporality: otlp.AggregationTemporality_AGGREGATION_TEMPORALITY_DELTA,
+		IsMonotonic:            true,
+	}}}))
+	require.Len(t, errs, 1)
+	assert.ErrorIs(t, errs[0], ErrCounterValue)
+}
@@ -0,0 +1,175 @@
+package ingest
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/frolmr/metrics/internal/domain"
+	prompb "github.com/frolmr/metrics/pkg/proto/prompb"
+)
+
+const (
+	// promNameLabel holds metric name among series labels.
+	promNameLabel = "__name__"
+	// promStaleNaN is the bit pattern of Prometheus staleness marker.
+	promStaleNaN uint64 = 0x7ff0000000000002
+)
+
+// promCounterSuffixes are suffixes of cumulative series of histograms and summaries.
+var promCounterSuffixes = []string{"_bucket", "_count", "_sum"}
+
+var ErrInvalidSeries = errors.New("invalid prometheus series")
+
+// PromConverter translates Prometheus remote write requests to domain metrics.
+// Metric type is taken from rules, then from metadata Prometheus sends from time to time, then series
+// with _total suffix are taken for counters and all the others for gauges. Counters are cumulative in
+// Prometheus, so they are converted to deltas starting from the second sample of every series.
+// Labels which are not listed in rules tags don't get into names: counters of such series are summed up
+// and gauges overwrite each other.
+type PromConverter struct {
+	rules   *Rules
+	tracker *CumulativeTracker
+
+	mu       sync.RWMutex
+	families map[string]prompb.MetricMetadata_MetricType
+}
+
+// NewPromConverter function is constructor for Prometheus converter.
+func NewPromConverter(rules *Rules) *PromConverter {
+	return &PromConverter{
+		rules:    rules,
+		tracker:  NewCumulativeTracker(),
+		families: make(map[string]prompb.MetricMetadata_MetricType),
+	}
+}
+
+// Convert remembers metadata of request and returns metrics of its series and errors of series which can't be converted.
+func (c *PromConverter) Convert(req *prompb.WriteRequest) ([]domain.Metrics, []error) {
+	c.learn(req.GetMetadata())
+
+	var (
+		metrics []domain.Metrics
+		errs    []error
+	)
+
+	for _, ts := range req.GetTimeseries() {
+		m, ok, err := c.convertSeries(ts)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if ok {
+			metrics = append(metrics, m)
+		}
+	}
+
+	return metrics, errs
+}
+
+func (c *PromConverter) learn(metadata []*prompb.MetricMetadata) {
+	if len(metadata) == 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, md := range metadata {
+		c.families[md.GetMetricFamilyName()] = md.GetType()
+	}
+}
+
+// convertSeries returns metric of the series, ok is false if series has nothing to save yet.
+func (c *PromConverter) convertSeries(ts *prompb.TimeSeries) (domain.Metrics, bool, error) {
+	var (
+		metricName string
+		tags       = make([]Tag, 0, len(ts.GetLabels()))
+		pairs      = make([]string, 0, len(ts.GetLabels()))
+	)
+	for _, l := range ts.GetLabels() {
+		pairs = append(pairs, l.GetName()+"="+l.GetValue())
+		if l.GetName() == promNameLabel {
+			metricName = l.GetValue()
+			continue
+		}
+		tags = append(tags, Tag{Key: l.GetName(), Value: l.GetValue()})
+	}
+	if metricName == "" {
+		return domain.Metrics{}, false, fmt.Errorf("%w: missing %s label", ErrInvalidSeries, promNameLabel)
+	}
+	sort.Strings(pairs)
+
+	samples := append([]*prompb.Sample(nil), ts.GetSamples()...)
+	sort.SliceStable(samples, func(i, j int) bool {
+		return samples[i].GetTimestamp() < samples[j].GetTimestamp()
+	})
+
+	name := c.rules.taggedName(metricName, tags)
+	key := strings.Join(pairs, "\x00")
+
+	if c.typeOf(metricName, name) == domain.GaugeType {
+		for i := len(samples) - 1; i >= 0; i-- {
+			if v := samples[i].GetValue(); math.Float64bits(v) != promStaleNaN {
+				return gauge(name, v), true, nil
+			}
+		}
+		return domain.Metrics{}, false, nil
+	}
+
+	var (
+		sum     float64
+		tracked bool
+	)
+	for _, s := range samples {
+		v := s.GetValue()
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			continue
+		}
+
+		// NOTE: whole parts are tracked, so fractional increments add up instead of being rounded away
+		if delta, ok := c.tracker.Delta(key, 0, math.Floor(v)); ok {
+			sum += delta
+			tracked = true
+		}
+	}
+	return counter(name, roundDelta(sum)), tracked, nil
+}
+
+func (c *PromConverter) typeOf(metricName, name string) string {
+	if mType, ok := c.rules.lookupType(name); ok {
+		return mType
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if mType, ok := c.families[metricName]; ok {
+		if mType == prompb.MetricMetadata_COUNTER {
+			return domain.CounterType
+		}
+		return domain.GaugeType
+	}
+
+	if family, ok := strings.CutSuffix(metricName, "_total"); ok {
+		if mType, known := c.families[family]; !known || mType == prompb.MetricMetadata_COUNTER {
+			return domain.CounterType
+		}
+	}
+
+	for _, suffix := range promCounterSuffixes {
+		family, ok := strings.CutSuffix(metricName, suffix)
+		if !ok {
+			continue
+		}
+		switch c.families[family] {
+		case prompb.MetricMetadata_HISTOGRAM, prompb.MetricMetadata_SUMMARY:
+			return domain.CounterType
+		}
+	}
+
+	return domain.GaugeType
+}
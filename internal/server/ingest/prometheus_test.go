@@ -0,0 +1,91 @@
+package ingest
+
+import (
+	"math"
+	"testing"
+
+	"github.com/frolmr/metrics/internal/domain"
+	prompb "github.com/frolmr/metrics/pkg/proto/prompb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func promSeries(name string, labels map[string]string, samples ...*prompb.Sample) *prompb.TimeSeries {
+	ts := &prompb.TimeSeries{
+		Labels:  []*prompb.Label{{Name: promNameLabel, Value: name}},
+		Samples: samples,
+	}
+	for k, v := range labels {
+		ts.Labels = append(ts.Labels, &prompb.Label{Name: k, Value: v})
+	}
+	return ts
+}
+
+func promSample(ts int64, value float64) *prompb.Sample {
+	return &prompb.Sample{Timestamp: ts, Value: value}
+}
+
+func TestPromConverter(t *testing.T) {
+	c := NewPromConverter(&Rules{Tags: []string{"method"}})
+
+	requests := func(get, post float64) *prompb.WriteRequest {
+		return &prompb.WriteRequest{Timeseries: []*prompb.TimeSeries{
+			promSeries("http_requests_total", map[string]string{"method": "GET", "instance": "a"}, promSample(1, get)),
+			promSeries("http_requests_total", map[string]string{"method": "POST", "instance": "a"}, promSample(1, post)),
+			promSeries("memory_bytes", map[string]string{"instance": "a"},
+				promSample(2, 20), promSample(1, 10), promSample(3, math.Float64frombits(promStaleNaN))),
+			{Labels: []*prompb.Label{{Name: "job", Value: "node"}}, Samples: []*prompb.Sample{promSample(1, 1)}},
+		}}
+	}
+
+	metrics, errs := c.Convert(requests(10, 4))
+	require.Len(t, errs, 1)
+	assert.ErrorIs(t, errs[0], ErrInvalidSeries)
+
+	memory := 20.0
+	assert.Equal(t, []domain.Metrics{
+		{ID: "memory_bytes", MType: domain.GaugeType, Value: &memory},
+	}, metrics, "counters are reported from the second sample, stale marker is skipped")
+
+	metrics, errs = c.Convert(requests(25.7, 3))
+	require.Len(t, errs, 1)
+
+	get, post := int64(15), int64(3)
+	assert.Equal(t, []domain.Metrics{
+		{ID: "http_requests_total_GET", MType: domain.CounterType, Delta: &get},
+		{ID: "http_requests_total_POST", MType: domain.CounterType, Delta: &post},
+		{ID: "memory_bytes", MType: domain.GaugeType, Value: &memory},
+	}, metrics, "decreased counter means reset")
+}
+
+func TestPromConverter_Types(t *testing.T) {
+	rules := &Rules{Rules: []Rule{{Match: "^forced$", Type: domain.CounterType}}}
+	require.NoError(t, rules.Compile())
+	c := NewPromConverter(rules)
+
+	c.Convert(&prompb.WriteRequest{Metadata: []*prompb.MetricMetadata{
+		{MetricFamilyName: "latency", Type: prompb.MetricMetadata_HISTOGRAM},
+		{MetricFamilyName: "temperature_total", Type: prompb.MetricMetadata_GAUGE},
+		{MetricFamilyName: "jobs", Type: prompb.MetricMetadata_COUNTER},
+	}})
+
+	tests := []struct {
+		name  string
+		mType string
+	}{
+		{name: "forced", mType: domain.CounterType},
+		{name: "requests_total", mType: domain.CounterType},
+		{name: "jobs", mType: domain.CounterType},
+		{name: "jobs_total", mType: domain.CounterType},
+		{name: "temperature_total", mType: domain.GaugeType},
+		{name: "latency_bucket", mType: domain.CounterType},
+		{name: "latency_count", mType: domain.CounterType},
+		{name: "load_count", mType: domain.GaugeType},
+		{name: "memory_bytes", mType: domain.GaugeType},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.mType, c.typeOf(tt.name, tt.name))
+		})
+	}
+}
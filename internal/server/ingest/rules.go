@@ -0,0 +1,126 @@
+// Package ingest converts metrics of foreign protocols to domain metrics.
+package ingest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/frolmr/metrics/internal/domain"
+)
+
+var ErrInvalidRules = errors.New("invalid ingest rules")
+
+// Rule sets type of metrics which names match the pattern.
+type Rule struct {
+	// Match is a regexp matched against resulting metric name.
+	Match string `json:"match"`
+	// Type is gauge or counter.
+	Type string `json:"type"`
+
+	re *regexp.Regexp
+}
+
+// Rules describe how foreign metrics are named and typed. Zero and nil Rules are valid:
+// every metric is a gauge and no tags get into names.
+type Rules struct {
+	// Rules are checked in order, the first matching one wins.
+	Rules []Rule `json:"rules"`
+	// Tags lists tag keys which values are added to metric name in this order.
+	Tags []string `json:"tags"`
+	// GraphiteTemplates extract tags from Graphite paths, the first one with matching filter is used.
+	GraphiteTemplates []GraphiteTemplate `json:"graphite_templates"`
+}
+
+// LoadRules reads rules from JSON file, empty path means default rules.
+func LoadRules(path string) (*Rules, error) {
+	if path == "" {
+		return &Rules{}, nil
+	}
+
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, err
+	}
+
+	var rules Rules
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidRules, err)
+	}
+	if err := rules.Compile(); err != nil {
+		return nil, err
+	}
+	return &rules, nil
+}
+
+// Compile checks rules, it has to be called before use of rules which are not loaded by LoadRules.
+func (r *Rules) Compile() error {
+	for i := range r.Rules {
+		rule := &r.Rules[i]
+
+		if rule.Type != domain.GaugeType && rule.Type != domain.CounterType {
+			return fmt.Errorf("%w: rule %d: unknown type %q", ErrInvalidRules, i, rule.Type)
+		}
+
+		re, err := regexp.Compile(rule.Match)
+		if err != nil {
+			return fmt.Errorf("%w: rule %d: %w", ErrInvalidRules, i, err)
+		}
+		rule.re = re
+	}
+
+	for i := range r.GraphiteTemplates {
+		if err := r.GraphiteTemplates[i].compile(); err != nil {
+			return fmt.Errorf("%w: graphite template %d: %w", ErrInvalidRules, i, err)
+		}
+	}
+	return nil
+}
+
+// TypeOf returns type of the first rule matching name, gauge if there is none.
+func (r *Rules) TypeOf(name string) string {
+	if mType, ok := r.lookupType(name); ok {
+		return mType
+	}
+	return domain.GaugeType
+}
+
+// lookupType returns type of the first rule matching name, ok is false if there is none.
+func (r *Rules) lookupType(name string) (string, bool) {
+	if r == nil {
+		return "", false
+	}
+
+	for _, rule := range r.Rules {
+		if rule.re != nil && rule.re.MatchString(name) {
+			return rule.Type, true
+		}
+	}
+	return "", false
+}
+
+// TagKeys returns keys of tags which go into metric names.
+func (r *Rules) TagKeys() []string {
+	if r == nil {
+		return nil
+	}
+	return r.Tags
+}
+
+// taggedName appends values of tags listed in rules to name.
+func (r *Rules) taggedName(name string, tags []Tag) string {
+	parts := []string{name}
+	for _, key := range r.TagKeys() {
+		for _, tag := range tags {
+			if tag.Key == key {
+				parts = append(parts, tag.Value)
+				break
+			}
+		}
+	}
+	return strings.Join(parts, "_")
+}
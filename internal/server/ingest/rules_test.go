@@ -0,0 +1,65 @@
+package ingest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/frolmr/metrics/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadRules(t *testing.T) {
+	dir := t.TempDir()
+
+	write := func(name, data string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(data), 0600))
+		return path
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		wantErr error
+	}{
+		{name: "empty path", path: ""},
+		{name: "valid", path: write("valid.json", `{"rules":[{"match":"^http_","type":"counter"}],"tags":["host"]}`)},
+		{name: "bad json", path: write("json.json", `{"rules":`), wantErr: ErrInvalidRules},
+		{name: "bad type", path: write("type.json", `{"rules":[{"match":"x","type":"summary"}]}`), wantErr: ErrInvalidRules},
+		{name: "bad regexp", path: write("re.json", `{"rules":[{"match":"(","type":"gauge"}]}`), wantErr: ErrInvalidRules},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules, err := LoadRules(tt.path)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.NotNil(t, rules)
+		})
+	}
+
+	_, err := LoadRules(filepath.Join(dir, "missing.json"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestRules_TypeOf(t *testing.T) {
+	rules := &Rules{Rules: []Rule{
+		{Match: "_total$", Type: domain.CounterType},
+		{Match: "^http_", Type: domain.GaugeType},
+		{Match: "^http_", Type: domain.CounterType},
+	}}
+	require.NoError(t, rules.Compile())
+
+	assert.Equal(t, domain.CounterType, rules.TypeOf("http_requests_total"))
+	assert.Equal(t, domain.GaugeType, rules.TypeOf("http_in_flight"))
+	assert.Equal(t, domain.GaugeType, rules.TypeOf("cpu"))
+
+	var nilRules *Rules
+	assert.Equal(t, domain.GaugeType, nilRules.TypeOf("http_requests_total"))
+	assert.Empty(t, nilRules.TagKeys())
+}
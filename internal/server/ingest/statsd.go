@@ -0,0 +1,235 @@
+package ingest
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/frolmr/metrics/internal/domain"
+)
+
+// StatsD metric types.
+const (
+	StatsDCounter = "c"
+	StatsDGauge   = "g"
+	StatsDTimer   = "ms"
+)
+
+var ErrInvalidStatsD = errors.New("invalid statsd metric")
+
+// timerPercentiles are reported for every timer in addition to min, max and mean.
+var timerPercentiles = []int{50, 90, 99}
+
+// StatsDSample is a parsed StatsD metric line name:value|type[|@rate][|#tags].
+type StatsDSample struct {
+	Name  string
+	Value float64
+	Type  string
+	// Relative is set for gauges with explicit sign, they change current value instead of setting it.
+	Relative bool
+	// SampleRate is in (0, 1], counters and timer counts are scaled by it.
+	SampleRate float64
+	// Tags are DogStatsD tags, tag without value has empty Value.
+	Tags []Tag
+}
+
+// ParseStatsD parses every non-empty line of packet, lines that fail to parse are reported
+// and don't stop parsing of the rest.
+func ParseStatsD(packet string) ([]StatsDSample, []error) {
+	var (
+		samples []StatsDSample
+		errs    []error
+	)
+
+	for _, line := range strings.Split(packet, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		s, err := ParseStatsDLine(line)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		samples = append(samples, s)
+	}
+
+	return samples, errs
+}
+
+// ParseStatsDLine parses single StatsD metric.
+func ParseStatsDLine(line string) (StatsDSample, error) {
+	s := StatsDSample{SampleRate: 1}
+
+	name, rest, ok := strings.Cut(line, ":")
+	if !ok || name == "" {
+		return s, fmt.Errorf("%w: %q: missing name", ErrInvalidStatsD, line)
+	}
+	s.Name = name
+
+	parts := strings.Split(rest, "|")
+	if len(parts) < 2 {
+		return s, fmt.Errorf("%w: %q: missing type", ErrInvalidStatsD, line)
+	}
+
+	s.Type = parts[1]
+	switch s.Type {
+	case StatsDCounter, StatsDTimer:
+	case StatsDGauge:
+		s.Relative = strings.HasPrefix(parts[0], "+") || strings.HasPrefix(parts[0], "-")
+	default:
+		return s, fmt.Errorf("%w: %q: unknown type %q", ErrInvalidStatsD, line, s.Type)
+	}
+
+	value, err := strconv.ParseFloat(parts[0], 64)
+	if err != nil || math.IsNaN(value) || math.IsInf(value, 0) {
+		return s, fmt.Errorf("%w: %q: bad value", ErrInvalidStatsD, line)
+	}
+	s.Value = value
+
+	for _, part := range parts[2:] {
+		switch {
+		case strings.HasPrefix(part, "@"):
+			rate, err := strconv.ParseFloat(part[1:], 64)
+			if err != nil || rate <= 0 || rate > 1 {
+				return s, fmt.Errorf("%w: %q: bad sample rate", ErrInvalidStatsD, line)
+			}
+			s.SampleRate = rate
+		case strings.HasPrefix(part, "#"):
+			for _, tag := range strings.Split(part[1:], ",") {
+				key, value, _ := strings.Cut(tag, ":")
+				if key != "" {
+					s.Tags = append(s.Tags, Tag{Key: key, Value: value})
+				}
+			}
+		default:
+			return s, fmt.Errorf("%w: %q: unknown section %q", ErrInvalidStatsD, line, part)
+		}
+	}
+
+	return s, nil
+}
+
+// StatsDAggregator sums up StatsD samples between flushes as StatsD daemon does.
+type StatsDAggregator struct {
+	mu    sync.Mutex
+	rules *Rules
+
+	counters map[string]float64
+	// gauges keep last values between flushes for relative updates, changed are sent on flush.
+	gauges  map[string]float64
+	changed map[string]struct{}
+	timers  map[string]*timer
+}
+
+type timer struct {
+	values []float64
+	count  float64
+}
+
+// NewStatsDAggregator function is constructor for aggregator, rules add tag values to metric names.
+func NewStatsDAggregator(rules *Rules) *StatsDAggregator {
+	return &StatsDAggregator{
+		rules:    rules,
+		counters: make(map[string]float64),
+		gauges:   make(map[string]float64),
+		changed:  make(map[string]struct{}),
+		timers:   make(map[string]*timer),
+	}
+}
+
+// Add accounts sample till the next flush.
+func (a *StatsDAggregator) Add(s StatsDSample) {
+	name := a.rules.taggedName(s.Name, s.Tags)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	switch s.Type {
+	case StatsDCounter:
+		a.counters[name] += s.Value / s.SampleRate
+	case StatsDGauge:
+		if s.Relative {
+			a.gauges[name] += s.Value
+		} else {
+			a.gauges[name] = s.Value
+		}
+		a.changed[name] = struct{}{}
+	case StatsDTimer:
+		t, ok := a.timers[name]
+		if !ok {
+			t = &timer{}
+			a.timers[name] = t
+		}
+		t.values = append(t.values, s.Value)
+		t.count += 1 / s.SampleRate
+	}
+}
+
+// Flush returns metrics aggregated since the previous flush sorted by name.
+// Counter becomes counter metric, gauge becomes gauge metric and timer becomes
+// name_count counter together with name_min, name_max, name_mean and name_pN gauges.
+func (a *StatsDAggregator) Flush() []domain.Metrics {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	metrics := make([]domain.Metrics, 0, len(a.counters)+len(a.changed)+len(a.timers)*(4+len(timerPercentiles)))
+
+	for name, sum := range a.counters {
+		metrics = append(metrics, counter(name, roundDelta(sum)))
+	}
+	for name := range a.changed {
+		metrics = append(metrics, gauge(name, a.gauges[name]))
+	}
+	for name, t := range a.timers {
+		metrics = append(metrics, t.metrics(name)...)
+	}
+
+	a.counters = make(map[string]float64)
+	a.changed = make(map[string]struct{})
+	a.timers = make(map[string]*timer)
+
+	sort.Slice(metrics, func(i, j int) bool {
+		return metrics[i].ID < metrics[j].ID
+	})
+	return metrics
+}
+
+func (t *timer) metrics(name string) []domain.Metrics {
+	sort.Float64s(t.values)
+
+	var sum float64
+	for _, v := range t.values {
+		sum += v
+	}
+
+	metrics := []domain.Metrics{
+		counter(name+"_count", roundDelta(t.count)),
+		gauge(name+"_min", t.values[0]),
+		gauge(name+"_max", t.values[len(t.values)-1]),
+		gauge(name+"_mean", sum/float64(len(t.values))),
+	}
+	for _, p := range timerPercentiles {
+		// NOTE: nearest-rank percentile
+		rank := int(math.Ceil(float64(p)/100*float64(len(t.values)))) - 1
+		metrics = append(metrics, gauge(name+"_p"+strconv.Itoa(p), t.values[max(rank, 0)]))
+	}
+	return metrics
+}
+
+// roundDelta rounds sampled sum to counter delta, saturating at int64 range.
+func roundDelta(sum float64) int64 {
+	switch rounded := math.Round(sum); {
+	case rounded >= math.MaxInt64:
+		return math.MaxInt64
+	case rounded <= math.MinInt64:
+		return math.MinInt64
+	default:
+		return int64(rounded)
+	}
+}
@@ -0,0 +1,121 @@
+package ingest
+
+import (
+	"testing"
+
+	"github.com/frolmr/metrics/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseStatsDLine(t *testing.T) {
+	tests := []struct {
+		name    string
+		line    string
+		want    StatsDSample
+		wantErr bool
+	}{
+		{
+			name: "counter",
+			line: "app.requests:3|c",
+			want: StatsDSample{Name: "app.requests", Value: 3, Type: StatsDCounter, SampleRate: 1},
+		},
+		{
+			name: "sampled counter",
+			line: "app.requests:1|c|@0.1",
+			want: StatsDSample{Name: "app.requests", Value: 1, Type: StatsDCounter, SampleRate: 0.1},
+		},
+		{
+			name: "gauge",
+			line: "app.load:0.75|g",
+			want: StatsDSample{Name: "app.load", Value: 0.75, Type: StatsDGauge, SampleRate: 1},
+		},
+		{
+			name: "relative gauge",
+			line: "app.load:-2|g",
+			want: StatsDSample{Name: "app.load", Value: -2, Type: StatsDGauge, Relative: true, SampleRate: 1},
+		},
+		{
+			name: "timer with tags",
+			line: "app.latency:12.5|ms|@0.5|#host:web1,canary",
+			want: StatsDSample{
+				Name: "app.latency", Value: 12.5, Type: StatsDTimer, SampleRate: 0.5,
+				Tags: []Tag{{Key: "host", Value: "web1"}, {Key: "canary"}},
+			},
+		},
+		{name: "missing name", line: ":1|c", wantErr: true},
+		{name: "missing value", line: "app.requests", wantErr: true},
+		{name: "missing type", line: "app.requests:1", wantErr: true},
+		{name: "unknown type", line: "app.users:1|s", wantErr: true},
+		{name: "bad value", line: "app.requests:x|c", wantErr: true},
+		{name: "not finite value", line: "app.load:NaN|g", wantErr: true},
+		{name: "bad sample rate", line: "app.requests:1|c|@2", wantErr: true},
+		{name: "unknown section", line: "app.requests:1|c|x", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseStatsDLine(tt.line)
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrInvalidStatsD)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseStatsD(t *testing.T) {
+	samples, errs := ParseStatsD("a:1|c\n\nbad\nb:2|g\n")
+	require.Len(t, samples, 2)
+	assert.Equal(t, "a", samples[0].Name)
+	assert.Equal(t, "b", samples[1].Name)
+	require.Len(t, errs, 1)
+	assert.ErrorIs(t, errs[0], ErrInvalidStatsD)
+}
+
+func TestStatsDAggregator(t *testing.T) {
+	a := NewStatsDAggregator(&Rules{Tags: []string{"host"}})
+
+	samples, errs := ParseStatsD(
+		"requests:1|c|@0.5\nrequests:3|c\n" +
+			"load:5|g\nload:+2|g\nload:-1|g\n" +
+			"latency:10|ms\nlatency:20|ms\nlatency:30|ms|@0.5\nlatency:40|ms\n" +
+			"cpu:0.5|g|#host:web1\n")
+	require.Empty(t, errs)
+	for _, s := range samples {
+		a.Add(s)
+	}
+
+	metricsByID := func(metrics []domain.Metrics) map[string]domain.Metrics {
+		byID := make(map[string]domain.Metrics, len(metrics))
+		for _, m := range metrics {
+			byID[m.ID] = m
+		}
+		return byID
+	}
+
+	got := metricsByID(a.Flush())
+	require.Len(t, got, 10)
+
+	assert.Equal(t, domain.CounterType, got["requests"].MType)
+	assert.Equal(t, int64(5), *got["requests"].Delta)
+	assert.InDelta(t, 6.0, *got["load"].Value, 1e-9)
+	assert.InDelta(t, 0.5, *got["cpu_web1"].Value, 1e-9)
+
+	assert.Equal(t, int64(5), *got["latency_count"].Delta)
+	assert.InDelta(t, 10.0, *got["latency_min"].Value, 1e-9)
+	assert.InDelta(t, 40.0, *got["latency_max"].Value, 1e-9)
+	assert.InDelta(t, 25.0, *got["latency_mean"].Value, 1e-9)
+	assert.InDelta(t, 20.0, *got["latency_p50"].Value, 1e-9)
+	assert.InDelta(t, 40.0, *got["latency_p90"].Value, 1e-9)
+	assert.InDelta(t, 40.0, *got["latency_p99"].Value, 1e-9)
+
+	assert.Empty(t, a.Flush(), "nothing changed since the last flush")
+
+	a.Add(StatsDSample{Name: "load", Value: 1, Type: StatsDGauge, Relative: true, SampleRate: 1})
+	got = metricsByID(a.Flush())
+	require.Len(t, got, 1)
+	assert.InDelta(t, 7.0, *got["load"].Value, 1e-9, "relative gauge changes value kept from previous flush")
+}
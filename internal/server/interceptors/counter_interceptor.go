@@ -0,0 +1,103 @@
+package interceptors
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+
+	"github.com/frolmr/metrics/internal/domain"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RequestKey identifies a group of counted requests.
+type RequestKey struct {
+	Method string
+	Code   codes.Code
+}
+
+// RequestCounter counts finished gRPC calls by method and status code.
+type RequestCounter struct {
+	mu     sync.Mutex
+	counts map[RequestKey]int64
+}
+
+func NewRequestCounter() *RequestCounter {
+	return &RequestCounter{
+		counts: make(map[RequestKey]int64),
+	}
+}
+
+// UnaryInterceptor returns interceptor counting unary calls.
+func (c *RequestCounter) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		resp, err := handler(ctx, req)
+		c.inc(info.FullMethod, status.Code(err))
+		return resp, err
+	}
+}
+
+// StreamInterceptor returns interceptor counting streams, a stream is counted once when it ends.
+func (c *RequestCounter) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		err := handler(srv, ss)
+		c.inc(info.FullMethod, status.Code(err))
+		return err
+	}
+}
+
+// Count returns number of calls of method finished with code.
+func (c *RequestCounter) Count(method string, code codes.Code) int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.counts[RequestKey{Method: method, Code: code}]
+}
+
+// Snapshot returns copy of all counters.
+func (c *RequestCounter) Snapshot() map[RequestKey]int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	snapshot := make(map[RequestKey]int64, len(c.counts))
+	for k, v := range c.counts {
+		snapshot[k] = v
+	}
+	return snapshot
+}
+
+// RequestCount is the number of calls of method finished with code, as served by RequestCounter.
+type RequestCount struct {
+	Method   string `json:"method"`
+	Code     string `json:"code"`
+	Requests int64  `json:"requests"`
+}
+
+// ServeHTTP writes counts as JSON array ordered by method and code.
+func (c *RequestCounter) ServeHTTP(res http.ResponseWriter, req *http.Request) {
+	snapshot := c.Snapshot()
+
+	counts := make([]RequestCount, 0, len(snapshot))
+	for key, requests := range snapshot {
+		counts = append(counts, RequestCount{Method: key.Method, Code: key.Code.String(), Requests: requests})
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Method != counts[j].Method {
+			return counts[i].Method < counts[j].Method
+		}
+		return counts[i].Code < counts[j].Code
+	})
+
+	res.Header().Set("Content-Type", domain.JSONContentType)
+	if err := json.NewEncoder(res).Encode(counts); err != nil {
+		http.Error(res, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (c *RequestCounter) inc(method string, code codes.Code) {
+	c.mu.Lock()
+	c.counts[RequestKey{Method: method, Code: code}]++
+	c.mu.Unlock()
+}
@@ -0,0 +1,60 @@
+package interceptors
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/frolmr/metrics/internal/domain"
+	pb "github.com/frolmr/metrics/pkg/proto/metrics"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRequestCounter(t *testing.T) {
+	counter := NewRequestCounter()
+	unary := counter.UnaryInterceptor()
+	stream := counter.StreamInterceptor()
+
+	unaryInfo := &grpc.UnaryServerInfo{FullMethod: pb.Metrics_UpdateMetricsBulk_FullMethodName}
+	okHandler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return &pb.Ack{Received: true}, nil
+	}
+	failHandler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, status.Error(codes.Unauthenticated, "no signature")
+	}
+
+	for range 2 {
+		_, err := unary(context.Background(), nil, unaryInfo, okHandler)
+		require.NoError(t, err)
+	}
+	_, err := unary(context.Background(), nil, unaryInfo, failHandler)
+	require.Error(t, err)
+
+	streamInfo := &grpc.StreamServerInfo{FullMethod: pb.Metrics_StreamMetrics_FullMethodName}
+	require.NoError(t, stream(nil, &mockServerStream{}, streamInfo, func(srv interface{}, ss grpc.ServerStream) error {
+		return nil
+	}))
+
+	require.Equal(t, int64(2), counter.Count(pb.Metrics_UpdateMetricsBulk_FullMethodName, codes.OK))
+	require.Equal(t, int64(1), counter.Count(pb.Metrics_UpdateMetricsBulk_FullMethodName, codes.Unauthenticated))
+	require.Equal(t, int64(1), counter.Count(pb.Metrics_StreamMetrics_FullMethodName, codes.OK))
+	require.Len(t, counter.Snapshot(), 3)
+
+	res := httptest.NewRecorder()
+	counter.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/debug/grpc/requests", nil))
+	require.Equal(t, http.StatusOK, res.Code)
+	require.Equal(t, domain.JSONContentType, res.Header().Get("Content-Type"))
+
+	var counts []RequestCount
+	require.NoError(t, json.Unmarshal(res.Body.Bytes(), &counts))
+	require.Equal(t, []RequestCount{
+		{Method: pb.Metrics_StreamMetrics_FullMethodName, Code: "OK", Requests: 1},
+		{Method: pb.Metrics_UpdateMetricsBulk_FullMethodName, Code: "OK", Requests: 2},
+		{Method: pb.Metrics_UpdateMetricsBulk_FullMethodName, Code: "Unauthenticated", Requests: 1},
+	}, counts)
+}
@@ -0,0 +1,100 @@
+package interceptors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/frolmr/metrics/internal/server/decryptor"
+	pb "github.com/frolmr/metrics/pkg/proto/metrics"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// NewDecryptInterceptor replaces encrypted metrics of bulk update with decrypted ones.
+// With private key configured plain metrics are rejected the same way as unencrypted HTTP bodies.
+func NewDecryptInterceptor(d *decryptor.Decryptor) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if d == nil || d.PrivateKey == nil {
+			return handler(ctx, req)
+		}
+
+		if info.FullMethod != pb.Metrics_UpdateMetricsBulk_FullMethodName {
+			return handler(ctx, req)
+		}
+
+		updateReq, ok := req.(*pb.UpdateMetricsBulkRequest)
+		if !ok {
+			return nil, status.Errorf(codes.Internal, "invalid request type")
+		}
+
+		metrics, err := decryptMetrics(d, updateReq.GetEncryptedMetrics())
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "failed to decrypt data: %v", err)
+		}
+		updateReq.Metrics = metrics
+		updateReq.EncryptedMetrics = nil
+
+		return handler(ctx, updateReq)
+	}
+}
+
+// NewStreamDecryptInterceptor decrypts metrics of every batch received over metrics stream.
+func NewStreamDecryptInterceptor(d *decryptor.Decryptor) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if d == nil || d.PrivateKey == nil {
+			return handler(srv, ss)
+		}
+
+		if info.FullMethod != pb.Metrics_StreamMetrics_FullMethodName {
+			return handler(srv, ss)
+		}
+
+		return handler(srv, &decryptedStream{ServerStream: ss, decryptor: d})
+	}
+}
+
+type decryptedStream struct {
+	grpc.ServerStream
+	decryptor *decryptor.Decryptor
+}
+
+func (s *decryptedStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+
+	batch, ok := m.(*pb.MetricsBatch)
+	if !ok {
+		return status.Errorf(codes.Internal, "invalid message type")
+	}
+
+	metrics, err := decryptMetrics(s.decryptor, batch.GetEncryptedMetrics())
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "failed to decrypt batch %d: %v", batch.GetId(), err)
+	}
+	batch.Metrics = metrics
+	batch.EncryptedMetrics = nil
+
+	return nil
+}
+
+func decryptMetrics(d *decryptor.Decryptor, encrypted []byte) ([]*pb.Metric, error) {
+	if len(encrypted) == 0 {
+		return nil, errors.New("metrics are not encrypted")
+	}
+
+	data, err := d.DecryptData(encrypted)
+	if err != nil {
+		return nil, err
+	}
+
+	var payload pb.UpdateMetricsBulkRequest
+	if err := proto.Unmarshal(data, &payload); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal decrypted metrics: %w", err)
+	}
+
+	return payload.GetMetrics(), nil
+}
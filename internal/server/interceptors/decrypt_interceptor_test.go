@@ -0,0 +1,97 @@
+package interceptors
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+
+	"github.com/frolmr/metrics/internal/server/decryptor"
+	pb "github.com/frolmr/metrics/pkg/proto/metrics"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func encryptMetrics(t *testing.T, key *rsa.PublicKey, metrics []*pb.Metric) []byte {
+	t.Helper()
+
+	data, err := proto.Marshal(&pb.UpdateMetricsBulkRequest{Metrics: metrics})
+	require.NoError(t, err)
+
+	encrypted, err := rsa.EncryptPKCS1v15(rand.Reader, key, data)
+	require.NoError(t, err)
+
+	return encrypted
+}
+
+func TestDecryptInterceptor(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	interceptor := NewDecryptInterceptor(decryptor.NewDecryptor(privateKey))
+	info := &grpc.UnaryServerInfo{FullMethod: pb.Metrics_UpdateMetricsBulk_FullMethodName}
+
+	metrics := []*pb.Metric{{Key: "test", Type: pb.Metric_MTYPE_GAUGE, MValue: &pb.Metric_Value{Value: 1.23}}}
+
+	var received *pb.UpdateMetricsBulkRequest
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		received = req.(*pb.UpdateMetricsBulkRequest)
+		return &pb.Ack{Received: true}, nil
+	}
+
+	t.Run("encrypted metrics", func(t *testing.T) {
+		req := &pb.UpdateMetricsBulkRequest{EncryptedMetrics: encryptMetrics(t, &privateKey.PublicKey, metrics)}
+
+		_, err := interceptor(context.Background(), req, info, handler)
+		require.NoError(t, err)
+		require.Len(t, received.GetMetrics(), 1)
+		require.True(t, proto.Equal(metrics[0], received.GetMetrics()[0]))
+		require.Empty(t, received.GetEncryptedMetrics())
+	})
+
+	t.Run("plain metrics rejected", func(t *testing.T) {
+		_, err := interceptor(context.Background(), &pb.UpdateMetricsBulkRequest{Metrics: metrics}, info, handler)
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("garbage rejected", func(t *testing.T) {
+		_, err := interceptor(context.Background(), &pb.UpdateMetricsBulkRequest{EncryptedMetrics: []byte("garbage")}, info, handler)
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("no key configured", func(t *testing.T) {
+		noKey := NewDecryptInterceptor(decryptor.NewDecryptor(nil))
+		_, err := noKey(context.Background(), &pb.UpdateMetricsBulkRequest{Metrics: metrics}, info, handler)
+		require.NoError(t, err)
+	})
+}
+
+func TestStreamDecryptInterceptor(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	interceptor := NewStreamDecryptInterceptor(decryptor.NewDecryptor(privateKey))
+	info := &grpc.StreamServerInfo{FullMethod: pb.Metrics_StreamMetrics_FullMethodName}
+
+	metrics := []*pb.Metric{{Key: "count", Type: pb.Metric_MTYPE_COUNTER, MValue: &pb.Metric_Delta{Delta: 42}}}
+
+	recvHandler := func(srv interface{}, ss grpc.ServerStream) error {
+		batch := &pb.MetricsBatch{}
+		if err := ss.RecvMsg(batch); err != nil {
+			return err
+		}
+		if len(batch.GetMetrics()) != 1 || batch.GetMetrics()[0].GetDelta() != 42 {
+			return status.Error(codes.Internal, "unexpected metrics")
+		}
+		return nil
+	}
+
+	stream := &mockServerStream{batch: &pb.MetricsBatch{Id: 1, EncryptedMetrics: encryptMetrics(t, &privateKey.PublicKey, metrics)}}
+	require.NoError(t, interceptor(nil, stream, info, recvHandler))
+
+	stream = &mockServerStream{batch: &pb.MetricsBatch{Id: 2, Metrics: metrics}}
+	require.Equal(t, codes.InvalidArgument, status.Code(interceptor(nil, stream, info, recvHandler)))
+}
@@ -0,0 +1,80 @@
+package interceptors
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/frolmr/metrics/internal/server/logger"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// NewLoggingInterceptor logs every unary call: method, peer, status code and duration.
+func NewLoggingInterceptor(l *logger.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+
+		resp, err := handler(ctx, req)
+
+		l.SugaredLogger.Infoln(
+			"method", info.FullMethod,
+			"peer", peerAddress(ctx),
+			"code", status.Code(err),
+			"duration", time.Since(start),
+			"messages", 1,
+		)
+
+		return resp, err
+	}
+}
+
+// NewStreamLoggingInterceptor logs every stream when it ends, messages is a sum of received and sent messages.
+func NewStreamLoggingInterceptor(l *logger.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+
+		cs := &countingStream{ServerStream: ss}
+		err := handler(srv, cs)
+
+		l.SugaredLogger.Infoln(
+			"method", info.FullMethod,
+			"peer", peerAddress(ss.Context()),
+			"code", status.Code(err),
+			"duration", time.Since(start),
+			"messages", cs.messages.Load(),
+		)
+
+		return err
+	}
+}
+
+type countingStream struct {
+	grpc.ServerStream
+	messages atomic.Int64
+}
+
+func (s *countingStream) RecvMsg(m interface{}) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err == nil {
+		s.messages.Add(1)
+	}
+	return err
+}
+
+func (s *countingStream) SendMsg(m interface{}) error {
+	err := s.ServerStream.SendMsg(m)
+	if err == nil {
+		s.messages.Add(1)
+	}
+	return err
+}
+
+func peerAddress(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return "unknown"
+	}
+	return p.Addr.String()
+}
@@ -0,0 +1,66 @@
+package interceptors
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/frolmr/metrics/internal/server/logger"
+	pb "github.com/frolmr/metrics/pkg/proto/metrics"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+func newObservedLogger() (*logger.Logger, *observer.ObservedLogs) {
+	core, recorded := observer.New(zapcore.DebugLevel)
+	return &logger.Logger{SugaredLogger: *zap.New(core).Sugar()}, recorded
+}
+
+func TestLoggingInterceptor(t *testing.T) {
+	l, recorded := newObservedLogger()
+	interceptor := NewLoggingInterceptor(l)
+
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 5000}})
+	info := &grpc.UnaryServerInfo{FullMethod: pb.Metrics_UpdateMetricsBulk_FullMethodName}
+
+	_, err := interceptor(ctx, nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, status.Error(codes.InvalidArgument, "bad request")
+	})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	logs := recorded.All()
+	require.Len(t, logs, 1)
+	require.Contains(t, logs[0].Message, pb.Metrics_UpdateMetricsBulk_FullMethodName)
+	require.Contains(t, logs[0].Message, "10.0.0.1:5000")
+	require.Contains(t, logs[0].Message, "InvalidArgument")
+}
+
+func TestStreamLoggingInterceptor(t *testing.T) {
+	l, recorded := newObservedLogger()
+	interceptor := NewStreamLoggingInterceptor(l)
+
+	stream := &mockServerStream{batch: &pb.MetricsBatch{Id: 1}}
+	info := &grpc.StreamServerInfo{FullMethod: pb.Metrics_StreamMetrics_FullMethodName}
+
+	err := interceptor(nil, stream, info, func(srv interface{}, ss grpc.ServerStream) error {
+		for range 3 {
+			if err := ss.RecvMsg(&pb.MetricsBatch{}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	require.NoError(t, err)
+
+	logs := recorded.All()
+	require.Len(t, logs, 1)
+	require.Contains(t, logs[0].Message, pb.Metrics_StreamMetrics_FullMethodName)
+	require.Contains(t, logs[0].Message, "peer unknown")
+	require.Contains(t, logs[0].Message, "messages 3")
+}
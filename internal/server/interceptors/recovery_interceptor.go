@@ -0,0 +1,46 @@
+package interceptors
+
+import (
+	"context"
+	"runtime/debug"
+
+	"github.com/frolmr/metrics/internal/server/logger"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// NewRecoveryInterceptor turns panic in unary handler into codes.Internal error instead of crashing the server.
+func NewRecoveryInterceptor(l *logger.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = recoveredError(l, info.FullMethod, r)
+			}
+		}()
+
+		return handler(ctx, req)
+	}
+}
+
+// NewStreamRecoveryInterceptor turns panic in stream handler into codes.Internal error instead of crashing the server.
+func NewStreamRecoveryInterceptor(l *logger.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = recoveredError(l, info.FullMethod, r)
+			}
+		}()
+
+		return handler(srv, ss)
+	}
+}
+
+func recoveredError(l *logger.Logger, method string, r interface{}) error {
+	l.SugaredLogger.Errorln(
+		"method", method,
+		"panic", r,
+		"stack", string(debug.Stack()),
+	)
+	return status.Errorf(codes.Internal, "internal server error")
+}
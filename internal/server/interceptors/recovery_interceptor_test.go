@@ -0,0 +1,47 @@
+package interceptors
+
+import (
+	"context"
+	"testing"
+
+	pb "github.com/frolmr/metrics/pkg/proto/metrics"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRecoveryInterceptor(t *testing.T) {
+	l, recorded := newObservedLogger()
+	interceptor := NewRecoveryInterceptor(l)
+	info := &grpc.UnaryServerInfo{FullMethod: pb.Metrics_UpdateMetricsBulk_FullMethodName}
+
+	t.Run("panic", func(t *testing.T) {
+		resp, err := interceptor(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+			var m *pb.Metric
+			return m.Key, nil
+		})
+		require.Nil(t, resp)
+		require.Equal(t, codes.Internal, status.Code(err))
+		require.Equal(t, 1, recorded.Len())
+	})
+
+	t.Run("no panic", func(t *testing.T) {
+		resp, err := interceptor(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return &pb.Ack{Received: true}, nil
+		})
+		require.NoError(t, err)
+		require.True(t, resp.(*pb.Ack).Received)
+	})
+}
+
+func TestStreamRecoveryInterceptor(t *testing.T) {
+	l, _ := newObservedLogger()
+	interceptor := NewStreamRecoveryInterceptor(l)
+	info := &grpc.StreamServerInfo{FullMethod: pb.Metrics_StreamMetrics_FullMethodName}
+
+	err := interceptor(nil, &mockServerStream{}, info, func(srv interface{}, ss grpc.ServerStream) error {
+		panic("boom")
+	})
+	require.Equal(t, codes.Internal, status.Code(err))
+}
@@ -40,6 +40,70 @@ func NewSignatureInterceptor(signKey string) grpc.UnaryServerInterceptor {
 	}
 }
 
+// NewStreamSignatureInterceptor validates signature of every batch received over metrics stream.
+// Batch with missing or wrong signature is rejected in its ack and skipped, the stream stays open.
+func NewStreamSignatureInterceptor(signKey string) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if signKey == "" {
+			return handler(srv, ss)
+		}
+
+		if info.FullMethod != pb.Metrics_StreamMetrics_FullMethodName {
+			return handler(srv, ss)
+		}
+
+		return handler(srv, &signedStream{ServerStream: ss, signKey: signKey})
+	}
+}
+
+type signedStream struct {
+	grpc.ServerStream
+	signKey string
+}
+
+// RecvMsg returns the next batch with valid signature, batches received before it are acked as rejected.
+func (s *signedStream) RecvMsg(m interface{}) error {
+	for {
+		if err := s.ServerStream.RecvMsg(m); err != nil {
+			return err
+		}
+
+		batch, ok := m.(*pb.MetricsBatch)
+		if !ok {
+			return status.Errorf(codes.Internal, "invalid message type")
+		}
+
+		err := s.verify(batch)
+		if err == nil {
+			return nil
+		}
+
+		// NOTE: ack is sent from the handler goroutine, so it can't interleave with acks the handler sends
+		if err := s.ServerStream.SendMsg(rejectedBatchAck(batch, "signature validation failed: "+err.Error())); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *signedStream) verify(batch *pb.MetricsBatch) error {
+	if batch.Signature == nil {
+		return errors.New("no signature in batch")
+	}
+
+	payload := &pb.UpdateMetricsBulkRequest{Metrics: batch.GetMetrics()}
+	return checkSignature(s.signKey, payload, batch.GetSignature())
+}
+
+// rejectedBatchAck acks batch as not received with every its metric rejected for reason.
+func rejectedBatchAck(batch *pb.MetricsBatch, reason string) *pb.BatchAck {
+	rejected := make([]*pb.MetricError, 0, len(batch.GetMetrics()))
+	for i, m := range batch.GetMetrics() {
+		rejected = append(rejected, &pb.MetricError{Index: uint32(i), Key: m.GetKey(), Error: reason}) //nolint:gosec // index is bounded by batch size
+	}
+
+	return &pb.BatchAck{Id: batch.GetId(), Received: false, Error: &reason, Rejected: rejected}
+}
+
 func validateSignature(ctx context.Context, signKey string, req *pb.UpdateMetricsBulkRequest) error {
 	md, ok := metadata.FromIncomingContext(ctx)
 	if !ok {
@@ -51,13 +115,17 @@ func validateSignature(ctx context.Context, signKey string, req *pb.UpdateMetric
 		return errors.New("no signature header found")
 	}
 
+	return checkSignature(signKey, req, signatureHeaders[0])
+}
+
+func checkSignature(signKey string, req *pb.UpdateMetricsBulkRequest, signature string) error {
 	jsonData, err := json.Marshal(req)
 	if err != nil {
 		return fmt.Errorf("failed to marshal request: %w", err)
 	}
 
 	expectedSignature := signer.SignPayloadWithKey(jsonData, []byte(signKey))
-	receivedSignature, err := hex.DecodeString(signatureHeaders[0])
+	receivedSignature, err := hex.DecodeString(signature)
 	if err != nil {
 		return fmt.Errorf("invalid signature format: %w", err)
 	}
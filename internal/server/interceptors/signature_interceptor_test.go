@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
+	"io"
 	"testing"
 
 	"github.com/frolmr/metrics/internal/domain"
@@ -14,6 +16,7 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 )
 
 func TestSignatureInterceptor(t *testing.T) {
@@ -144,3 +147,130 @@ func TestSignatureInterceptor(t *testing.T) {
 		require.True(t, resp.(*pb.Ack).Received)
 	})
 }
+
+type mockServerStream struct {
+	grpc.ServerStream
+	batch *pb.MetricsBatch
+}
+
+func (m *mockServerStream) Context() context.Context {
+	return context.Background()
+}
+
+func (m *mockServerStream) RecvMsg(msg interface{}) error {
+	out := msg.(*pb.MetricsBatch)
+	out.Id = m.batch.Id
+	out.Metrics = m.batch.Metrics
+	out.Signature = m.batch.Signature
+	out.EncryptedMetrics = m.batch.EncryptedMetrics
+	return nil
+}
+
+// batchStream receives batches one by one and records acks sent over it.
+type batchStream struct {
+	grpc.ServerStream
+	batches []*pb.MetricsBatch
+	acks    []*pb.BatchAck
+}
+
+func (b *batchStream) Context() context.Context {
+	return context.Background()
+}
+
+func (b *batchStream) RecvMsg(msg interface{}) error {
+	if len(b.batches) == 0 {
+		return io.EOF
+	}
+	// NOTE: like gRPC codec, message is reset before unmarshaling, signed stream reuses it for skipped batches
+	out := msg.(*pb.MetricsBatch)
+	proto.Reset(out)
+	proto.Merge(out, b.batches[0])
+	b.batches = b.batches[1:]
+	return nil
+}
+
+func (b *batchStream) SendMsg(msg interface{}) error {
+	b.acks = append(b.acks, msg.(*pb.BatchAck))
+	return nil
+}
+
+func TestStreamSignatureInterceptor(t *testing.T) {
+	signKey := "test-key"
+
+	metrics := []*pb.Metric{
+		{
+			Key:    "test",
+			Type:   pb.Metric_MTYPE_GAUGE,
+			MValue: &pb.Metric_Value{Value: 1.23},
+		},
+	}
+
+	jsonData, err := json.Marshal(&pb.UpdateMetricsBulkRequest{Metrics: metrics})
+	require.NoError(t, err)
+	validSignature := hex.EncodeToString(signer.SignPayloadWithKey(jsonData, []byte(signKey)))
+	invalidSignature := "invalid_signature"
+	wrongSignature := hex.EncodeToString(signer.SignPayloadWithKey(jsonData, []byte("other-key")))
+
+	// handler takes batches until the stream ends and returns ids of the batches it got
+	run := func(t *testing.T, key, method string, batches ...*pb.MetricsBatch) ([]uint64, []*pb.BatchAck) {
+		t.Helper()
+
+		stream := &batchStream{batches: batches}
+		var received []uint64
+		err := NewStreamSignatureInterceptor(key)(nil, stream, &grpc.StreamServerInfo{FullMethod: method},
+			func(srv interface{}, ss grpc.ServerStream) error {
+				for {
+					batch := &pb.MetricsBatch{}
+					if err := ss.RecvMsg(batch); err != nil {
+						if errors.Is(err, io.EOF) {
+							return nil
+						}
+						return err
+					}
+					received = append(received, batch.GetId())
+				}
+			})
+		require.NoError(t, err)
+		return received, stream.acks
+	}
+
+	t.Run("valid signature", func(t *testing.T) {
+		received, acks := run(t, signKey, pb.Metrics_StreamMetrics_FullMethodName,
+			&pb.MetricsBatch{Id: 1, Metrics: metrics, Signature: &validSignature})
+		require.Equal(t, []uint64{1}, received)
+		require.Empty(t, acks)
+	})
+
+	t.Run("bad batches are rejected and stream goes on", func(t *testing.T) {
+		received, acks := run(t, signKey, pb.Metrics_StreamMetrics_FullMethodName,
+			&pb.MetricsBatch{Id: 1, Metrics: metrics, Signature: &invalidSignature},
+			&pb.MetricsBatch{Id: 2, Metrics: metrics},
+			&pb.MetricsBatch{Id: 3, Metrics: metrics, Signature: &wrongSignature},
+			&pb.MetricsBatch{Id: 4, Metrics: metrics, Signature: &validSignature})
+		require.Equal(t, []uint64{4}, received)
+
+		require.Len(t, acks, 3)
+		for i, ack := range acks {
+			require.Equal(t, uint64(i+1), ack.GetId())
+			require.False(t, ack.GetReceived())
+			require.Contains(t, ack.GetError(), "signature validation failed")
+			require.Len(t, ack.GetRejected(), 1)
+			require.Equal(t, "test", ack.GetRejected()[0].GetKey())
+			require.Equal(t, ack.GetError(), ack.GetRejected()[0].GetError())
+		}
+		require.Contains(t, acks[1].GetError(), "no signature")
+		require.Contains(t, acks[2].GetError(), "signature mismatch")
+	})
+
+	t.Run("skip validation for other methods", func(t *testing.T) {
+		received, acks := run(t, signKey, "/metrics.Metrics/OtherMethod", &pb.MetricsBatch{Id: 1, Metrics: metrics})
+		require.Equal(t, []uint64{1}, received)
+		require.Empty(t, acks)
+	})
+
+	t.Run("no validation when no key", func(t *testing.T) {
+		received, acks := run(t, "", pb.Metrics_StreamMetrics_FullMethodName, &pb.MetricsBatch{Id: 1, Metrics: metrics})
+		require.Equal(t, []uint64{1}, received)
+		require.Empty(t, acks)
+	})
+}
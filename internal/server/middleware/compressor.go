@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/frolmr/metrics/internal/domain"
+	"github.com/frolmr/metrics/internal/server/apierror"
 	"github.com/frolmr/metrics/internal/server/gzipper"
 )
 
@@ -25,7 +26,7 @@ func Compressor(next http.Handler) http.Handler {
 		if sendsGzip {
 			cr, err := gzipper.NewCompressReader(req.Body)
 			if err != nil {
-				res.WriteHeader(http.StatusInternalServerError)
+				apierror.Write(res, req, http.StatusBadRequest, apierror.CodeBadRequest, "invalid gzip body", nil)
 				return
 			}
 			req.Body = cr
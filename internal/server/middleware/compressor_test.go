@@ -95,5 +95,5 @@ func TestCompressor_DecompressionError(t *testing.T) {
 
 	middleware.ServeHTTP(rec, req)
 
-	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
 }
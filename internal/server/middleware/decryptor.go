@@ -5,6 +5,7 @@ import (
 	"io"
 	"net/http"
 
+	"github.com/frolmr/metrics/internal/server/apierror"
 	"github.com/frolmr/metrics/internal/server/decryptor"
 )
 
@@ -18,14 +19,14 @@ func WithDecrypt(d *decryptor.Decryptor) func(next http.Handler) http.Handler {
 
 			encryptedData, err := io.ReadAll(req.Body)
 			if err != nil {
-				http.Error(res, "failed to read request body", http.StatusBadRequest)
+				apierror.Write(res, req, http.StatusBadRequest, apierror.CodeBadRequest, "failed to read request body", nil)
 				return
 			}
 			defer req.Body.Close()
 
 			decryptedData, err := d.DecryptData(encryptedData)
 			if err != nil {
-				http.Error(res, "failed to decrypt data", http.StatusBadRequest)
+				apierror.Write(res, req, http.StatusBadRequest, apierror.CodeDecryptionFailed, "failed to decrypt data", nil)
 				return
 			}
 
@@ -38,6 +38,11 @@ func (r *loggingResponseWriter) WriteHeader(statusCode int) {
 	r.wroteHeader = true
 }
 
+// Unwrap lets http.ResponseController reach flusher of the underlying writer.
+func (r *loggingResponseWriter) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
 func WithLog(l *logger.Logger) func(next http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		fn := func(res http.ResponseWriter, req *http.Request) {
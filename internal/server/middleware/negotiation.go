@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/frolmr/metrics/internal/server/apierror"
+)
+
+// WithContentType rejects requests which body is not of the media type.
+func WithContentType(mediaType string) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			if !apierror.HasContentType(req, mediaType) {
+				apierror.Write(res, req, http.StatusUnsupportedMediaType, apierror.CodeUnsupportedMediaType,
+					"request body must be "+mediaType, nil)
+				return
+			}
+
+			next.ServeHTTP(res, req)
+		})
+	}
+}
+
+// WithAccept rejects requests which Accept header allows none of the media types.
+func WithAccept(mediaTypes ...string) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			if apierror.Negotiate(req, mediaTypes...) == "" {
+				apierror.Write(res, req, http.StatusNotAcceptable, apierror.CodeNotAcceptable,
+					"response can be "+strings.Join(mediaTypes, " or "), nil)
+				return
+			}
+
+			next.ServeHTTP(res, req)
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/frolmr/metrics/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithContentType(t *testing.T) {
+	handler := WithContentType(domain.JSONContentType)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		name        string
+		contentType string
+		want        int
+	}{
+		{name: "json", contentType: domain.JSONContentType, want: http.StatusOK},
+		{name: "json with charset", contentType: "application/json; charset=utf-8", want: http.StatusOK},
+		{name: "text", contentType: domain.TextContentType, want: http.StatusUnsupportedMediaType},
+		{name: "missing", contentType: "", want: http.StatusUnsupportedMediaType},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/update", nil)
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.want, rec.Code)
+		})
+	}
+}
+
+func TestWithAccept(t *testing.T) {
+	handler := WithAccept(domain.JSONContentType)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		name   string
+		accept string
+		want   int
+	}{
+		{name: "missing", accept: "", want: http.StatusOK},
+		{name: "json", accept: domain.JSONContentType, want: http.StatusOK},
+		{name: "any", accept: "*/*", want: http.StatusOK},
+		{name: "text", accept: domain.TextContentType, want: http.StatusNotAcceptable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/ping", nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.want, rec.Code)
+			if tt.want == http.StatusNotAcceptable {
+				assert.Contains(t, rec.Body.String(), `"code":"not_acceptable"`)
+			}
+		})
+	}
+}
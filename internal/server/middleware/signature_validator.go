@@ -7,6 +7,7 @@ import (
 	"net/http"
 
 	"github.com/frolmr/metrics/internal/domain"
+	"github.com/frolmr/metrics/internal/server/apierror"
 	"github.com/frolmr/metrics/pkg/signer"
 )
 
@@ -49,6 +50,11 @@ func (r *signingResponseWriter) WriteHeader(statusCode int) {
 	}
 }
 
+// Unwrap lets http.ResponseController reach flusher of the underlying writer.
+func (r *signingResponseWriter) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
 func WithSignature(key string) func(next http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		fn := func(res http.ResponseWriter, req *http.Request) {
@@ -74,7 +80,7 @@ func WithSignature(key string) func(next http.Handler) http.Handler {
 
 					next.ServeHTTP(&lw, req)
 				} else {
-					http.Error(res, "invalid signature", http.StatusBadGateway)
+					apierror.Write(res, req, http.StatusBadRequest, apierror.CodeInvalidSignature, "invalid signature", nil)
 					return
 				}
 			} else {
@@ -74,8 +74,8 @@ func TestWithSignature_InvalidSignature(t *testing.T) {
 
 	handler.ServeHTTP(rr, req)
 
-	if status := rr.Code; status != http.StatusBadGateway {
-		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusBadGateway)
+	if status := rr.Code; status != http.StatusBadRequest {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusBadRequest)
 	}
 
 	expectedBody := "invalid signature\n"
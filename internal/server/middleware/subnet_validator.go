@@ -3,6 +3,9 @@ package middleware
 import (
 	"net"
 	"net/http"
+
+	"github.com/frolmr/metrics/internal/domain"
+	"github.com/frolmr/metrics/internal/server/apierror"
 )
 
 func WithTrustedSubnet(trustedSubnet *net.IPNet) func(next http.Handler) http.Handler {
@@ -13,14 +16,9 @@ func WithTrustedSubnet(trustedSubnet *net.IPNet) func(next http.Handler) http.Ha
 				return
 			}
 
-			reqIP := net.ParseIP(req.Header.Get("X-Real-IP"))
-			if reqIP == nil {
-				res.WriteHeader(http.StatusForbidden)
-				return
-			}
-
-			if !trustedSubnet.Contains(reqIP) {
-				res.WriteHeader(http.StatusForbidden)
+			reqIP := net.ParseIP(req.Header.Get(domain.RealIPHeader))
+			if reqIP == nil || !trustedSubnet.Contains(reqIP) {
+				apierror.Write(res, req, http.StatusForbidden, apierror.CodeForbidden, "address is not in trusted subnet", nil)
 				return
 			}
 
@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/frolmr/metrics/internal/server/apierror"
+)
+
+// WithUnsignedIngest guards routes which can't be decrypted or signed, they are forbidden unless allowed.
+func WithUnsignedIngest(allowed bool) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			if !allowed {
+				apierror.Write(res, req, http.StatusForbidden, apierror.CodeForbidden,
+					"unsigned writes are disabled while signing or encryption key is set", nil)
+				return
+			}
+
+			next.ServeHTTP(res, req)
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithUnsignedIngest(t *testing.T) {
+	tests := []struct {
+		name           string
+		allowed        bool
+		expectedStatus int
+	}{
+		{name: "allowed", allowed: true, expectedStatus: http.StatusNoContent},
+		{name: "forbidden", allowed: false, expectedStatus: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNoContent)
+			})
+
+			rr := httptest.NewRecorder()
+			WithUnsignedIngest(tt.allowed)(handler).ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/api/v1/write", nil))
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+		})
+	}
+}
@@ -0,0 +1,225 @@
+// Package migrate copies metrics between storage backends and checks that the destination has the source metrics.
+package migrate
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"strconv"
+
+	"github.com/frolmr/metrics/internal/domain"
+	"github.com/frolmr/metrics/internal/server/storage"
+)
+
+// chunkSize is the most metrics read or written at once.
+const chunkSize = domain.MaxListLimit
+
+var (
+	ErrMismatch = errors.New("destination doesn't match source")
+	ErrOverflow = errors.New("counter can't be set without overflow")
+)
+
+// Summary describes contents of storage, equal summaries mean equal contents.
+type Summary struct {
+	Gauges   int
+	Counters int
+	// Checksum doesn't depend on order of metrics, so backends sorting names differently give the same sum.
+	Checksum uint64
+}
+
+func (s Summary) String() string {
+	return fmt.Sprintf("%d gauges, %d counters, checksum %016x", s.Gauges, s.Counters, s.Checksum)
+}
+
+// Report describes result of Copy.
+type Report struct {
+	// Source summarizes metrics read from source.
+	Source Summary
+	// Written is the number of metrics written to destination, or to be written on dry run.
+	Written int
+	// Unchanged is the number of metrics destination already had with the same values.
+	Unchanged int
+}
+
+// Copy makes every metric of src have the same value in dst, counters are set rather than added to.
+// Metrics dst has but src doesn't are left as is. Nothing is written to dst if dryRun is set.
+func Copy(src, dst storage.Repository, dryRun bool) (Report, error) {
+	var (
+		report Report
+		batch  = make([]domain.Metrics, 0, chunkSize)
+	)
+
+	err := walk(src, func(m domain.Metrics) error {
+		report.Source.add(m)
+
+		update, changed, err := diff(dst, m)
+		if err != nil {
+			return err
+		}
+		if !changed {
+			report.Unchanged++
+			return nil
+		}
+
+		report.Written++
+		if dryRun {
+			return nil
+		}
+
+		batch = append(batch, update)
+		if len(batch) < chunkSize {
+			return nil
+		}
+		err = dst.UpdateMetrics(batch)
+		batch = batch[:0]
+		return err
+	})
+	if err != nil {
+		return report, err
+	}
+
+	if len(batch) != 0 {
+		if err := dst.UpdateMetrics(batch); err != nil {
+			return report, err
+		}
+	}
+	return report, nil
+}
+
+// Summarize reads from repo every metric keys has and returns summary of repo values, metrics repo is missing
+// are not counted. Summarize(repo, repo) summarizes all metrics of repo, Summarize(dst, src) after Copy
+// has to be equal to Report.Source.
+func Summarize(repo, keys storage.Repository) (Summary, error) {
+	var summary Summary
+	err := walk(keys, func(m domain.Metrics) error {
+		switch m.MType {
+		case domain.GaugeType:
+			value, err := repo.GetGaugeMetric(m.ID)
+			if errors.Is(err, storage.ErrMetricNotFound) {
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("reading gauge %s: %w", m.ID, err)
+			}
+			m.Value = &value
+		case domain.CounterType:
+			delta, err := repo.GetCounterMetric(m.ID)
+			if errors.Is(err, storage.ErrMetricNotFound) {
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("reading counter %s: %w", m.ID, err)
+			}
+			m.Delta = &delta
+		default:
+			return fmt.Errorf("%w: %s", domain.ErrUnknownType, m.MType)
+		}
+
+		summary.add(m)
+		return nil
+	})
+	return summary, err
+}
+
+// Verify checks that every metric of src has the same value in dst, metrics only dst has are ignored
+// as Copy leaves them as is.
+func Verify(src, dst storage.Repository) error {
+	var (
+		checked    int
+		mismatched []string
+	)
+
+	err := walk(src, func(m domain.Metrics) error {
+		checked++
+		_, changed, err := diff(dst, m)
+		if err != nil && !errors.Is(err, ErrOverflow) {
+			return err
+		}
+		if changed || err != nil {
+			mismatched = append(mismatched, m.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("reading metrics: %w", err)
+	}
+
+	if len(mismatched) != 0 {
+		return fmt.Errorf("%w: %d of %d metrics differ, first is %s", ErrMismatch, len(mismatched), checked, mismatched[0])
+	}
+	return nil
+}
+
+func (s *Summary) add(m domain.Metrics) {
+	h := fnv.New64a()
+	h.Write([]byte(m.MType + "\x00" + m.ID + "\x00"))
+
+	switch m.MType {
+	case domain.CounterType:
+		s.Counters++
+		h.Write([]byte(strconv.FormatInt(*m.Delta, 10)))
+	case domain.GaugeType:
+		s.Gauges++
+		h.Write([]byte(strconv.FormatUint(math.Float64bits(*m.Value), 16)))
+	}
+
+	s.Checksum += h.Sum64()
+}
+
+// walk passes every metric of repo to fn page by page.
+func walk(repo storage.Repository, fn func(m domain.Metrics) error) error {
+	query := domain.MetricsQuery{Sort: domain.SortByName, Limit: chunkSize}
+	for {
+		metrics, err := repo.ListMetrics(query)
+		if err != nil {
+			return err
+		}
+
+		for _, m := range metrics {
+			if err := fn(m); err != nil {
+				return err
+			}
+		}
+		if len(metrics) < query.Limit {
+			return nil
+		}
+
+		last := metrics[len(metrics)-1]
+		query.After = &domain.MetricKey{ID: last.ID, MType: last.MType}
+	}
+}
+
+// diff returns update which makes dst value of metric equal to m, changed is false if values are equal already.
+func diff(dst storage.Repository, m domain.Metrics) (domain.Metrics, bool, error) {
+	switch m.MType {
+	case domain.GaugeType:
+		current, err := dst.GetGaugeMetric(m.ID)
+		if err != nil && !errors.Is(err, storage.ErrMetricNotFound) {
+			return m, false, fmt.Errorf("reading gauge %s: %w", m.ID, err)
+		}
+		if err == nil && math.Float64bits(current) == math.Float64bits(*m.Value) {
+			return m, false, nil
+		}
+		return m, true, nil
+	case domain.CounterType:
+		// NOTE: missing counter is zero for the purpose of delta but still has to be written,
+		// any other error may hide existing value, so delta can't be computed
+		current, err := dst.GetCounterMetric(m.ID)
+		if err != nil && !errors.Is(err, storage.ErrMetricNotFound) {
+			return m, false, fmt.Errorf("reading counter %s: %w", m.ID, err)
+		}
+		target := *m.Delta
+		if err == nil && current == target {
+			return m, false, nil
+		}
+		if (current > 0 && target < math.MinInt64+current) || (current < 0 && target > math.MaxInt64+current) {
+			return m, false, fmt.Errorf("%w: %s", ErrOverflow, m.ID)
+		}
+
+		delta := target - current
+		return domain.Metrics{ID: m.ID, MType: m.MType, Delta: &delta}, true, nil
+	default:
+		return m, false, fmt.Errorf("%w: %s", domain.ErrUnknownType, m.MType)
+	}
+}
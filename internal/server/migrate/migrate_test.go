@@ -0,0 +1,155 @@
+package migrate
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/frolmr/metrics/internal/server/mocks"
+	"github.com/frolmr/metrics/internal/server/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestCopy(t *testing.T) {
+	src := storage.NewMemStorage()
+	for i := 0; i < chunkSize+5; i++ {
+		src.GaugeMetrics[fmt.Sprintf("gauge%04d", i)] = float64(i) / 3
+	}
+	src.CounterMetrics["hits"] = 10
+	src.CounterMetrics["zero"] = 0
+	src.CounterMetrics["same"] = 7
+
+	dst := storage.NewMemStorage()
+	dst.CounterMetrics["hits"] = 4
+	dst.CounterMetrics["same"] = 7
+	dst.GaugeMetrics["gauge0001"] = src.GaugeMetrics["gauge0001"]
+
+	report, err := Copy(src, dst, true)
+	require.NoError(t, err)
+	assert.Equal(t, chunkSize+5, report.Source.Gauges)
+	assert.Equal(t, 3, report.Source.Counters)
+	assert.Equal(t, chunkSize+6, report.Written)
+	assert.Equal(t, 2, report.Unchanged)
+	assert.Len(t, dst.GaugeMetrics, 1, "dry run writes nothing")
+	assert.ErrorIs(t, Verify(src, dst), ErrMismatch)
+
+	_, err = Copy(src, dst, false)
+	require.NoError(t, err)
+	assert.Equal(t, int64(10), dst.CounterMetrics["hits"], "counters are set, not added to")
+	assert.Contains(t, dst.CounterMetrics, "zero")
+	require.NoError(t, Verify(src, dst))
+
+	report, err = Copy(src, dst, false)
+	require.NoError(t, err)
+	assert.Equal(t, 0, report.Written, "second copy has nothing to write")
+}
+
+func TestVerify(t *testing.T) {
+	src := storage.NewMemStorage()
+	src.GaugeMetrics["load"] = 0.5
+	src.CounterMetrics["hits"] = 10
+
+	dst := storage.NewMemStorage()
+	dst.GaugeMetrics["load"] = 0.5
+	dst.CounterMetrics["hits"] = 10
+	dst.GaugeMetrics["written_by_server"] = 1
+	dst.CounterMetrics["requests"] = 3
+
+	require.NoError(t, Verify(src, dst), "metrics only destination has are ignored")
+
+	dst.CounterMetrics["hits"] = 11
+	err := Verify(src, dst)
+	require.ErrorIs(t, err, ErrMismatch)
+	assert.Contains(t, err.Error(), "hits")
+
+	delete(dst.GaugeMetrics, "load")
+	dst.CounterMetrics["hits"] = 10
+	assert.ErrorIs(t, Verify(src, dst), ErrMismatch, "missing metric")
+}
+
+func TestCopy_Overflow(t *testing.T) {
+	src := storage.NewMemStorage()
+	src.CounterMetrics["hits"] = math.MaxInt64
+
+	dst := storage.NewMemStorage()
+	dst.CounterMetrics["hits"] = -1
+
+	_, err := Copy(src, dst, false)
+	assert.ErrorIs(t, err, ErrOverflow)
+}
+
+func TestCopy_ReadError(t *testing.T) {
+	src := storage.NewMemStorage()
+	src.CounterMetrics["hits"] = 10
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// NOTE: no UpdateMetrics is expected, counter must not be written when its current value is unknown
+	dst := mocks.NewMockRepository(ctrl)
+	dst.EXPECT().GetCounterMetric("hits").Return(int64(0), errors.New("db is down")).Times(2)
+
+	_, err := Copy(src, dst, false)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "db is down")
+
+	err = Verify(src, dst)
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, ErrMismatch, "read failure is not a mismatch")
+}
+
+func TestSummarize(t *testing.T) {
+	a := storage.NewMemStorage()
+	a.GaugeMetrics["load"] = 0.5
+	a.CounterMetrics["hits"] = 1
+
+	b := storage.NewMemStorage()
+	b.CounterMetrics["hits"] = 1
+	b.GaugeMetrics["load"] = 0.5
+
+	sa, err := Summarize(a, a)
+	require.NoError(t, err)
+	sb, err := Summarize(b, b)
+	require.NoError(t, err)
+	assert.Equal(t, sa, sb)
+
+	b.GaugeMetrics["load"] = 0.25
+	sb, err = Summarize(b, b)
+	require.NoError(t, err)
+	assert.NotEqual(t, sa.Checksum, sb.Checksum)
+	assert.Equal(t, sa.Gauges, sb.Gauges)
+}
+
+func TestSummarize_Keys(t *testing.T) {
+	src := storage.NewMemStorage()
+	src.GaugeMetrics["load"] = 0.5
+	src.CounterMetrics["hits"] = 10
+
+	dst := storage.NewMemStorage()
+	dst.GaugeMetrics["extra"] = 1
+
+	report, err := Copy(src, dst, false)
+	require.NoError(t, err)
+
+	summary, err := Summarize(dst, src)
+	require.NoError(t, err)
+	assert.Equal(t, report.Source, summary, "metrics only destination has are not counted")
+
+	delete(dst.CounterMetrics, "hits")
+	summary, err = Summarize(dst, src)
+	require.NoError(t, err)
+	assert.Equal(t, 0, summary.Counters)
+	assert.NotEqual(t, report.Source, summary)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	broken := mocks.NewMockRepository(ctrl)
+	broken.EXPECT().GetCounterMetric("hits").Return(int64(0), errors.New("db is down"))
+
+	_, err = Summarize(broken, src)
+	assert.ErrorContains(t, err, "db is down")
+}
@@ -3,10 +3,10 @@
 //
 // Generated by this command:
 //
-//	mockgen -source=repository.go -destination=mock_repository.go -package=storage
+//	mockgen -source=repository.go -destination=../mocks/mock_repository.go -package=mocks
 //
 
-// Package storage is a generated GoMock package.
+// Package mocks is a generated GoMock package.
 package mocks
 
 import (
@@ -100,6 +100,21 @@ func (mr *MockRepositoryMockRecorder) GetGaugeMetrics() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGaugeMetrics", reflect.TypeOf((*MockRepository)(nil).GetGaugeMetrics))
 }
 
+// ListMetrics mocks base method.
+func (m *MockRepository) ListMetrics(query domain.MetricsQuery) ([]domain.Metrics, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListMetrics", query)
+	ret0, _ := ret[0].([]domain.Metrics)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListMetrics indicates an expected call of ListMetrics.
+func (mr *MockRepositoryMockRecorder) ListMetrics(query any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMetrics", reflect.TypeOf((*MockRepository)(nil).ListMetrics), query)
+}
+
 // Ping mocks base method.
 func (m *MockRepository) Ping() error {
 	m.ctrl.T.Helper()
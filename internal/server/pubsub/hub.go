@@ -0,0 +1,126 @@
+// Package pubsub delivers accepted metric updates to live subscribers.
+package pubsub
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/frolmr/metrics/internal/domain"
+)
+
+const (
+	DefaultBufferSize = 256
+)
+
+// Filter decides whether update should be delivered to subscriber.
+type Filter func(m domain.Metrics) bool
+
+// Hub fans out published updates to subscribers. Publishing never blocks:
+// updates that don't fit into subscriber buffer are dropped for that subscriber.
+type Hub struct {
+	mu         sync.RWMutex
+	subs       map[*Subscription]struct{}
+	bufferSize int
+	closed     bool
+}
+
+// NewHub function is constructor for hub with given per-subscriber buffer size.
+func NewHub(bufferSize int) *Hub {
+	if bufferSize <= 0 {
+		bufferSize = DefaultBufferSize
+	}
+	return &Hub{
+		subs:       make(map[*Subscription]struct{}),
+		bufferSize: bufferSize,
+	}
+}
+
+// Subscription is a single subscriber of hub updates.
+type Subscription struct {
+	hub     *Hub
+	filter  Filter
+	ch      chan domain.Metrics
+	once    sync.Once
+	dropped atomic.Int64
+}
+
+// Subscribe registers new subscriber, nil filter accepts all updates.
+func (h *Hub) Subscribe(filter Filter) *Subscription {
+	sub := &Subscription{
+		hub:    h,
+		filter: filter,
+		ch:     make(chan domain.Metrics, h.bufferSize),
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		sub.once.Do(func() { close(sub.ch) })
+		return sub
+	}
+	h.subs[sub] = struct{}{}
+
+	return sub
+}
+
+// Close closes all subscriptions, subscribing to closed hub returns already closed subscription.
+func (h *Hub) Close() {
+	h.mu.Lock()
+	h.closed = true
+	subs := make([]*Subscription, 0, len(h.subs))
+	for sub := range h.subs {
+		subs = append(subs, sub)
+	}
+	h.mu.Unlock()
+
+	for _, sub := range subs {
+		sub.Close()
+	}
+}
+
+// Publish sends updates to all matching subscribers.
+func (h *Hub) Publish(metrics []domain.Metrics) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for sub := range h.subs {
+		for _, m := range metrics {
+			if sub.filter != nil && !sub.filter(m) {
+				continue
+			}
+			select {
+			case sub.ch <- m:
+			default:
+				sub.dropped.Add(1)
+			}
+		}
+	}
+}
+
+// Subscribers returns number of active subscribers.
+func (h *Hub) Subscribers() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.subs)
+}
+
+// Updates returns channel with updates, it's closed after Close.
+func (s *Subscription) Updates() <-chan domain.Metrics {
+	return s.ch
+}
+
+// Dropped returns number of updates dropped because subscriber was too slow.
+func (s *Subscription) Dropped() int64 {
+	return s.dropped.Load()
+}
+
+// Close unregisters subscriber and closes its updates channel.
+func (s *Subscription) Close() {
+	s.once.Do(func() {
+		s.hub.mu.Lock()
+		delete(s.hub.subs, s)
+		s.hub.mu.Unlock()
+		close(s.ch)
+	})
+}
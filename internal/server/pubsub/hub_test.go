@@ -0,0 +1,82 @@
+package pubsub
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/frolmr/metrics/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func gauge(name string, value float64) domain.Metrics {
+	return domain.Metrics{ID: name, MType: domain.GaugeType, Value: &value}
+}
+
+func TestHub(t *testing.T) {
+	t.Run("delivers to all subscribers", func(t *testing.T) {
+		h := NewHub(10)
+		s1 := h.Subscribe(nil)
+		s2 := h.Subscribe(nil)
+		defer s1.Close()
+		defer s2.Close()
+
+		h.Publish([]domain.Metrics{gauge("a", 1)})
+
+		assert.Equal(t, "a", (<-s1.Updates()).ID)
+		assert.Equal(t, "a", (<-s2.Updates()).ID)
+	})
+
+	t.Run("applies filter", func(t *testing.T) {
+		h := NewHub(10)
+		sub := h.Subscribe(func(m domain.Metrics) bool { return strings.HasPrefix(m.ID, "cpu") })
+		defer sub.Close()
+
+		h.Publish([]domain.Metrics{gauge("mem", 1), gauge("cpu0", 2)})
+
+		assert.Equal(t, "cpu0", (<-sub.Updates()).ID)
+		assert.Empty(t, sub.Updates())
+	})
+
+	t.Run("drops updates for slow subscriber", func(t *testing.T) {
+		h := NewHub(1)
+		sub := h.Subscribe(nil)
+		defer sub.Close()
+
+		h.Publish([]domain.Metrics{gauge("a", 1), gauge("b", 2), gauge("c", 3)})
+
+		assert.Equal(t, int64(2), sub.Dropped())
+		assert.Equal(t, "a", (<-sub.Updates()).ID)
+	})
+
+	t.Run("close unsubscribes", func(t *testing.T) {
+		h := NewHub(0)
+		sub := h.Subscribe(nil)
+		require.Equal(t, 1, h.Subscribers())
+
+		sub.Close()
+		sub.Close()
+
+		assert.Equal(t, 0, h.Subscribers())
+		_, ok := <-sub.Updates()
+		assert.False(t, ok)
+
+		h.Publish([]domain.Metrics{gauge("a", 1)})
+	})
+}
+
+func TestHubClose(t *testing.T) {
+	h := NewHub(10)
+	sub := h.Subscribe(nil)
+
+	h.Close()
+
+	_, ok := <-sub.Updates()
+	assert.False(t, ok)
+	assert.Equal(t, 0, h.Subscribers())
+
+	late := h.Subscribe(nil)
+	_, ok = <-late.Updates()
+	assert.False(t, ok)
+	late.Close()
+}
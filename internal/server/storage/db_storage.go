@@ -3,6 +3,10 @@ package storage
 
 import (
 	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/frolmr/metrics/internal/domain"
 )
@@ -79,6 +83,12 @@ func (ds DBStorage) splitInGroups(metrics []domain.Metrics) [][]domain.Metrics {
 
 // UpdateMetrics function is for bulk update of metrics
 func (ds DBStorage) UpdateMetrics(metrics []domain.Metrics) error {
+	for i, v := range metrics {
+		if err := v.Validate(); err != nil {
+			return fmt.Errorf("metric %d (%s): %w", i, v.ID, err)
+		}
+	}
+
 	metricsGroups := ds.splitInGroups(metrics)
 
 	counterStmt, err := ds.insertGaugeMetricStatement()
@@ -145,6 +155,9 @@ func (ds DBStorage) GetCounterMetric(name string) (int64, error) {
 
 	var val int64
 	err = stmt.QueryRow(name).Scan(&val)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, ErrMetricNotFound
+	}
 	if err != nil {
 		return 0, err
 	}
@@ -162,6 +175,9 @@ func (ds DBStorage) GetGaugeMetric(name string) (float64, error) {
 
 	var val float64
 	err = stmt.QueryRow(name).Scan(&val)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, ErrMetricNotFound
+	}
 	if err != nil {
 		return 0, err
 	}
@@ -191,6 +207,96 @@ func (ds DBStorage) GetGaugeMetrics() (map[string]float64, error) {
 	return getMetrics(stmt, vals), nil
 }
 
+// ListMetrics function is for page of metrics fetch from DB, names are compared bytewise as in memory storage.
+func (ds DBStorage) ListMetrics(query domain.MetricsQuery) ([]domain.Metrics, error) {
+	queryString, args := listMetricsQuery(query)
+
+	rows, err := ds.db.Query(queryString, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	metrics := make([]domain.Metrics, 0, query.Limit)
+	for rows.Next() {
+		var (
+			m     domain.Metrics
+			delta sql.NullInt64
+			value sql.NullFloat64
+		)
+		if err := rows.Scan(&m.MType, &m.ID, &delta, &value); err != nil {
+			return nil, err
+		}
+		if delta.Valid {
+			m.Delta = &delta.Int64
+		}
+		if value.Valid {
+			m.Value = &value.Float64
+		}
+		metrics = append(metrics, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return metrics, nil
+}
+
+func listMetricsQuery(query domain.MetricsQuery) (string, []any) {
+	var args []any
+	arg := func(v any) string {
+		args = append(args, v)
+		return "$" + strconv.Itoa(len(args))
+	}
+
+	tables := make([]string, 0, 2)
+	if query.MType != domain.GaugeType {
+		tables = append(tables, "SELECT 'counter' AS type, name, value AS delta, NULL::DOUBLE PRECISION AS value FROM counter_metrics")
+	}
+	if query.MType != domain.CounterType {
+		tables = append(tables, "SELECT 'gauge' AS type, name, NULL::BIGINT AS delta, value FROM gauge_metrics")
+	}
+
+	keys := []string{`name COLLATE "C"`, "type"}
+	if query.Sort.ByType() {
+		keys = []string{"type", `name COLLATE "C"`}
+	}
+
+	var conditions []string
+	if query.Prefix != "" {
+		conditions = append(conditions, `name COLLATE "C" LIKE `+arg(escapeLike(query.Prefix)+"%"))
+	}
+	if query.After != nil {
+		after := []string{arg(query.After.ID), arg(query.After.MType)}
+		if query.Sort.ByType() {
+			after[0], after[1] = after[1], after[0]
+		}
+
+		cmp := ">"
+		if query.Sort.Descending() {
+			cmp = "<"
+		}
+		conditions = append(conditions, "("+strings.Join(keys, ", ")+") "+cmp+" ("+strings.Join(after, ", ")+")")
+	}
+
+	direction := " ASC"
+	if query.Sort.Descending() {
+		direction = " DESC"
+	}
+
+	queryString := "SELECT type, name, delta, value FROM (" + strings.Join(tables, " UNION ALL ") + ") AS metrics"
+	if len(conditions) != 0 {
+		queryString += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	queryString += " ORDER BY " + strings.Join(keys, direction+", ") + direction + " LIMIT " + arg(query.Limit)
+
+	return queryString, args
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
 func getMetrics[K string, V Number](stmt *sql.Stmt, m map[string]V) map[string]V {
 	rows, err := stmt.Query()
 	if err != nil {
@@ -237,6 +237,27 @@ func TestGetCounterMetric_Error(t *testing.T) {
 	}
 }
 
+func TestGetCounterMetric_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectPrepare("SELECT value FROM counter_metrics")
+	mock.ExpectQuery("SELECT value FROM counter_metrics").WithArgs("test").WillReturnRows(sqlmock.NewRows([]string{"value"}))
+
+	dbstor := NewDBStorage(db)
+
+	if _, err := dbstor.GetCounterMetric("test"); !errors.Is(err, ErrMetricNotFound) {
+		t.Errorf("expected ErrMetricNotFound, but got %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
 func TestGetGaugeMetric(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
@@ -438,3 +459,76 @@ func TestGetMetrics_Float64(t *testing.T) {
 		t.Errorf("there were unfulfilled expectations: %s", err)
 	}
 }
+
+func TestListMetrics(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"type", "name", "delta", "value"}).
+		AddRow("counter", "mem_count", 5, nil).
+		AddRow("gauge", "mem_free", nil, 1.5)
+
+	mock.ExpectQuery("SELECT type, name, delta, value FROM ("+
+		"SELECT 'counter' AS type, name, value AS delta, NULL::DOUBLE PRECISION AS value FROM counter_metrics UNION ALL "+
+		"SELECT 'gauge' AS type, name, NULL::BIGINT AS delta, value FROM gauge_metrics) AS metrics "+
+		`WHERE name COLLATE "C" LIKE $1 AND (name COLLATE "C", type) > ($2, $3) `+
+		`ORDER BY name COLLATE "C" ASC, type ASC LIMIT $4`).
+		WithArgs(`mem\_%`, "mem", "gauge", 10).
+		WillReturnRows(rows)
+
+	dbstor := NewDBStorage(db)
+
+	metrics, err := dbstor.ListMetrics(domain.MetricsQuery{
+		Prefix: "mem_",
+		Sort:   domain.SortByName,
+		After:  &domain.MetricKey{ID: "mem", MType: domain.GaugeType},
+		Limit:  10,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, metrics, 2)
+	assert.Equal(t, int64(5), *metrics[0].Delta)
+	assert.Nil(t, metrics[0].Value)
+	assert.Equal(t, 1.5, *metrics[1].Value)
+	assert.Nil(t, metrics[1].Delta)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestListMetricsQuery(t *testing.T) {
+	query, args := listMetricsQuery(domain.MetricsQuery{
+		MType: domain.CounterType,
+		Sort:  domain.SortByTypeDesc,
+		After: &domain.MetricKey{ID: "b", MType: domain.CounterType},
+		Limit: 3,
+	})
+
+	assert.Equal(t, "SELECT type, name, delta, value FROM ("+
+		"SELECT 'counter' AS type, name, value AS delta, NULL::DOUBLE PRECISION AS value FROM counter_metrics) AS metrics "+
+		`WHERE (type, name COLLATE "C") < ($2, $1) ORDER BY type DESC, name COLLATE "C" DESC LIMIT $3`, query)
+	assert.Equal(t, []any{"b", domain.CounterType, 3}, args)
+}
+
+func TestListMetrics_Error(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT type, name, delta, value").WillReturnError(errors.New("query error"))
+
+	dbstor := NewDBStorage(db)
+
+	if _, err := dbstor.ListMetrics(domain.MetricsQuery{Sort: domain.SortByName, Limit: 10}); err == nil {
+		t.Error("expected an error, but got nil")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -31,7 +31,7 @@ func (fs *FileSnapshot) SaveData() error {
 
 	var file *os.File
 	if err == nil && fileInfo.Mode().IsRegular() {
-		file, err = os.OpenFile(fs.fileName, os.O_RDWR, snapshotFilePermissions)
+		file, err = os.OpenFile(fs.fileName, os.O_RDWR|os.O_TRUNC, snapshotFilePermissions)
 		if err != nil {
 			return err
 		}
@@ -39,6 +39,34 @@ func TestSaveData_Success(t *testing.T) {
 	assert.Equal(t, "test data", string(fileContent))
 }
 
+func TestSaveData_Truncates(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSnap := mocks.NewMockSnapshooter(ctrl)
+
+	tmpFile, err := os.CreateTemp("", "testfile")
+	assert.NoError(t, err)
+	defer os.Remove(tmpFile.Name())
+
+	_, err = tmpFile.WriteString("previous longer data")
+	assert.NoError(t, err)
+	assert.NoError(t, tmpFile.Close())
+
+	mockSnap.EXPECT().
+		SaveToSnapshot(gomock.Any()).
+		DoAndReturn(func(writer *bufio.Writer) error {
+			_, writerErr := writer.WriteString("[]")
+			return writerErr
+		})
+
+	assert.NoError(t, NewFileSnapshot(mockSnap, tmpFile.Name()).SaveData())
+
+	fileContent, err := os.ReadFile(tmpFile.Name())
+	assert.NoError(t, err)
+	assert.Equal(t, "[]", string(fileContent))
+}
+
 func TestSaveData_Error(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
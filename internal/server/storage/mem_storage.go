@@ -1,14 +1,22 @@
 package storage
 
 import (
-	"errors"
+	"fmt"
+	"maps"
+	"sort"
+	"sync"
 
 	"github.com/frolmr/metrics/internal/domain"
 )
 
+// MemStorage keeps metrics in maps guarded by mu, it is shared by HTTP and gRPC handlers and by ingest listeners.
 type MemStorage struct {
+	mu sync.RWMutex
+
 	CounterMetrics map[string]int64
 	GaugeMetrics   map[string]float64
+
+	validator *domain.Validator
 }
 
 func NewMemStorage() *MemStorage {
@@ -18,55 +26,129 @@ func NewMemStorage() *MemStorage {
 	}
 }
 
-func (ms MemStorage) Ping() error {
+// SetValidator sets validator applied to metrics restored from snapshot, default rules are used if not set.
+func (ms *MemStorage) SetValidator(validator *domain.Validator) {
+	ms.validator = validator
+}
+
+func (ms *MemStorage) Ping() error {
 	return nil
 }
 
-func (ms MemStorage) UpdateCounterMetric(name string, value int64) error {
+func (ms *MemStorage) UpdateCounterMetric(name string, value int64) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
 	ms.CounterMetrics[name] += value
 	return nil
 }
 
-func (ms MemStorage) UpdateGaugeMetric(name string, value float64) error {
+func (ms *MemStorage) UpdateGaugeMetric(name string, value float64) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
 	ms.GaugeMetrics[name] = value
 	return nil
 }
 
-func (ms MemStorage) UpdateMetrics(metrics []domain.Metrics) error {
+// UpdateMetrics saves metrics only if all of them are valid, so that malformed batch is not saved partially.
+func (ms *MemStorage) UpdateMetrics(metrics []domain.Metrics) error {
+	for i, v := range metrics {
+		if err := v.Validate(); err != nil {
+			return fmt.Errorf("metric %d (%s): %w", i, v.ID, err)
+		}
+	}
+
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
 	for _, v := range metrics {
 		if v.MType == domain.CounterType {
-			if err := ms.UpdateCounterMetric(v.ID, *v.Delta); err != nil {
-				return err
-			}
+			ms.CounterMetrics[v.ID] += *v.Delta
 		} else {
-			if err := ms.UpdateGaugeMetric(v.ID, *v.Value); err != nil {
-				return err
-			}
+			ms.GaugeMetrics[v.ID] = *v.Value
 		}
 	}
 	return nil
 }
 
-func (ms MemStorage) GetCounterMetric(name string) (int64, error) {
+func (ms *MemStorage) GetCounterMetric(name string) (int64, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
 	if value, exists := ms.CounterMetrics[name]; !exists {
-		return 0, errors.New("value not found")
+		return 0, ErrMetricNotFound
 	} else {
 		return value, nil
 	}
 }
 
-func (ms MemStorage) GetGaugeMetric(name string) (float64, error) {
+func (ms *MemStorage) GetGaugeMetric(name string) (float64, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
 	if value, exists := ms.GaugeMetrics[name]; !exists {
-		return 0, errors.New("value not found")
+		return 0, ErrMetricNotFound
 	} else {
 		return value, nil
 	}
 }
 
-func (ms MemStorage) GetCounterMetrics() (map[string]int64, error) {
-	return ms.CounterMetrics, nil
+// GetCounterMetrics returns a copy, so that callers may range over it while metrics are updated.
+func (ms *MemStorage) GetCounterMetrics() (map[string]int64, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	return maps.Clone(ms.CounterMetrics), nil
 }
 
-func (ms MemStorage) GetGaugeMetrics() (map[string]float64, error) {
-	return ms.GaugeMetrics, nil
+// GetGaugeMetrics returns a copy, so that callers may range over it while metrics are updated.
+func (ms *MemStorage) GetGaugeMetrics() (map[string]float64, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	return maps.Clone(ms.GaugeMetrics), nil
+}
+
+// ListMetrics sorts matching keys on every call, memory storage is small enough for that.
+func (ms *MemStorage) ListMetrics(query domain.MetricsQuery) ([]domain.Metrics, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	keys := make([]domain.MetricKey, 0, len(ms.CounterMetrics)+len(ms.GaugeMetrics))
+	for name := range ms.CounterMetrics {
+		keys = append(keys, domain.MetricKey{ID: name, MType: domain.CounterType})
+	}
+	for name := range ms.GaugeMetrics {
+		keys = append(keys, domain.MetricKey{ID: name, MType: domain.GaugeType})
+	}
+
+	matched := keys[:0]
+	for _, k := range keys {
+		if query.Match(k) && (query.After == nil || query.Less(*query.After, k)) {
+			matched = append(matched, k)
+		}
+	}
+
+	sort.Slice(matched, func(i, j int) bool {
+		return query.Less(matched[i], matched[j])
+	})
+
+	if len(matched) > query.Limit {
+		matched = matched[:query.Limit]
+	}
+
+	metrics := make([]domain.Metrics, 0, len(matched))
+	for _, k := range matched {
+		m := domain.Metrics{ID: k.ID, MType: k.MType}
+		if k.MType == domain.CounterType {
+			delta := ms.CounterMetrics[k.ID]
+			m.Delta = &delta
+		} else {
+			value := ms.GaugeMetrics[k.ID]
+			m.Value = &value
+		}
+		metrics = append(metrics, m)
+	}
+	return metrics, nil
 }
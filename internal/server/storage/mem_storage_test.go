@@ -1,6 +1,9 @@
 package storage
 
 import (
+	"bytes"
+	"fmt"
+	"sync"
 	"testing"
 
 	"github.com/frolmr/metrics/internal/domain"
@@ -8,7 +11,7 @@ import (
 )
 
 func TestMemStorage(t *testing.T) {
-	ms := MemStorage{
+	ms := &MemStorage{
 		CounterMetrics: map[string]int64{"cm1": 1},
 		GaugeMetrics:   map[string]float64{"gm1": 0.2},
 	}
@@ -76,3 +79,103 @@ func TestMemStorageUpdateMetrics_EmptyInput(t *testing.T) {
 	err := ms.UpdateMetrics([]domain.Metrics{})
 	assert.NoError(t, err)
 }
+
+func TestMemStorageUpdateMetrics_InvalidMetric(t *testing.T) {
+	ms := NewMemStorage()
+	value := 1.5
+
+	err := ms.UpdateMetrics([]domain.Metrics{
+		{ID: "gauge", MType: domain.GaugeType, Value: &value},
+		{ID: "counter", MType: domain.CounterType},
+	})
+	assert.ErrorIs(t, err, domain.ErrMissingDelta)
+	assert.Empty(t, ms.GaugeMetrics, "batch with invalid metric must not be saved partially")
+}
+
+func TestMemStorageListMetrics(t *testing.T) {
+	ms := &MemStorage{
+		CounterMetrics: map[string]int64{"poll_count": 5, "requests": 3, "cpu": 1},
+		GaugeMetrics:   map[string]float64{"cpu": 0.5, "mem_free": 10, "mem_used": 20},
+	}
+
+	t.Run("pages cover all metrics in order", func(t *testing.T) {
+		query := domain.MetricsQuery{Sort: domain.SortByName, Limit: 2}
+
+		var names []string
+		for {
+			page, err := ms.ListMetrics(query)
+			assert.NoError(t, err)
+			if len(page) == 0 {
+				break
+			}
+			for _, m := range page {
+				names = append(names, m.MType+":"+m.ID)
+			}
+			last := page[len(page)-1]
+			query.After = &domain.MetricKey{ID: last.ID, MType: last.MType}
+		}
+
+		assert.Equal(t, []string{
+			"counter:cpu", "gauge:cpu", "gauge:mem_free", "gauge:mem_used", "counter:poll_count", "counter:requests",
+		}, names)
+	})
+
+	t.Run("filters by type and prefix", func(t *testing.T) {
+		page, err := ms.ListMetrics(domain.MetricsQuery{
+			MType: domain.GaugeType, Prefix: "mem_", Sort: domain.SortByNameDesc, Limit: 10,
+		})
+		assert.NoError(t, err)
+		assert.Len(t, page, 2)
+		assert.Equal(t, "mem_used", page[0].ID)
+		assert.Equal(t, 20.0, *page[0].Value)
+		assert.Equal(t, "mem_free", page[1].ID)
+	})
+
+	t.Run("counter values", func(t *testing.T) {
+		page, err := ms.ListMetrics(domain.MetricsQuery{MType: domain.CounterType, Prefix: "poll", Sort: domain.SortByName, Limit: 10})
+		assert.NoError(t, err)
+		assert.Len(t, page, 1)
+		assert.Equal(t, int64(5), *page[0].Delta)
+		assert.Nil(t, page[0].Value)
+	})
+}
+
+func TestMemStorage_ConcurrentUpdates(t *testing.T) {
+	ms := NewMemStorage()
+
+	const workers, updates = 8, 200
+	delta := int64(1)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < updates; i++ {
+				value := float64(i)
+				_ = ms.UpdateCounterMetric("hits", 1)
+				_ = ms.UpdateGaugeMetric(fmt.Sprintf("gauge_%d", i%10), value)
+				_ = ms.UpdateMetrics([]domain.Metrics{
+					{ID: "hits", MType: domain.CounterType, Delta: &delta},
+					{ID: "load", MType: domain.GaugeType, Value: &value},
+				})
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for i := 0; i < updates; i++ {
+				_, _ = ms.GetCounterMetric("hits")
+				gauges, _ := ms.GetGaugeMetrics()
+				for range gauges {
+				}
+				_, _ = ms.ListMetrics(domain.MetricsQuery{Sort: domain.SortByName, Limit: 5})
+				_ = ms.SaveToSnapshot(&bytes.Buffer{})
+			}
+		}()
+	}
+	wg.Wait()
+
+	hits, err := ms.GetCounterMetric("hits")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(workers*updates*2), hits)
+}
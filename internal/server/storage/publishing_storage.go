@@ -0,0 +1,48 @@
+package storage
+
+import (
+	"github.com/frolmr/metrics/internal/domain"
+)
+
+// Publisher receives metric updates accepted by storage.
+type Publisher interface {
+	Publish(metrics []domain.Metrics)
+}
+
+// PublishingStorage wraps repository and publishes every successful update.
+type PublishingStorage struct {
+	Repository
+	publisher Publisher
+}
+
+// NewPublishingStorage function is constructor for publishing storage decorator.
+func NewPublishingStorage(repo Repository, publisher Publisher) *PublishingStorage {
+	return &PublishingStorage{
+		Repository: repo,
+		publisher:  publisher,
+	}
+}
+
+func (ps PublishingStorage) UpdateCounterMetric(name string, value int64) error {
+	if err := ps.Repository.UpdateCounterMetric(name, value); err != nil {
+		return err
+	}
+	ps.publisher.Publish([]domain.Metrics{{ID: name, MType: domain.CounterType, Delta: &value}})
+	return nil
+}
+
+func (ps PublishingStorage) UpdateGaugeMetric(name string, value float64) error {
+	if err := ps.Repository.UpdateGaugeMetric(name, value); err != nil {
+		return err
+	}
+	ps.publisher.Publish([]domain.Metrics{{ID: name, MType: domain.GaugeType, Value: &value}})
+	return nil
+}
+
+func (ps PublishingStorage) UpdateMetrics(metrics []domain.Metrics) error {
+	if err := ps.Repository.UpdateMetrics(metrics); err != nil {
+		return err
+	}
+	ps.publisher.Publish(metrics)
+	return nil
+}
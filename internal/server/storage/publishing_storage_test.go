@@ -0,0 +1,59 @@
+package storage
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/frolmr/metrics/internal/domain"
+	"github.com/frolmr/metrics/internal/server/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+type recordingPublisher struct {
+	published []domain.Metrics
+}
+
+func (rp *recordingPublisher) Publish(metrics []domain.Metrics) {
+	rp.published = append(rp.published, metrics...)
+}
+
+func TestPublishingStorage(t *testing.T) {
+	t.Run("publishes successful updates", func(t *testing.T) {
+		pub := &recordingPublisher{}
+		ps := NewPublishingStorage(NewMemStorage(), pub)
+
+		assert.NoError(t, ps.UpdateCounterMetric("c", 2))
+		assert.NoError(t, ps.UpdateGaugeMetric("g", 1.5))
+
+		value := 3.5
+		assert.NoError(t, ps.UpdateMetrics([]domain.Metrics{{ID: "g2", MType: domain.GaugeType, Value: &value}}))
+
+		assert.Len(t, pub.published, 3)
+		assert.Equal(t, int64(2), *pub.published[0].Delta)
+		assert.Equal(t, 1.5, *pub.published[1].Value)
+		assert.Equal(t, "g2", pub.published[2].ID)
+
+		val, err := ps.GetGaugeMetric("g2")
+		assert.NoError(t, err)
+		assert.Equal(t, 3.5, val)
+	})
+
+	t.Run("does not publish failed updates", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := mocks.NewMockRepository(ctrl)
+		repo.EXPECT().UpdateCounterMetric("c", int64(1)).Return(errors.New("db error"))
+		repo.EXPECT().UpdateGaugeMetric("g", 1.0).Return(errors.New("db error"))
+		repo.EXPECT().UpdateMetrics(gomock.Any()).Return(errors.New("db error"))
+
+		pub := &recordingPublisher{}
+		ps := NewPublishingStorage(repo, pub)
+
+		assert.Error(t, ps.UpdateCounterMetric("c", 1))
+		assert.Error(t, ps.UpdateGaugeMetric("g", 1))
+		assert.Error(t, ps.UpdateMetrics(nil))
+		assert.Empty(t, pub.published)
+	})
+}
@@ -1,9 +1,14 @@
 package storage
 
 import (
+	"errors"
+
 	"github.com/frolmr/metrics/internal/domain"
 )
 
+// ErrMetricNotFound is returned by GetCounterMetric and GetGaugeMetric when storage has no such metric.
+var ErrMetricNotFound = errors.New("value not found")
+
 type Repository interface {
 	Ping() error
 	UpdateCounterMetric(name string, value int64) error
@@ -15,4 +20,7 @@ type Repository interface {
 
 	GetCounterMetrics() (map[string]int64, error)
 	GetGaugeMetrics() (map[string]float64, error)
+
+	// ListMetrics returns up to query.Limit metrics following query.After in query order.
+	ListMetrics(query domain.MetricsQuery) ([]domain.Metrics, error)
 }
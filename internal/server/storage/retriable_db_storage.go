@@ -124,6 +124,19 @@ func (rs RetriableStorage) GetGaugeMetrics() (res map[string]float64, err error)
 	return
 }
 
+func (rs RetriableStorage) ListMetrics(query domain.MetricsQuery) (res []domain.Metrics, err error) {
+	for _, interval := range rs.retryIntervals {
+		res, err = rs.dbStorage.ListMetrics(query)
+		if err == nil {
+			return res, nil
+		}
+		if rs.isRetriable(err) {
+			time.Sleep(interval)
+		}
+	}
+	return
+}
+
 func (rs RetriableStorage) isRetriable(err error) bool {
 	var connErr *pgconn.ConnectError
 	return errors.As(err, &connErr)
@@ -19,13 +19,24 @@ func (ms *MemStorage) RestoreFromSnapshot(source io.Reader) error {
 		return err
 	}
 
+	validator := ms.validator
+	if validator == nil {
+		validator = domain.DefaultValidator()
+	}
+
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
 	for _, metric := range metricsSnap {
+		if err := validator.Validate(metric); err != nil {
+			log.Println("invalid data in snapshot: ", metric.ID, err)
+			continue
+		}
+
 		if metric.MType == domain.GaugeType {
 			ms.GaugeMetrics[metric.ID] = *metric.Value
-		} else if metric.MType == domain.CounterType {
-			ms.CounterMetrics[metric.ID] = *metric.Delta
 		} else {
-			log.Println("invalid data in snapshot: ", metric.MType)
+			ms.CounterMetrics[metric.ID] = *metric.Delta
 		}
 	}
 
@@ -35,12 +46,14 @@ func (ms *MemStorage) RestoreFromSnapshot(source io.Reader) error {
 func (ms *MemStorage) SaveToSnapshot(destination io.Writer) error {
 	var metricsJSON []domain.Metrics
 
+	ms.mu.RLock()
 	for name, value := range ms.CounterMetrics {
 		metricsJSON = append(metricsJSON, domain.Metrics{ID: name, MType: domain.CounterType, Delta: &value})
 	}
 	for name, value := range ms.GaugeMetrics {
 		metricsJSON = append(metricsJSON, domain.Metrics{ID: name, MType: domain.GaugeType, Value: &value})
 	}
+	ms.mu.RUnlock()
 
 	data, err := json.MarshalIndent(metricsJSON, "", " ")
 	if err != nil {
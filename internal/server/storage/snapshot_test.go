@@ -3,6 +3,7 @@ package storage
 import (
 	"bufio"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -13,7 +14,7 @@ const (
 )
 
 func TestSnaphots(t *testing.T) {
-	ms := MemStorage{
+	ms := &MemStorage{
 		CounterMetrics: map[string]int64{"cm1": 1},
 		GaugeMetrics:   map[string]float64{"gm1": 0.2},
 	}
@@ -34,3 +35,18 @@ func TestSnaphots(t *testing.T) {
 		assert.Equal(t, ms.GaugeMetrics["gm1"], float64(8.8))
 	})
 }
+
+func TestRestoreFromSnapshotSkipsInvalidMetrics(t *testing.T) {
+	ms := NewMemStorage()
+
+	snapshot := `[
+		{"id": "valid", "type": "counter", "delta": 5},
+		{"id": "no_delta", "type": "counter"},
+		{"id": "bad name", "type": "gauge", "value": 1.5},
+		{"id": "unknown", "type": "histogram", "value": 1.5}
+	]`
+
+	assert.NoError(t, ms.RestoreFromSnapshot(strings.NewReader(snapshot)))
+	assert.Equal(t, map[string]int64{"valid": 5}, ms.CounterMetrics)
+	assert.Empty(t, ms.GaugeMetrics)
+}
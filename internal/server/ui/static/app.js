@@ -0,0 +1,145 @@
+'use strict';
+
+// Dashboard polls the versioned API and keeps recent values in memory for sparklines.
+(function () {
+  const api = '../api/v1/metrics';
+  const pageSize = 1000;
+  const historySize = 60;
+  const sparkWidth = 160;
+  const sparkHeight = 24;
+
+  const history = new Map();
+  let metrics = [];
+  let timer = null;
+
+  const el = (id) => document.getElementById(id);
+
+  async function fetchMetrics() {
+    const all = [];
+    let cursor = '';
+    do {
+      const params = new URLSearchParams({limit: String(pageSize)});
+      if (cursor) {
+        params.set('cursor', cursor);
+      }
+      const res = await fetch(api + '?' + params, {headers: {Accept: 'application/json'}});
+      const body = await res.json();
+      if (!res.ok) {
+        throw new Error(body.message || res.statusText);
+      }
+      all.push(...body.metrics);
+      cursor = body.next_cursor || '';
+    } while (cursor);
+    return all;
+  }
+
+  function valueOf(m) {
+    return m.type === 'counter' ? m.delta : m.value;
+  }
+
+  function remember(list) {
+    const seen = new Set();
+    for (const m of list) {
+      const key = m.type + ':' + m.id;
+      seen.add(key);
+      const values = history.get(key) || [];
+      values.push(valueOf(m));
+      if (values.length > historySize) {
+        values.shift();
+      }
+      history.set(key, values);
+    }
+    for (const key of history.keys()) {
+      if (!seen.has(key)) {
+        history.delete(key);
+      }
+    }
+  }
+
+  function sparkline(values) {
+    const ns = 'http://www.w3.org/2000/svg';
+    const svg = document.createElementNS(ns, 'svg');
+    svg.setAttribute('class', 'sparkline');
+    svg.setAttribute('width', sparkWidth);
+    svg.setAttribute('height', sparkHeight);
+    svg.setAttribute('viewBox', '0 0 ' + sparkWidth + ' ' + sparkHeight);
+
+    if (values.length < 2) {
+      return svg;
+    }
+
+    const min = Math.min(...values);
+    const max = Math.max(...values);
+    const span = max - min || 1;
+    const step = sparkWidth / (historySize - 1);
+    const offset = sparkWidth - step * (values.length - 1);
+
+    const points = values.map((v, i) => {
+      const x = offset + i * step;
+      const y = sparkHeight - 1 - ((v - min) / span) * (sparkHeight - 2);
+      return x.toFixed(1) + ',' + y.toFixed(1);
+    });
+
+    const line = document.createElementNS(ns, 'polyline');
+    line.setAttribute('points', points.join(' '));
+    svg.appendChild(line);
+    return svg;
+  }
+
+  function row(m) {
+    const tr = document.createElement('tr');
+
+    const name = document.createElement('td');
+    name.className = 'name';
+    name.textContent = m.id;
+
+    const value = document.createElement('td');
+    value.className = 'value';
+    value.textContent = String(valueOf(m));
+
+    const chart = document.createElement('td');
+    chart.appendChild(sparkline(history.get(m.type + ':' + m.id) || []));
+
+    tr.append(name, value, chart);
+    return tr;
+  }
+
+  function render() {
+    const search = el('search').value.trim().toLowerCase();
+    const visible = metrics.filter((m) => m.id.toLowerCase().includes(search));
+
+    for (const type of ['gauge', 'counter']) {
+      const rows = visible.filter((m) => m.type === type).map(row);
+      el(type).replaceChildren(...rows);
+      el(type + '-count').textContent = '(' + rows.length + ')';
+    }
+  }
+
+  async function refresh() {
+    try {
+      metrics = await fetchMetrics();
+      remember(metrics);
+      el('error').hidden = true;
+      el('status').textContent = 'Updated ' + new Date().toLocaleTimeString();
+    } catch (err) {
+      el('error').textContent = 'Failed to load metrics: ' + err.message;
+      el('error').hidden = false;
+    }
+    render();
+  }
+
+  function schedule() {
+    clearInterval(timer);
+    const interval = Number(el('interval').value);
+    if (interval > 0) {
+      timer = setInterval(refresh, interval);
+    }
+  }
+
+  el('search').addEventListener('input', render);
+  el('interval').addEventListener('change', schedule);
+  el('refresh').addEventListener('click', refresh);
+
+  refresh();
+  schedule();
+})();
@@ -0,0 +1,30 @@
+// Package ui serves embedded HTML dashboard, it needs nothing but the versioned API and works offline.
+package ui
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+)
+
+// Prefix is the path dashboard is served at.
+const Prefix = "/ui"
+
+//go:embed static
+var static embed.FS
+
+// Handler serves dashboard files for requests under Prefix.
+func Handler() http.Handler {
+	files, err := fs.Sub(static, "static")
+	if err != nil {
+		// NOTE: embedded directory is checked at compile time, so this never happens
+		panic(err)
+	}
+
+	return http.StripPrefix(Prefix, http.FileServer(http.FS(files)))
+}
+
+// Redirect sends requests of Prefix without trailing slash to the dashboard index.
+func Redirect(res http.ResponseWriter, req *http.Request) {
+	http.Redirect(res, req, Prefix+"/", http.StatusMovedPermanently)
+}
@@ -0,0 +1,76 @@
+package ui
+
+import (
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler(t *testing.T) {
+	ts := httptest.NewServer(Handler())
+	defer ts.Close()
+
+	tests := []struct {
+		path        string
+		contentType string
+		contains    string
+	}{
+		{path: "/ui/", contentType: "text/html; charset=utf-8", contains: `<script src="app.js">`},
+		{path: "/ui/app.js", contentType: "text/javascript; charset=utf-8", contains: "api/v1/metrics"},
+		{path: "/ui/style.css", contentType: "text/css; charset=utf-8", contains: "sparkline"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			resp, err := ts.Client().Get(ts.URL + tt.path)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+			assert.Equal(t, tt.contentType, resp.Header.Get("Content-Type"))
+			assert.Contains(t, string(body), tt.contains)
+		})
+	}
+
+	resp, err := ts.Client().Get(ts.URL + "/ui/missing.js")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestNoExternalResources(t *testing.T) {
+	// NOTE: SVG namespace is an identifier, browser doesn't load it
+	external := regexp.MustCompile(`(https?:)?//[a-zA-Z0-9.-]+\.[a-z]{2,}`)
+
+	err := fs.WalkDir(static, "static", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		data, err := static.ReadFile(path)
+		require.NoError(t, err)
+
+		for _, match := range external.FindAllString(string(data), -1) {
+			assert.Equal(t, "http://www.w3.org", match, path)
+		}
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+func TestRedirect(t *testing.T) {
+	rec := httptest.NewRecorder()
+	Redirect(rec, httptest.NewRequest(http.MethodGet, Prefix, nil))
+
+	assert.Equal(t, http.StatusMovedPermanently, rec.Code)
+	assert.Equal(t, "/ui/", rec.Header().Get("Location"))
+}
@@ -0,0 +1,256 @@
+// Package client pushes metrics to metrics server from Go applications.
+//
+// Values are accumulated in memory and sent in batches in background, the same way agent reports host metrics:
+// signed with key, encrypted with server public key and compressed, over HTTP or gRPC.
+//
+//	c, err := client.New(client.Config{Scheme: "http", Address: "localhost:8080", Key: "secret"})
+//	if err != nil {
+//		return err
+//	}
+//	defer c.Close()
+//
+//	c.Gauge("queue_size").Set(float64(len(queue)))
+//	c.Counter("orders_total").Add(1)
+package client
+
+import (
+	"context"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultFlushInterval is period of background flush if Config doesn't set it.
+	DefaultFlushInterval = 10 * time.Second
+	// DefaultTimeout limits single request if Config doesn't set it.
+	DefaultTimeout = 5 * time.Second
+)
+
+// DefaultRetryIntervals are pauses between attempts to send batch while server is unreachable.
+var DefaultRetryIntervals = []time.Duration{time.Second, 2 * time.Second, 5 * time.Second}
+
+// ErrServer is returned when server doesn't accept batch.
+var ErrServer = errors.New("server error")
+
+// Config describes server, credentials and batching.
+type Config struct {
+	// Scheme is http, https or grpc.
+	Scheme string
+	// Address is host and port of the server.
+	Address string
+
+	// Key signs metrics, server checks signature if it has the same key.
+	Key string
+	// CryptoKey encrypts metrics, server decrypts them with its private key.
+	CryptoKey *rsa.PublicKey
+	// HostIP returns address sent to server checking trusted subnet, nothing is sent if it is nil.
+	HostIP func() string
+
+	// GRPCStream sends batches over one long-living gRPC stream instead of a call per batch.
+	GRPCStream bool
+
+	// FlushInterval is period of background flush.
+	FlushInterval time.Duration
+	// Timeout limits single request.
+	Timeout time.Duration
+	// RetryIntervals are pauses between attempts, DefaultRetryIntervals if nil.
+	RetryIntervals []time.Duration
+
+	// ErrorHandler is called when background flush fails, errors are logged if it is nil.
+	ErrorHandler func(err error)
+}
+
+func (cfg Config) withDefaults() Config {
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = DefaultFlushInterval
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultTimeout
+	}
+	if cfg.RetryIntervals == nil {
+		cfg.RetryIntervals = DefaultRetryIntervals
+	}
+	if cfg.ErrorHandler == nil {
+		cfg.ErrorHandler = func(err error) {
+			log.Println("failed to flush metrics: ", err)
+		}
+	}
+	return cfg
+}
+
+// Batch is a set of gauge values and counter deltas sent in one request.
+type Batch struct {
+	Gauges   map[string]float64
+	Counters map[string]int64
+}
+
+// Len returns number of metrics in batch.
+func (b Batch) Len() int {
+	return len(b.Gauges) + len(b.Counters)
+}
+
+// Sender delivers batches to the server, retrying while server is unreachable.
+type Sender interface {
+	Send(ctx context.Context, batch Batch) error
+	Close() error
+}
+
+// NewSender function is constructor for sender of the scheme given by config.
+func NewSender(cfg Config) (Sender, error) {
+	cfg = cfg.withDefaults()
+
+	switch cfg.Scheme {
+	case "http", "https":
+		return NewHTTPSender(cfg), nil
+	case "grpc":
+		return NewGRPCSender(cfg)
+	default:
+		return nil, fmt.Errorf("unknown scheme %q", cfg.Scheme)
+	}
+}
+
+// Client accumulates metrics and flushes them in background, it is safe for concurrent use.
+type Client struct {
+	sender       Sender
+	errorHandler func(err error)
+
+	mu       sync.Mutex
+	gauges   map[string]float64
+	counters map[string]int64
+
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// New function is constructor for client, it starts background flush.
+func New(cfg Config) (*Client, error) {
+	sender, err := NewSender(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return NewWithSender(sender, cfg), nil
+}
+
+// NewWithSender function is constructor for client sending batches with sender, only batching part of cfg is used.
+func NewWithSender(sender Sender, cfg Config) *Client {
+	cfg = cfg.withDefaults()
+
+	c := &Client{
+		sender:       sender,
+		errorHandler: cfg.ErrorHandler,
+		gauges:       make(map[string]float64),
+		counters:     make(map[string]int64),
+		stop:         make(chan struct{}),
+		done:         make(chan struct{}),
+	}
+	go c.run(cfg.FlushInterval)
+
+	return c
+}
+
+// Gauge returns handle of the gauge, handles of the same name share value.
+func (c *Client) Gauge(name string) *Gauge {
+	return &Gauge{client: c, name: name}
+}
+
+// Counter returns handle of the counter, handles of the same name share value.
+func (c *Client) Counter(name string) *Counter {
+	return &Counter{client: c, name: name}
+}
+
+// Flush sends metrics accumulated since the previous flush.
+// Metrics are kept for the next flush if server is unreachable and dropped if server rejects them.
+func (c *Client) Flush(ctx context.Context) error {
+	c.mu.Lock()
+	batch := Batch{Gauges: c.gauges, Counters: c.counters}
+	c.gauges = make(map[string]float64)
+	c.counters = make(map[string]int64)
+	c.mu.Unlock()
+
+	if batch.Len() == 0 {
+		return nil
+	}
+
+	err := c.sender.Send(ctx, batch)
+	if err != nil && isRetriable(err) {
+		c.restore(batch)
+	}
+	return err
+}
+
+// Close stops background flush, flushes what is left and closes connection to the server.
+// Metrics must not be updated after Close.
+func (c *Client) Close() error {
+	c.closeOnce.Do(func() {
+		close(c.stop)
+		<-c.done
+
+		c.closeErr = errors.Join(c.Flush(context.Background()), c.sender.Close())
+	})
+	return c.closeErr
+}
+
+func (c *Client) run(interval time.Duration) {
+	defer close(c.done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := c.Flush(context.Background()); err != nil {
+				c.errorHandler(err)
+			}
+		case <-c.stop:
+			return
+		}
+	}
+}
+
+// restore returns unsent batch, gauges set since the flush are newer and win.
+func (c *Client) restore(batch Batch) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for name, value := range batch.Gauges {
+		if _, ok := c.gauges[name]; !ok {
+			c.gauges[name] = value
+		}
+	}
+	for name, delta := range batch.Counters {
+		c.counters[name] += delta
+	}
+}
+
+// Gauge is a metric which keeps the last value set.
+type Gauge struct {
+	client *Client
+	name   string
+}
+
+// Set sets gauge value sent with the next flush.
+func (g *Gauge) Set(value float64) {
+	g.client.mu.Lock()
+	g.client.gauges[g.name] = value
+	g.client.mu.Unlock()
+}
+
+// Counter is a metric which server sums up.
+type Counter struct {
+	client *Client
+	name   string
+}
+
+// Add adds delta to the counter, deltas are summed up until the next flush.
+func (c *Counter) Add(delta int64) {
+	c.client.mu.Lock()
+	c.client.counters[c.name] += delta
+	c.client.mu.Unlock()
+}
@@ -0,0 +1,173 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeSender struct {
+	mu      sync.Mutex
+	batches []Batch
+	err     error
+	closed  bool
+}
+
+func (s *fakeSender) Send(ctx context.Context, batch Batch) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.err != nil {
+		return s.err
+	}
+	s.batches = append(s.batches, batch)
+	return nil
+}
+
+func (s *fakeSender) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.closed = true
+	return nil
+}
+
+func (s *fakeSender) setErr(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.err = err
+}
+
+func (s *fakeSender) sent() []Batch {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Batch(nil), s.batches...)
+}
+
+func TestClientBatching(t *testing.T) {
+	sender := &fakeSender{}
+	c := NewWithSender(sender, Config{FlushInterval: time.Hour})
+
+	c.Gauge("load").Set(1)
+	c.Gauge("load").Set(2)
+	c.Counter("hits").Add(3)
+	c.Counter("hits").Add(4)
+
+	require.NoError(t, c.Flush(context.Background()))
+	require.NoError(t, c.Flush(context.Background()), "nothing to send")
+
+	c.Counter("hits").Add(1)
+	require.NoError(t, c.Close())
+	require.NoError(t, c.Close(), "close is idempotent")
+
+	assert.Equal(t, []Batch{
+		{Gauges: map[string]float64{"load": 2}, Counters: map[string]int64{"hits": 7}},
+		{Gauges: map[string]float64{}, Counters: map[string]int64{"hits": 1}},
+	}, sender.sent(), "the last batch is flushed on close")
+	assert.True(t, sender.closed)
+}
+
+func TestClientBackgroundFlush(t *testing.T) {
+	sender := &fakeSender{}
+	c := NewWithSender(sender, Config{FlushInterval: 10 * time.Millisecond})
+	defer c.Close()
+
+	c.Counter("hits").Add(1)
+
+	require.Eventually(t, func() bool { return len(sender.sent()) == 1 }, time.Second, 10*time.Millisecond)
+}
+
+func TestClientFlushFailure(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantKept bool
+	}{
+		{name: "unreachable server", err: syscall.ECONNREFUSED, wantKept: true},
+		{name: "rejected batch", err: ErrServer, wantKept: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sender := &fakeSender{err: tt.err}
+			c := NewWithSender(sender, Config{FlushInterval: time.Hour, ErrorHandler: func(error) {}})
+
+			c.Gauge("load").Set(1)
+			c.Counter("hits").Add(2)
+			require.ErrorIs(t, c.Flush(context.Background()), tt.err)
+
+			c.Gauge("load").Set(5)
+			c.Counter("hits").Add(3)
+
+			sender.setErr(nil)
+			require.NoError(t, c.Close())
+
+			want := Batch{Gauges: map[string]float64{"load": 5}, Counters: map[string]int64{"hits": 3}}
+			if tt.wantKept {
+				want.Counters["hits"] = 5
+			}
+			assert.Equal(t, []Batch{want}, sender.sent(), "gauge set after failed flush is newer")
+		})
+	}
+}
+
+func TestClientConcurrentUpdates(t *testing.T) {
+	sender := &fakeSender{}
+	c := NewWithSender(sender, Config{FlushInterval: time.Millisecond})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				c.Counter("hits").Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	require.NoError(t, c.Close())
+
+	var total int64
+	for _, b := range sender.sent() {
+		total += b.Counters["hits"]
+	}
+	assert.Equal(t, int64(1000), total, "no delta is lost between flushes")
+}
+
+func TestNewSender(t *testing.T) {
+	_, err := NewSender(Config{Scheme: "ftp", Address: "localhost:8080"})
+	assert.Error(t, err)
+
+	for _, scheme := range []string{"http", "https", "grpc"} {
+		s, err := NewSender(Config{Scheme: scheme, Address: "localhost:8080"})
+		require.NoError(t, err)
+		assert.NoError(t, s.Close())
+	}
+}
+
+func TestSendWithRetry(t *testing.T) {
+	var attempts int
+	err := sendWithRetry(context.Background(), []time.Duration{time.Millisecond, time.Millisecond}, func(context.Context) error {
+		attempts++
+		return syscall.ECONNREFUSED
+	})
+	assert.ErrorIs(t, err, syscall.ECONNREFUSED)
+	assert.Equal(t, 3, attempts)
+
+	attempts = 0
+	err = sendWithRetry(context.Background(), []time.Duration{time.Millisecond}, func(context.Context) error {
+		attempts++
+		return errors.New("bad request")
+	})
+	assert.Error(t, err)
+	assert.Equal(t, 1, attempts, "only unreachable server is retried")
+}
@@ -0,0 +1,288 @@
+package client
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+
+	"github.com/frolmr/metrics/internal/domain"
+	"github.com/frolmr/metrics/pkg/encryptor"
+	pb "github.com/frolmr/metrics/pkg/proto/metrics"
+	"github.com/frolmr/metrics/pkg/signer"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+)
+
+// GRPCSender sends batches with UpdateMetricsBulk call or, if GRPCStream is set, over StreamMetrics stream.
+type GRPCSender struct {
+	config Config
+	client pb.MetricsClient
+	conn   *grpc.ClientConn
+
+	streamMu     sync.Mutex
+	stream       grpc.BidiStreamingClient[pb.MetricsBatch, pb.BatchAck]
+	streamCancel context.CancelFunc
+	streamIP     string
+	batchID      uint64
+}
+
+// NewGRPCSender function is constructor for gRPC sender, connection is established lazily.
+func NewGRPCSender(cfg Config) (*GRPCSender, error) {
+	cfg = cfg.withDefaults()
+
+	// NOTE: payload is protected by crypto key the same way as over HTTP, not by transport
+	conn, err := grpc.NewClient(cfg.Address, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to gRPC server: %w", err)
+	}
+
+	return &GRPCSender{
+		config: cfg,
+		client: pb.NewMetricsClient(conn),
+		conn:   conn,
+	}, nil
+}
+
+func (s *GRPCSender) Close() error {
+	s.streamMu.Lock()
+	s.resetStream()
+	s.streamMu.Unlock()
+
+	if s.conn != nil {
+		return s.conn.Close()
+	}
+	return nil
+}
+
+func (s *GRPCSender) Send(ctx context.Context, batch Batch) error {
+	metrics := make([]*pb.Metric, 0, batch.Len())
+
+	for key, value := range batch.Gauges {
+		metric := &pb.Metric{
+			Key:  key,
+			Type: pb.Metric_MTYPE_GAUGE,
+			MValue: &pb.Metric_Value{
+				Value: value,
+			},
+		}
+		metrics = append(metrics, metric)
+	}
+
+	for key, value := range batch.Counters {
+		metric := &pb.Metric{
+			Key:  key,
+			Type: pb.Metric_MTYPE_COUNTER,
+			MValue: &pb.Metric_Delta{
+				Delta: value,
+			},
+		}
+		metrics = append(metrics, metric)
+	}
+
+	if len(metrics) == 0 {
+		return nil
+	}
+
+	req := &pb.UpdateMetricsBulkRequest{
+		Metrics: metrics,
+	}
+
+	send := s.sendMetrics
+	if s.config.GRPCStream {
+		send = s.sendBatch
+	}
+
+	return sendWithRetry(ctx, s.config.RetryIntervals, func(ctx context.Context) error {
+		return send(ctx, req)
+	})
+}
+
+func (s *GRPCSender) sendMetrics(ctx context.Context, req *pb.UpdateMetricsBulkRequest) error {
+	ctx, cancel := context.WithTimeout(ctx, s.config.Timeout)
+	defer cancel()
+
+	if s.config.HostIP != nil {
+		ctx = metadata.AppendToOutgoingContext(ctx, domain.RealIPHeader, s.config.HostIP())
+	}
+
+	if s.config.Key != "" {
+		signature, err := s.sign(req)
+		if err != nil {
+			return err
+		}
+		ctx = metadata.AppendToOutgoingContext(ctx, domain.SignatureHeader, signature)
+	}
+
+	if s.config.CryptoKey != nil {
+		encrypted, err := s.encrypt(req)
+		if err != nil {
+			return err
+		}
+		req = &pb.UpdateMetricsBulkRequest{EncryptedMetrics: encrypted}
+	}
+
+	resp, err := s.client.UpdateMetricsBulk(ctx, req)
+	if err != nil {
+		return fmt.Errorf("gRPC call failed: %w", err)
+	}
+	logRejected(resp.GetRejected())
+
+	if !resp.Received {
+		return notReceivedError(resp.Error)
+	}
+
+	return nil
+}
+
+// sendBatch sends metrics over long-living stream and waits for the batch ack.
+// Stream is (re)opened lazily and reset on any transport failure.
+func (s *GRPCSender) sendBatch(ctx context.Context, req *pb.UpdateMetricsBulkRequest) error {
+	s.streamMu.Lock()
+	defer s.streamMu.Unlock()
+
+	stream, err := s.openStream()
+	if err != nil {
+		return fmt.Errorf("failed to open metrics stream: %w", err)
+	}
+
+	s.batchID++
+	batch := &pb.MetricsBatch{
+		Id:      s.batchID,
+		Metrics: req.GetMetrics(),
+	}
+
+	if s.config.Key != "" {
+		signature, err := s.sign(req)
+		if err != nil {
+			return err
+		}
+		batch.Signature = &signature
+	}
+
+	if s.config.CryptoKey != nil {
+		encrypted, err := s.encrypt(req)
+		if err != nil {
+			return err
+		}
+		batch.Metrics = nil
+		batch.EncryptedMetrics = encrypted
+	}
+
+	if err := stream.Send(batch); err != nil {
+		s.resetStream()
+		return fmt.Errorf("failed to send batch: %w", err)
+	}
+
+	ackCh := make(chan batchAck, 1)
+	go func() {
+		ack, err := stream.Recv()
+		ackCh <- batchAck{ack: ack, err: err}
+	}()
+
+	ctx, cancel := context.WithTimeout(ctx, s.config.Timeout)
+	defer cancel()
+
+	select {
+	case res := <-ackCh:
+		if res.err != nil {
+			s.resetStream()
+			return fmt.Errorf("failed to receive batch ack: %w", res.err)
+		}
+		if res.ack.GetId() != batch.GetId() {
+			s.resetStream()
+			return fmt.Errorf("unexpected ack id %d for batch %d", res.ack.GetId(), batch.GetId())
+		}
+		logRejected(res.ack.GetRejected())
+		if !res.ack.GetReceived() {
+			return notReceivedError(res.ack.Error)
+		}
+	case <-ctx.Done():
+		// NOTE: ack of this batch may still come, stream is reset so that it isn't taken for the next one
+		s.resetStream()
+		return fmt.Errorf("batch %d ack timeout: %w", batch.GetId(), ctx.Err())
+	}
+
+	return nil
+}
+
+func notReceivedError(reason *string) error {
+	if reason != nil {
+		return fmt.Errorf("%w: %s", ErrServer, *reason)
+	}
+	return fmt.Errorf("%w: server did not acknowledge receipt of metrics", ErrServer)
+}
+
+func logRejected(rejected []*pb.MetricError) {
+	for _, r := range rejected {
+		log.Printf("metric %s rejected by server: %s", r.GetKey(), r.GetError())
+	}
+}
+
+type batchAck struct {
+	ack *pb.BatchAck
+	err error
+}
+
+func (s *GRPCSender) openStream() (grpc.BidiStreamingClient[pb.MetricsBatch, pb.BatchAck], error) {
+	var hostIP string
+	if s.config.HostIP != nil {
+		hostIP = s.config.HostIP()
+	}
+	if s.stream != nil && s.streamIP == hostIP {
+		return s.stream, nil
+	}
+	s.resetStream()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	if s.config.HostIP != nil {
+		ctx = metadata.AppendToOutgoingContext(ctx, domain.RealIPHeader, hostIP)
+	}
+
+	stream, err := s.client.StreamMetrics(ctx)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	s.stream = stream
+	s.streamCancel = cancel
+	s.streamIP = hostIP
+
+	return stream, nil
+}
+
+func (s *GRPCSender) resetStream() {
+	if s.stream == nil {
+		return
+	}
+
+	_ = s.stream.CloseSend()
+	s.streamCancel()
+
+	s.stream = nil
+	s.streamCancel = nil
+}
+
+func (s *GRPCSender) sign(req *pb.UpdateMetricsBulkRequest) (string, error) {
+	jsonData, err := json.Marshal(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal metrics for signing: %w", err)
+	}
+
+	return hex.EncodeToString(signer.SignPayloadWithKey(jsonData, []byte(s.config.Key))), nil
+}
+
+// encrypt encrypts metrics of req, signature is always calculated over plain metrics.
+func (s *GRPCSender) encrypt(req *pb.UpdateMetricsBulkRequest) ([]byte, error) {
+	data, err := proto.Marshal(&pb.UpdateMetricsBulkRequest{Metrics: req.GetMetrics()})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal metrics for encryption: %w", err)
+	}
+
+	return encryptor.Encrypt(s.config.CryptoKey, data)
+}
@@ -0,0 +1,217 @@
+package client
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"fmt"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/frolmr/metrics/internal/domain"
+	"github.com/frolmr/metrics/internal/server/decryptor"
+	pb "github.com/frolmr/metrics/pkg/proto/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestGRPCSender(t *testing.T) {
+	t.Run("successful report", func(t *testing.T) {
+		cfg := Config{
+			Address: "localhost:8080",
+			HostIP:  testHostIP,
+			Key:     "test-key",
+		}
+
+		lis, err := net.Listen("tcp", cfg.Address)
+		require.NoError(t, err)
+		defer lis.Close()
+
+		s := grpc.NewServer()
+		mockServer := &mockMetricsServer{}
+		pb.RegisterMetricsServer(s, mockServer)
+
+		serveErr := make(chan error, 1)
+		go func() {
+			serveErr <- s.Serve(lis)
+		}()
+		defer s.Stop()
+
+		sender, err := NewGRPCSender(cfg)
+		require.NoError(t, err)
+		defer sender.Close()
+
+		batch := Batch{Gauges: map[string]float64{"test": 1.23}, Counters: map[string]int64{"count": 42}}
+
+		require.NoError(t, sender.Send(context.Background(), batch))
+		require.Equal(t, "127.0.0.1", mockServer.realIP)
+
+		select {
+		case err := <-serveErr:
+			require.NoError(t, err)
+		default:
+		}
+	})
+
+	t.Run("with retries", func(t *testing.T) {
+		cfg := Config{
+			Address:        "invalid-address",
+			RetryIntervals: []time.Duration{time.Millisecond},
+		}
+
+		sender, err := NewGRPCSender(cfg)
+		require.NoError(t, err)
+		defer sender.Close()
+
+		batch := Batch{Gauges: map[string]float64{"test": 1.23}}
+
+		assert.Error(t, sender.Send(context.Background(), batch), "unreachable server")
+	})
+}
+
+func TestGRPCSenderStream(t *testing.T) {
+	lis, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	defer lis.Close()
+
+	s := grpc.NewServer()
+	mockServer := &mockMetricsServer{}
+	pb.RegisterMetricsServer(s, mockServer)
+
+	go func() {
+		_ = s.Serve(lis)
+	}()
+	defer s.Stop()
+
+	cfg := Config{
+		Address:    lis.Addr().String(),
+		HostIP:     testHostIP,
+		Key:        "test-key",
+		GRPCStream: true,
+	}
+
+	sender, err := NewGRPCSender(cfg)
+	require.NoError(t, err)
+	defer sender.Close()
+
+	batch := Batch{Gauges: map[string]float64{"test": 1.23}, Counters: map[string]int64{"count": 42}}
+
+	require.NoError(t, sender.Send(context.Background(), batch))
+	require.NoError(t, sender.Send(context.Background(), batch))
+
+	mockServer.mu.Lock()
+	defer mockServer.mu.Unlock()
+	require.Equal(t, []uint64{1, 2}, mockServer.batchIDs)
+	require.Equal(t, 1, mockServer.streams, "batches should share one stream")
+	require.Equal(t, "127.0.0.1", mockServer.realIP)
+}
+
+func TestGRPCSenderEncryption(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	for _, stream := range []bool{false, true} {
+		t.Run(fmt.Sprintf("stream=%v", stream), func(t *testing.T) {
+			lis, err := net.Listen("tcp", "localhost:0")
+			require.NoError(t, err)
+			defer lis.Close()
+
+			s := grpc.NewServer()
+			mockServer := &mockMetricsServer{}
+			pb.RegisterMetricsServer(s, mockServer)
+
+			go func() {
+				_ = s.Serve(lis)
+			}()
+			defer s.Stop()
+
+			cfg := Config{
+				Address:    lis.Addr().String(),
+				HostIP:     testHostIP,
+				CryptoKey:  &privateKey.PublicKey,
+				GRPCStream: stream,
+			}
+
+			sender, err := NewGRPCSender(cfg)
+			require.NoError(t, err)
+			defer sender.Close()
+
+			batch := Batch{Counters: map[string]int64{"count": 42}}
+
+			require.NoError(t, sender.Send(context.Background(), batch))
+
+			mockServer.mu.Lock()
+			defer mockServer.mu.Unlock()
+			require.Empty(t, mockServer.plainMetrics, "metrics must not be sent in plain")
+			require.NotEmpty(t, mockServer.encrypted)
+
+			data, err := decryptor.NewDecryptor(privateKey).DecryptData(mockServer.encrypted)
+			require.NoError(t, err)
+
+			var payload pb.UpdateMetricsBulkRequest
+			require.NoError(t, proto.Unmarshal(data, &payload))
+			require.Len(t, payload.GetMetrics(), 1)
+			require.Equal(t, "count", payload.GetMetrics()[0].GetKey())
+			require.Equal(t, int64(42), payload.GetMetrics()[0].GetDelta())
+		})
+	}
+}
+
+type mockMetricsServer struct {
+	pb.UnimplementedMetricsServer
+	realIP string
+
+	encrypted    []byte
+	plainMetrics []*pb.Metric
+
+	mu       sync.Mutex
+	streams  int
+	batchIDs []uint64
+}
+
+func (m *mockMetricsServer) StreamMetrics(stream grpc.BidiStreamingServer[pb.MetricsBatch, pb.BatchAck]) error {
+	m.mu.Lock()
+	m.streams++
+	if md, ok := metadata.FromIncomingContext(stream.Context()); ok {
+		if ips := md.Get(domain.RealIPHeader); len(ips) != 0 {
+			m.realIP = ips[0]
+		}
+	}
+	m.mu.Unlock()
+
+	for {
+		batch, err := stream.Recv()
+		if err != nil {
+			return nil
+		}
+
+		m.mu.Lock()
+		m.batchIDs = append(m.batchIDs, batch.GetId())
+		m.encrypted = batch.GetEncryptedMetrics()
+		m.plainMetrics = append(m.plainMetrics, batch.GetMetrics()...)
+		m.mu.Unlock()
+
+		if err := stream.Send(&pb.BatchAck{Id: batch.GetId(), Received: true}); err != nil {
+			return err
+		}
+	}
+}
+
+func (m *mockMetricsServer) UpdateMetricsBulk(ctx context.Context, req *pb.UpdateMetricsBulkRequest) (*pb.Ack, error) {
+	m.mu.Lock()
+	m.encrypted = req.GetEncryptedMetrics()
+	m.plainMetrics = append(m.plainMetrics, req.GetMetrics()...)
+	m.mu.Unlock()
+
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if ips := md.Get(domain.RealIPHeader); len(ips) != 0 {
+			m.realIP = ips[0]
+		}
+	}
+	return &pb.Ack{Received: true}, nil
+}
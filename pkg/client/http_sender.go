@@ -0,0 +1,143 @@
+package client
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/frolmr/metrics/internal/domain"
+	"github.com/frolmr/metrics/pkg/encryptor"
+	"github.com/frolmr/metrics/pkg/signer"
+	"github.com/go-resty/resty/v2"
+)
+
+// HTTPSender sends batches to bulk update endpoint of the server.
+type HTTPSender struct {
+	config Config
+	client *resty.Client
+}
+
+// NewHTTPSender function is constructor for HTTP sender.
+func NewHTTPSender(cfg Config) *HTTPSender {
+	cfg = cfg.withDefaults()
+
+	return &HTTPSender{
+		config: cfg,
+		client: resty.New().SetTimeout(cfg.Timeout),
+	}
+}
+
+func (s *HTTPSender) Close() error {
+	if s.client != nil {
+		s.client.GetClient().CloseIdleConnections()
+	}
+	return nil
+}
+
+// Send posts batch as JSON signed over plain metrics, then encrypted and compressed.
+func (s *HTTPSender) Send(ctx context.Context, batch Batch) error {
+	metrics := make([]domain.Metrics, 0, batch.Len())
+
+	for key, value := range batch.Gauges {
+		metric := domain.Metrics{
+			ID:    key,
+			MType: domain.GaugeType,
+			Value: &value,
+		}
+		metrics = append(metrics, metric)
+	}
+	for key, value := range batch.Counters {
+		metric := domain.Metrics{
+			ID:    key,
+			MType: domain.CounterType,
+			Delta: &value,
+		}
+		metrics = append(metrics, metric)
+	}
+
+	if len(metrics) == 0 {
+		return nil
+	}
+
+	return sendWithRetry(ctx, s.config.RetryIntervals, func(ctx context.Context) error {
+		return s.sendMetrics(ctx, metrics)
+	})
+}
+
+func (s *HTTPSender) sendMetrics(ctx context.Context, metrics []domain.Metrics) error {
+	metricsJSON, err := json.Marshal(metrics)
+	if err != nil {
+		return err
+	}
+
+	encryptedPayload, err := s.encryptPayload(metricsJSON)
+	if err != nil {
+		return fmt.Errorf("encryption failure: %w", err)
+	}
+
+	compressedData, err := s.compressPayload(encryptedPayload)
+	if err != nil {
+		return fmt.Errorf("compression failure: %w", err)
+	}
+
+	cl := s.client.R().
+		SetContext(ctx).
+		SetHeader("Content-Type", domain.JSONContentType).
+		SetHeader("Content-Encoding", domain.CompressFormat).
+		SetBody(compressedData).
+		SetPathParam("serverScheme", s.config.Scheme).
+		SetPathParam("serverHost", s.config.Address)
+
+	if s.config.HostIP != nil {
+		cl.SetHeader(domain.RealIPHeader, s.config.HostIP())
+	}
+
+	if s.config.Key != "" {
+		signature := signer.SignPayloadWithKey(metricsJSON, []byte(s.config.Key))
+		cl.SetHeader(domain.SignatureHeader, hex.EncodeToString(signature))
+	}
+
+	resp, err := cl.
+		Post("{serverScheme}://{serverHost}/updates/")
+	if err != nil {
+		return err
+	}
+
+	if resp.IsError() {
+		return fmt.Errorf("%w: %s", ErrServer, resp.Status())
+	}
+
+	var result domain.UpdateResult
+	if json.Unmarshal(resp.Body(), &result) == nil {
+		for _, r := range result.Rejected {
+			log.Printf("metric %s rejected by server: %s", r.ID, r.Reason)
+		}
+	}
+
+	return nil
+}
+
+func (s *HTTPSender) compressPayload(payload []byte) (*bytes.Buffer, error) {
+	buf := bytes.NewBuffer(nil)
+	zb := gzip.NewWriter(buf)
+	if _, err := zb.Write(payload); err != nil {
+		return nil, err
+	}
+
+	if err := zb.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf, nil
+}
+
+func (s *HTTPSender) encryptPayload(payload []byte) ([]byte, error) {
+	if s.config.CryptoKey == nil {
+		return payload, nil
+	}
+	return encryptor.Encrypt(s.config.CryptoKey, payload)
+}
@@ -1,8 +1,9 @@
-package reporter
+package client
 
 import (
 	"bytes"
 	"compress/gzip"
+	"context"
 	"crypto/rand"
 	"crypto/rsa"
 	"encoding/hex"
@@ -16,8 +17,6 @@ import (
 	"syscall"
 	"testing"
 
-	"github.com/frolmr/metrics/internal/agent/config"
-	"github.com/frolmr/metrics/internal/agent/metrics"
 	"github.com/frolmr/metrics/internal/domain"
 	"github.com/frolmr/metrics/pkg/signer"
 	"github.com/jarcoal/httpmock"
@@ -25,26 +24,31 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+func testHostIP() string {
+	return "127.0.0.1"
+}
+
 func generateTestRSAKeys(t *testing.T) (*rsa.PrivateKey, *rsa.PublicKey) {
 	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
 	require.NoError(t, err)
 	return privateKey, &privateKey.PublicKey
 }
 
-func TestHTTPReporter(t *testing.T) {
+func TestHTTPSenderSend(t *testing.T) {
 	tests := []struct {
 		name         string
-		metrics      metrics.MetricsCollection
+		batch        Batch
 		mockResponse *http.Response
 		mockError    error
+		wantErr      error
 	}{
 		{
 			name: "successful report",
-			metrics: metrics.MetricsCollection{
-				GaugeMetrics: map[string]float64{
+			batch: Batch{
+				Gauges: map[string]float64{
 					"test_gauge": 123.45,
 				},
-				CounterMetrics: map[string]int64{
+				Counters: map[string]int64{
 					"test_counter": 67,
 				},
 			},
@@ -55,8 +59,8 @@ func TestHTTPReporter(t *testing.T) {
 		},
 		{
 			name: "server error - no retry",
-			metrics: metrics.MetricsCollection{
-				GaugeMetrics: map[string]float64{
+			batch: Batch{
+				Gauges: map[string]float64{
 					"test_gauge": 123.45,
 				},
 			},
@@ -64,25 +68,27 @@ func TestHTTPReporter(t *testing.T) {
 				StatusCode: http.StatusInternalServerError,
 				Body:       httpmock.NewRespBodyFromString("Server Error"),
 			},
+			wantErr: ErrServer,
 		},
 		{
 			name: "empty metrics - no request",
-			metrics: metrics.MetricsCollection{
-				GaugeMetrics:   map[string]float64{},
-				CounterMetrics: map[string]int64{},
+			batch: Batch{
+				Gauges:   map[string]float64{},
+				Counters: map[string]int64{},
 			},
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			cfg := &config.Config{
-				Scheme:      "http",
-				HTTPAddress: "localhost:8080",
+			cfg := Config{
+				Scheme:  "http",
+				HostIP:  testHostIP,
+				Address: "localhost:8080",
 			}
 
-			reporter := NewHTTPReporter(cfg)
-			httpmock.ActivateNonDefault(reporter.client.GetClient())
+			sender := NewHTTPSender(cfg)
+			httpmock.ActivateNonDefault(sender.client.GetClient())
 			defer httpmock.DeactivateAndReset()
 
 			if tt.mockResponse != nil {
@@ -99,12 +105,17 @@ func TestHTTPReporter(t *testing.T) {
 				)
 			}
 
-			reporter.ReportMetrics(tt.metrics)
+			err := sender.Send(context.Background(), tt.batch)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
 
 			info := httpmock.GetCallCountInfo()
 			callCount := info["POST http://localhost:8080/updates/"]
 
-			if len(tt.metrics.GaugeMetrics)+len(tt.metrics.CounterMetrics) == 0 {
+			if tt.batch.Len() == 0 {
 				assert.Equal(t, 0, callCount, "expected no request for empty metrics")
 				return
 			}
@@ -114,15 +125,16 @@ func TestHTTPReporter(t *testing.T) {
 	}
 }
 
-func TestReportMetricsWithSignature(t *testing.T) {
-	cfg := &config.Config{
-		Scheme:      "http",
-		HTTPAddress: "localhost:8080",
-		Key:         "test-key",
+func TestHTTPSenderWithSignature(t *testing.T) {
+	cfg := Config{
+		Scheme:  "http",
+		HostIP:  testHostIP,
+		Address: "localhost:8080",
+		Key:     "test-key",
 	}
 
-	reporter := NewHTTPReporter(cfg)
-	httpmock.ActivateNonDefault(reporter.client.GetClient())
+	sender := NewHTTPSender(cfg)
+	httpmock.ActivateNonDefault(sender.client.GetClient())
 	defer httpmock.DeactivateAndReset()
 
 	httpmock.RegisterResponder(
@@ -130,6 +142,7 @@ func TestReportMetricsWithSignature(t *testing.T) {
 		"http://localhost:8080/updates/",
 		func(req *http.Request) (*http.Response, error) {
 			assert.Equal(t, "gzip", req.Header.Get("Content-Encoding"))
+			assert.Equal(t, "127.0.0.1", req.Header.Get(domain.RealIPHeader))
 
 			signature := req.Header.Get(domain.SignatureHeader)
 			assert.NotEmpty(t, signature, "signature header should be present")
@@ -149,19 +162,19 @@ func TestReportMetricsWithSignature(t *testing.T) {
 		},
 	)
 
-	metrics := metrics.MetricsCollection{
-		GaugeMetrics: map[string]float64{
+	batch := Batch{
+		Gauges: map[string]float64{
 			"test_gauge": 123.45,
 		},
 	}
-	reporter.ReportMetrics(metrics)
+	require.NoError(t, sender.Send(context.Background(), batch))
 
 	info := httpmock.GetCallCountInfo()
 	assert.Equal(t, 1, info["POST http://localhost:8080/updates/"])
 }
 
 func TestCompressPayload(t *testing.T) {
-	reporter := NewHTTPReporter(&config.Config{})
+	sender := NewHTTPSender(Config{})
 
 	tests := []struct {
 		name     string
@@ -201,7 +214,7 @@ func TestCompressPayload(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			compressed, err := reporter.compressPayload(tt.input)
+			compressed, err := sender.compressPayload(tt.input)
 			require.NoError(t, err)
 			require.NotNil(t, compressed)
 
@@ -250,19 +263,20 @@ func TestIsConnectionRefused(t *testing.T) {
 	}
 }
 
-func TestReportMetricsWithEncryption(t *testing.T) {
+func TestHTTPSenderWithEncryption(t *testing.T) {
 	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
 	require.NoError(t, err)
 	publicKey := &privateKey.PublicKey
 
-	cfg := &config.Config{
-		Scheme:      "http",
-		HTTPAddress: "localhost:8080",
-		CryptoKey:   publicKey,
+	cfg := Config{
+		Scheme:    "http",
+		HostIP:    testHostIP,
+		Address:   "localhost:8080",
+		CryptoKey: publicKey,
 	}
 
-	reporter := NewHTTPReporter(cfg)
-	httpmock.ActivateNonDefault(reporter.client.GetClient())
+	sender := NewHTTPSender(cfg)
+	httpmock.ActivateNonDefault(sender.client.GetClient())
 	defer httpmock.DeactivateAndReset()
 
 	httpmock.RegisterResponder(
@@ -293,26 +307,27 @@ func TestReportMetricsWithEncryption(t *testing.T) {
 		},
 	)
 
-	metrics := metrics.MetricsCollection{
-		GaugeMetrics: map[string]float64{
+	batch := Batch{
+		Gauges: map[string]float64{
 			"test_gauge": 123.45,
 		},
 	}
-	reporter.ReportMetrics(metrics)
+	require.NoError(t, sender.Send(context.Background(), batch))
 
 	info := httpmock.GetCallCountInfo()
 	assert.Equal(t, 1, info["POST http://localhost:8080/updates/"])
 }
 
-func TestReportMetricsWithoutEncryption(t *testing.T) {
-	cfg := &config.Config{
-		Scheme:      "http",
-		HTTPAddress: "localhost:8080",
-		CryptoKey:   nil,
+func TestHTTPSenderWithoutEncryption(t *testing.T) {
+	cfg := Config{
+		Scheme:    "http",
+		HostIP:    testHostIP,
+		Address:   "localhost:8080",
+		CryptoKey: nil,
 	}
 
-	reporter := NewHTTPReporter(cfg)
-	httpmock.ActivateNonDefault(reporter.client.GetClient())
+	sender := NewHTTPSender(cfg)
+	httpmock.ActivateNonDefault(sender.client.GetClient())
 	defer httpmock.DeactivateAndReset()
 
 	httpmock.RegisterResponder(
@@ -337,31 +352,32 @@ func TestReportMetricsWithoutEncryption(t *testing.T) {
 		},
 	)
 
-	metrics := metrics.MetricsCollection{
-		GaugeMetrics: map[string]float64{
+	batch := Batch{
+		Gauges: map[string]float64{
 			"test_gauge": 123.45,
 		},
 	}
-	reporter.ReportMetrics(metrics)
+	require.NoError(t, sender.Send(context.Background(), batch))
 
 	info := httpmock.GetCallCountInfo()
 	assert.Equal(t, 1, info["POST http://localhost:8080/updates/"])
 }
 
-func TestReportMetricsWithEncryptionAndSignature(t *testing.T) {
+func TestHTTPSenderWithEncryptionAndSignature(t *testing.T) {
 	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
 	require.NoError(t, err)
 	publicKey := &privateKey.PublicKey
 
-	cfg := &config.Config{
-		Scheme:      "http",
-		HTTPAddress: "localhost:8080",
-		CryptoKey:   publicKey,
-		Key:         "test-signature-key",
+	cfg := Config{
+		Scheme:    "http",
+		HostIP:    testHostIP,
+		Address:   "localhost:8080",
+		CryptoKey: publicKey,
+		Key:       "test-signature-key",
 	}
 
-	reporter := NewHTTPReporter(cfg)
-	httpmock.ActivateNonDefault(reporter.client.GetClient())
+	sender := NewHTTPSender(cfg)
+	httpmock.ActivateNonDefault(sender.client.GetClient())
 	defer httpmock.DeactivateAndReset()
 
 	httpmock.RegisterResponder(
@@ -397,28 +413,29 @@ func TestReportMetricsWithEncryptionAndSignature(t *testing.T) {
 		},
 	)
 
-	metrics := metrics.MetricsCollection{
-		GaugeMetrics: map[string]float64{
+	batch := Batch{
+		Gauges: map[string]float64{
 			"test_gauge": 123.45,
 		},
 	}
-	reporter.ReportMetrics(metrics)
+	require.NoError(t, sender.Send(context.Background(), batch))
 
 	info := httpmock.GetCallCountInfo()
 	assert.Equal(t, 1, info["POST http://localhost:8080/updates/"])
 }
 
-func TestReportMetricsWithLargePayloadEncryption(t *testing.T) {
+func TestHTTPSenderWithLargePayloadEncryption(t *testing.T) {
 	privateKey, publicKey := generateTestRSAKeys(t)
 
-	cfg := &config.Config{
-		Scheme:      "http",
-		HTTPAddress: "localhost:8080",
-		CryptoKey:   publicKey,
+	cfg := Config{
+		Scheme:    "http",
+		HostIP:    testHostIP,
+		Address:   "localhost:8080",
+		CryptoKey: publicKey,
 	}
 
-	reporter := NewHTTPReporter(cfg)
-	httpmock.ActivateNonDefault(reporter.client.GetClient())
+	sender := NewHTTPSender(cfg)
+	httpmock.ActivateNonDefault(sender.client.GetClient())
 	defer httpmock.DeactivateAndReset()
 
 	largeGaugeMetrics := make(map[string]float64)
@@ -443,11 +460,11 @@ func TestReportMetricsWithLargePayloadEncryption(t *testing.T) {
 		},
 	)
 
-	metrics := metrics.MetricsCollection{
-		GaugeMetrics:   largeGaugeMetrics,
-		CounterMetrics: map[string]int64{},
+	batch := Batch{
+		Gauges:   largeGaugeMetrics,
+		Counters: map[string]int64{},
 	}
-	reporter.ReportMetrics(metrics)
+	require.NoError(t, sender.Send(context.Background(), batch))
 
 	assert.True(t, len(receivedPayload) > privateKey.Size(), "encrypted payload should be larger than key size")
 	assert.Equal(t, 0, len(receivedPayload)%privateKey.Size(), "payload should be multiple of key size")
@@ -468,7 +485,7 @@ func TestReportMetricsWithLargePayloadEncryption(t *testing.T) {
 	assert.Len(t, metricsList, 100, "should have 100 metrics")
 }
 
-func TestReportMetricsEncryptionFailure(t *testing.T) {
+func TestHTTPSenderEncryptionFailure(t *testing.T) {
 	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
 	require.NoError(t, err)
 	publicKey := &privateKey.PublicKey
@@ -479,15 +496,16 @@ func TestReportMetricsEncryptionFailure(t *testing.T) {
 	rand.Reader = failReader
 	defer func() { rand.Reader = oldReader }()
 
-	cfg := &config.Config{
-		Scheme:      "http",
-		HTTPAddress: "localhost:8080",
-		CryptoKey:   publicKey,
+	cfg := Config{
+		Scheme:    "http",
+		HostIP:    testHostIP,
+		Address:   "localhost:8080",
+		CryptoKey: publicKey,
 	}
 
-	reporter := NewHTTPReporter(cfg)
+	sender := NewHTTPSender(cfg)
 
-	_, err = reporter.encryptPayload([]byte("test data"))
+	_, err = sender.encryptPayload([]byte("test data"))
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "mock reader error")
 }
@@ -0,0 +1,60 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"syscall"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// sendWithRetry repeats send after each of intervals while error says server is unreachable.
+func sendWithRetry(ctx context.Context, intervals []time.Duration, send func(ctx context.Context) error) error {
+	err := send(ctx)
+	for _, interval := range intervals {
+		if err == nil || !isRetriable(err) {
+			return err
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		}
+
+		err = send(ctx)
+	}
+	return err
+}
+
+// isRetriable reports whether batch may be accepted if sent again later.
+func isRetriable(err error) bool {
+	return errors.Is(err, context.DeadlineExceeded) ||
+		isConnectionRefused(err) ||
+		status.Code(err) == codes.Unavailable ||
+		status.Code(err) == codes.DeadlineExceeded
+}
+
+func isConnectionRefused(err error) bool {
+	var netErr *net.OpError
+	if errors.As(err, &netErr) {
+		var syscallErr *os.SyscallError
+		if errors.As(netErr.Err, &syscallErr) {
+			return errors.Is(syscallErr.Err, syscall.ECONNREFUSED)
+		}
+		return errors.Is(netErr.Err, syscall.ECONNREFUSED)
+	}
+
+	var syscallErr *os.SyscallError
+	if errors.As(err, &syscallErr) {
+		return errors.Is(syscallErr.Err, syscall.ECONNREFUSED)
+	}
+
+	return errors.Is(err, syscall.ECONNREFUSED)
+}
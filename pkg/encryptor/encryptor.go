@@ -0,0 +1,72 @@
+// Package encryptor encrypts payloads sent to the server with its RSA public key.
+// Payload is encrypted chunk by chunk, server decrypts it with chunks of key size.
+package encryptor
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+)
+
+var ErrNotRSAKey = errors.New("public key is not RSA")
+
+// LoadPublicKey reads PEM encoded RSA public key, empty path means no key.
+func LoadPublicKey(publicKeyPath string) (*rsa.PublicKey, error) {
+	if publicKeyPath == "" {
+		return nil, nil
+	}
+
+	keyBytes, err := os.ReadFile(publicKeyPath)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(keyBytes)
+	if block == nil {
+		return nil, errors.New("failed to parse PEM block containing the public key")
+	}
+
+	pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	rsaPub, ok := pub.(*rsa.PublicKey)
+	if !ok {
+		return nil, ErrNotRSAKey
+	}
+	return rsaPub, nil
+}
+
+// Encrypt encrypts payload with RSA chunk by chunk.
+func Encrypt(key *rsa.PublicKey, payload []byte) ([]byte, error) {
+	// Calculate maximum chunk size (for 2048-bit key: 245 bytes)
+	maxChunkSize := key.Size() - 11
+
+	var encryptedPayload []byte
+	for _, chunk := range chunkData(payload, maxChunkSize) {
+		encryptedChunk, err := rsa.EncryptPKCS1v15(rand.Reader, key, chunk)
+		if err != nil {
+			return nil, fmt.Errorf("RSA encryption failed: %w", err)
+		}
+		encryptedPayload = append(encryptedPayload, encryptedChunk...)
+	}
+
+	return encryptedPayload, nil
+}
+
+func chunkData(data []byte, chunkSize int) [][]byte {
+	var chunks [][]byte
+	for i := 0; i < len(data); i += chunkSize {
+		end := i + chunkSize
+		if end > len(data) {
+			end = len(data)
+		}
+		chunks = append(chunks, data[i:end])
+	}
+	return chunks
+}
@@ -0,0 +1,63 @@
+package encryptor
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncrypt(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	payload := make([]byte, 600)
+	for i := range payload {
+		payload[i] = byte(i)
+	}
+
+	encrypted, err := Encrypt(&privateKey.PublicKey, payload)
+	require.NoError(t, err)
+	require.Len(t, encrypted, 3*privateKey.Size(), "payload takes three chunks")
+
+	var decrypted []byte
+	for i := 0; i < len(encrypted); i += privateKey.Size() {
+		chunk, err := rsa.DecryptPKCS1v15(nil, privateKey, encrypted[i:i+privateKey.Size()])
+		require.NoError(t, err)
+		decrypted = append(decrypted, chunk...)
+	}
+	assert.Equal(t, payload, decrypted)
+}
+
+func TestLoadPublicKey(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	valid := filepath.Join(dir, "public.pem")
+	require.NoError(t, os.WriteFile(valid, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0600))
+	invalid := filepath.Join(dir, "invalid.pem")
+	require.NoError(t, os.WriteFile(invalid, []byte("not a key"), 0600))
+
+	key, err := LoadPublicKey(valid)
+	require.NoError(t, err)
+	assert.True(t, key.Equal(&privateKey.PublicKey))
+
+	key, err = LoadPublicKey("")
+	assert.NoError(t, err)
+	assert.Nil(t, key, "empty path means no key")
+
+	_, err = LoadPublicKey(invalid)
+	assert.Error(t, err)
+
+	_, err = LoadPublicKey(filepath.Join(dir, "missing.pem"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
@@ -18,19 +18,34 @@ type CommonConfig struct {
 // AgentConfig represents agent-specific configuration from file
 type AgentConfig struct {
 	CommonConfig
-	ReportIntervalSec int `json:"report_interval"`
-	PollIntervalSec   int `json:"poll_interval"`
-	RateLimit         int `json:"rate_limit"`
+	ReportIntervalSec int    `json:"report_interval"`
+	PollIntervalSec   int    `json:"poll_interval"`
+	RateLimit         int    `json:"rate_limit"`
+	HostIP            string `json:"host_ip"`
+	HostInterface     string `json:"host_interface"`
+	GRPCStream        bool   `json:"grpc_stream"`
+	PushAddress       string `json:"push_address"`
+	Collectors        string `json:"collectors"`
 }
 
 // ServerConfig represents server-specific configuration from file
 type ServerConfig struct {
 	CommonConfig
-	Restore          bool   `json:"restore"`
-	StoreIntervalSec int    `json:"store_interval"`
-	StoreFile        string `json:"store_file"`
-	DatabaseDSN      string `json:"database_dsn"`
-	TrustedSubnet    string `json:"trusted_subnet"`
+	Restore                bool   `json:"restore"`
+	StoreIntervalSec       int    `json:"store_interval"`
+	StoreFile              string `json:"store_file"`
+	DatabaseDSN            string `json:"database_dsn"`
+	TrustedSubnet          string `json:"trusted_subnet"`
+	GRPCAddress            string `json:"grpc_address"`
+	MetricNamePattern      string `json:"metric_name_pattern"`
+	MetricNameMaxLength    int    `json:"metric_name_max_length"`
+	GaugeNonFinite         string `json:"gauge_non_finite"`
+	CounterOverflow        string `json:"counter_overflow"`
+	IngestRulesPath        string `json:"ingest_rules"`
+	StatsDAddress          string `json:"statsd_address"`
+	StatsDFlushIntervalSec int    `json:"statsd_flush_interval"`
+	GraphiteAddress        string `json:"graphite_address"`
+	UnsignedIngest         bool   `json:"unsigned_ingest"`
 }
 
 // ReadAgentConfig reads agent configuration from JSON file
@@ -71,10 +71,12 @@ func (Metric_MType) EnumDescriptor() ([]byte, []int) {
 }
 
 type UpdateMetricsBulkRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Metrics       []*Metric              `protobuf:"bytes,1,rep,name=metrics,proto3" json:"metrics,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Metrics []*Metric              `protobuf:"bytes,1,rep,name=metrics,proto3" json:"metrics,omitempty"`
+	// RSA encrypted UpdateMetricsBulkRequest with metrics, used instead of metrics when server has crypto key.
+	EncryptedMetrics []byte `protobuf:"bytes,2,opt,name=encrypted_metrics,json=encryptedMetrics,proto3" json:"encrypted_metrics,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
 }
 
 func (x *UpdateMetricsBulkRequest) Reset() {
@@ -114,6 +116,13 @@ func (x *UpdateMetricsBulkRequest) GetMetrics() []*Metric {
 	return nil
 }
 
+func (x *UpdateMetricsBulkRequest) GetEncryptedMetrics() []byte {
+	if x != nil {
+		return x.EncryptedMetrics
+	}
+	return nil
+}
+
 type Metric struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	Key   string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
@@ -212,17 +221,78 @@ func (*Metric_Delta) isMetric_MValue() {}
 
 func (*Metric_Value) isMetric_MValue() {}
 
+type MetricError struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Index         uint32                 `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	Key           string                 `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	Error         string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MetricError) Reset() {
+	*x = MetricError{}
+	mi := &file_pkg_proto_metrics_metrics_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MetricError) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MetricError) ProtoMessage() {}
+
+func (x *MetricError) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_metrics_metrics_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MetricError.ProtoReflect.Descriptor instead.
+func (*MetricError) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_metrics_metrics_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *MetricError) GetIndex() uint32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *MetricError) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *MetricError) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
 type Ack struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Received      bool                   `protobuf:"varint,1,opt,name=received,proto3" json:"received,omitempty"`
 	Error         *string                `protobuf:"bytes,2,opt,name=error,proto3,oneof" json:"error,omitempty"`
+	Rejected      []*MetricError         `protobuf:"bytes,3,rep,name=rejected,proto3" json:"rejected,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Ack) Reset() {
 	*x = Ack{}
-	mi := &file_pkg_proto_metrics_metrics_proto_msgTypes[2]
+	mi := &file_pkg_proto_metrics_metrics_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -234,7 +304,7 @@ func (x *Ack) String() string {
 func (*Ack) ProtoMessage() {}
 
 func (x *Ack) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_proto_metrics_metrics_proto_msgTypes[2]
+	mi := &file_pkg_proto_metrics_metrics_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -247,7 +317,7 @@ func (x *Ack) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Ack.ProtoReflect.Descriptor instead.
 func (*Ack) Descriptor() ([]byte, []int) {
-	return file_pkg_proto_metrics_metrics_proto_rawDescGZIP(), []int{2}
+	return file_pkg_proto_metrics_metrics_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *Ack) GetReceived() bool {
@@ -264,13 +334,382 @@ func (x *Ack) GetError() string {
 	return ""
 }
 
+func (x *Ack) GetRejected() []*MetricError {
+	if x != nil {
+		return x.Rejected
+	}
+	return nil
+}
+
+type MetricsBatch struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Id        uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Metrics   []*Metric              `protobuf:"bytes,2,rep,name=metrics,proto3" json:"metrics,omitempty"`
+	Signature *string                `protobuf:"bytes,3,opt,name=signature,proto3,oneof" json:"signature,omitempty"`
+	// RSA encrypted UpdateMetricsBulkRequest with metrics, used instead of metrics when server has crypto key.
+	EncryptedMetrics []byte `protobuf:"bytes,4,opt,name=encrypted_metrics,json=encryptedMetrics,proto3" json:"encrypted_metrics,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *MetricsBatch) Reset() {
+	*x = MetricsBatch{}
+	mi := &file_pkg_proto_metrics_metrics_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MetricsBatch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MetricsBatch) ProtoMessage() {}
+
+func (x *MetricsBatch) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_metrics_metrics_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MetricsBatch.ProtoReflect.Descriptor instead.
+func (*MetricsBatch) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_metrics_metrics_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *MetricsBatch) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *MetricsBatch) GetMetrics() []*Metric {
+	if x != nil {
+		return x.Metrics
+	}
+	return nil
+}
+
+func (x *MetricsBatch) GetSignature() string {
+	if x != nil && x.Signature != nil {
+		return *x.Signature
+	}
+	return ""
+}
+
+func (x *MetricsBatch) GetEncryptedMetrics() []byte {
+	if x != nil {
+		return x.EncryptedMetrics
+	}
+	return nil
+}
+
+type BatchAck struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Received      bool                   `protobuf:"varint,2,opt,name=received,proto3" json:"received,omitempty"`
+	Error         *string                `protobuf:"bytes,3,opt,name=error,proto3,oneof" json:"error,omitempty"`
+	Rejected      []*MetricError         `protobuf:"bytes,4,rep,name=rejected,proto3" json:"rejected,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchAck) Reset() {
+	*x = BatchAck{}
+	mi := &file_pkg_proto_metrics_metrics_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchAck) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchAck) ProtoMessage() {}
+
+func (x *BatchAck) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_metrics_metrics_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchAck.ProtoReflect.Descriptor instead.
+func (*BatchAck) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_metrics_metrics_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *BatchAck) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *BatchAck) GetReceived() bool {
+	if x != nil {
+		return x.Received
+	}
+	return false
+}
+
+func (x *BatchAck) GetError() string {
+	if x != nil && x.Error != nil {
+		return *x.Error
+	}
+	return ""
+}
+
+func (x *BatchAck) GetRejected() []*MetricError {
+	if x != nil {
+		return x.Rejected
+	}
+	return nil
+}
+
+type GetMetricRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Type          Metric_MType           `protobuf:"varint,2,opt,name=type,proto3,enum=metrics.Metric_MType" json:"type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetMetricRequest) Reset() {
+	*x = GetMetricRequest{}
+	mi := &file_pkg_proto_metrics_metrics_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetMetricRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMetricRequest) ProtoMessage() {}
+
+func (x *GetMetricRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_metrics_metrics_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMetricRequest.ProtoReflect.Descriptor instead.
+func (*GetMetricRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_metrics_metrics_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetMetricRequest) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *GetMetricRequest) GetType() Metric_MType {
+	if x != nil {
+		return x.Type
+	}
+	return Metric_MTYPE_UNDEFINED
+}
+
+type ListMetricsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Prefix        string                 `protobuf:"bytes,1,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	Type          Metric_MType           `protobuf:"varint,2,opt,name=type,proto3,enum=metrics.Metric_MType" json:"type,omitempty"`
+	PageSize      int32                  `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken     string                 `protobuf:"bytes,4,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListMetricsRequest) Reset() {
+	*x = ListMetricsRequest{}
+	mi := &file_pkg_proto_metrics_metrics_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListMetricsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListMetricsRequest) ProtoMessage() {}
+
+func (x *ListMetricsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_metrics_metrics_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListMetricsRequest.ProtoReflect.Descriptor instead.
+func (*ListMetricsRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_metrics_metrics_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ListMetricsRequest) GetPrefix() string {
+	if x != nil {
+		return x.Prefix
+	}
+	return ""
+}
+
+func (x *ListMetricsRequest) GetType() Metric_MType {
+	if x != nil {
+		return x.Type
+	}
+	return Metric_MTYPE_UNDEFINED
+}
+
+func (x *ListMetricsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListMetricsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type ListMetricsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Metrics       []*Metric              `protobuf:"bytes,1,rep,name=metrics,proto3" json:"metrics,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListMetricsResponse) Reset() {
+	*x = ListMetricsResponse{}
+	mi := &file_pkg_proto_metrics_metrics_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListMetricsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListMetricsResponse) ProtoMessage() {}
+
+func (x *ListMetricsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_metrics_metrics_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListMetricsResponse.ProtoReflect.Descriptor instead.
+func (*ListMetricsResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_metrics_metrics_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ListMetricsResponse) GetMetrics() []*Metric {
+	if x != nil {
+		return x.Metrics
+	}
+	return nil
+}
+
+func (x *ListMetricsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type WatchMetricsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Prefix        string                 `protobuf:"bytes,1,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	Type          Metric_MType           `protobuf:"varint,2,opt,name=type,proto3,enum=metrics.Metric_MType" json:"type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchMetricsRequest) Reset() {
+	*x = WatchMetricsRequest{}
+	mi := &file_pkg_proto_metrics_metrics_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchMetricsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchMetricsRequest) ProtoMessage() {}
+
+func (x *WatchMetricsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_metrics_metrics_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchMetricsRequest.ProtoReflect.Descriptor instead.
+func (*WatchMetricsRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_metrics_metrics_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *WatchMetricsRequest) GetPrefix() string {
+	if x != nil {
+		return x.Prefix
+	}
+	return ""
+}
+
+func (x *WatchMetricsRequest) GetType() Metric_MType {
+	if x != nil {
+		return x.Type
+	}
+	return Metric_MTYPE_UNDEFINED
+}
+
 var File_pkg_proto_metrics_metrics_proto protoreflect.FileDescriptor
 
 const file_pkg_proto_metrics_metrics_proto_rawDesc = "" +
 	"\n" +
-	"\x1fpkg/proto/metrics/metrics.proto\x12\ametrics\"E\n" +
+	"\x1fpkg/proto/metrics/metrics.proto\x12\ametrics\"r\n" +
 	"\x18UpdateMetricsBulkRequest\x12)\n" +
-	"\ametrics\x18\x01 \x03(\v2\x0f.metrics.MetricR\ametrics\"\xc2\x01\n" +
+	"\ametrics\x18\x01 \x03(\v2\x0f.metrics.MetricR\ametrics\x12+\n" +
+	"\x11encrypted_metrics\x18\x02 \x01(\fR\x10encryptedMetrics\"\xc2\x01\n" +
 	"\x06Metric\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12)\n" +
 	"\x04type\x18\x02 \x01(\x0e2\x15.metrics.Metric.MTypeR\x04type\x12\x16\n" +
@@ -280,13 +719,50 @@ const file_pkg_proto_metrics_metrics_proto_rawDesc = "" +
 	"\x0fMTYPE_UNDEFINED\x10\x00\x12\x11\n" +
 	"\rMTYPE_COUNTER\x10\x01\x12\x0f\n" +
 	"\vMTYPE_GAUGE\x10\x02B\t\n" +
-	"\am_value\"F\n" +
+	"\am_value\"K\n" +
+	"\vMetricError\x12\x14\n" +
+	"\x05index\x18\x01 \x01(\rR\x05index\x12\x10\n" +
+	"\x03key\x18\x02 \x01(\tR\x03key\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\"x\n" +
 	"\x03Ack\x12\x1a\n" +
 	"\breceived\x18\x01 \x01(\bR\breceived\x12\x19\n" +
-	"\x05error\x18\x02 \x01(\tH\x00R\x05error\x88\x01\x01B\b\n" +
-	"\x06_error2O\n" +
+	"\x05error\x18\x02 \x01(\tH\x00R\x05error\x88\x01\x01\x120\n" +
+	"\brejected\x18\x03 \x03(\v2\x14.metrics.MetricErrorR\brejectedB\b\n" +
+	"\x06_error\"\xa7\x01\n" +
+	"\fMetricsBatch\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12)\n" +
+	"\ametrics\x18\x02 \x03(\v2\x0f.metrics.MetricR\ametrics\x12!\n" +
+	"\tsignature\x18\x03 \x01(\tH\x00R\tsignature\x88\x01\x01\x12+\n" +
+	"\x11encrypted_metrics\x18\x04 \x01(\fR\x10encryptedMetricsB\f\n" +
+	"\n" +
+	"_signature\"\x8d\x01\n" +
+	"\bBatchAck\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x1a\n" +
+	"\breceived\x18\x02 \x01(\bR\breceived\x12\x19\n" +
+	"\x05error\x18\x03 \x01(\tH\x00R\x05error\x88\x01\x01\x120\n" +
+	"\brejected\x18\x04 \x03(\v2\x14.metrics.MetricErrorR\brejectedB\b\n" +
+	"\x06_error\"O\n" +
+	"\x10GetMetricRequest\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12)\n" +
+	"\x04type\x18\x02 \x01(\x0e2\x15.metrics.Metric.MTypeR\x04type\"\x93\x01\n" +
+	"\x12ListMetricsRequest\x12\x16\n" +
+	"\x06prefix\x18\x01 \x01(\tR\x06prefix\x12)\n" +
+	"\x04type\x18\x02 \x01(\x0e2\x15.metrics.Metric.MTypeR\x04type\x12\x1b\n" +
+	"\tpage_size\x18\x03 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x04 \x01(\tR\tpageToken\"h\n" +
+	"\x13ListMetricsResponse\x12)\n" +
+	"\ametrics\x18\x01 \x03(\v2\x0f.metrics.MetricR\ametrics\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"X\n" +
+	"\x13WatchMetricsRequest\x12\x16\n" +
+	"\x06prefix\x18\x01 \x01(\tR\x06prefix\x12)\n" +
+	"\x04type\x18\x02 \x01(\x0e2\x15.metrics.Metric.MTypeR\x04type2\xd2\x02\n" +
 	"\aMetrics\x12D\n" +
-	"\x11UpdateMetricsBulk\x12!.metrics.UpdateMetricsBulkRequest\x1a\f.metrics.AckB\x0fZ\rmetrics.protob\x06proto3"
+	"\x11UpdateMetricsBulk\x12!.metrics.UpdateMetricsBulkRequest\x1a\f.metrics.Ack\x12=\n" +
+	"\rStreamMetrics\x12\x15.metrics.MetricsBatch\x1a\x11.metrics.BatchAck(\x010\x01\x127\n" +
+	"\tGetMetric\x12\x19.metrics.GetMetricRequest\x1a\x0f.metrics.Metric\x12H\n" +
+	"\vListMetrics\x12\x1b.metrics.ListMetricsRequest\x1a\x1c.metrics.ListMetricsResponse\x12?\n" +
+	"\fWatchMetrics\x12\x1c.metrics.WatchMetricsRequest\x1a\x0f.metrics.Metric0\x01B\x0fZ\rmetrics.protob\x06proto3"
 
 var (
 	file_pkg_proto_metrics_metrics_proto_rawDescOnce sync.Once
@@ -301,23 +777,45 @@ func file_pkg_proto_metrics_metrics_proto_rawDescGZIP() []byte {
 }
 
 var file_pkg_proto_metrics_metrics_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_pkg_proto_metrics_metrics_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_pkg_proto_metrics_metrics_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
 var file_pkg_proto_metrics_metrics_proto_goTypes = []any{
 	(Metric_MType)(0),                // 0: metrics.Metric.MType
 	(*UpdateMetricsBulkRequest)(nil), // 1: metrics.UpdateMetricsBulkRequest
 	(*Metric)(nil),                   // 2: metrics.Metric
-	(*Ack)(nil),                      // 3: metrics.Ack
+	(*MetricError)(nil),              // 3: metrics.MetricError
+	(*Ack)(nil),                      // 4: metrics.Ack
+	(*MetricsBatch)(nil),             // 5: metrics.MetricsBatch
+	(*BatchAck)(nil),                 // 6: metrics.BatchAck
+	(*GetMetricRequest)(nil),         // 7: metrics.GetMetricRequest
+	(*ListMetricsRequest)(nil),       // 8: metrics.ListMetricsRequest
+	(*ListMetricsResponse)(nil),      // 9: metrics.ListMetricsResponse
+	(*WatchMetricsRequest)(nil),      // 10: metrics.WatchMetricsRequest
 }
 var file_pkg_proto_metrics_metrics_proto_depIdxs = []int32{
-	2, // 0: metrics.UpdateMetricsBulkRequest.metrics:type_name -> metrics.Metric
-	0, // 1: metrics.Metric.type:type_name -> metrics.Metric.MType
-	1, // 2: metrics.Metrics.UpdateMetricsBulk:input_type -> metrics.UpdateMetricsBulkRequest
-	3, // 3: metrics.Metrics.UpdateMetricsBulk:output_type -> metrics.Ack
-	3, // [3:4] is the sub-list for method output_type
-	2, // [2:3] is the sub-list for method input_type
-	2, // [2:2] is the sub-list for extension type_name
-	2, // [2:2] is the sub-list for extension extendee
-	0, // [0:2] is the sub-list for field type_name
+	2,  // 0: metrics.UpdateMetricsBulkRequest.metrics:type_name -> metrics.Metric
+	0,  // 1: metrics.Metric.type:type_name -> metrics.Metric.MType
+	3,  // 2: metrics.Ack.rejected:type_name -> metrics.MetricError
+	2,  // 3: metrics.MetricsBatch.metrics:type_name -> metrics.Metric
+	3,  // 4: metrics.BatchAck.rejected:type_name -> metrics.MetricError
+	0,  // 5: metrics.GetMetricRequest.type:type_name -> metrics.Metric.MType
+	0,  // 6: metrics.ListMetricsRequest.type:type_name -> metrics.Metric.MType
+	2,  // 7: metrics.ListMetricsResponse.metrics:type_name -> metrics.Metric
+	0,  // 8: metrics.WatchMetricsRequest.type:type_name -> metrics.Metric.MType
+	1,  // 9: metrics.Metrics.UpdateMetricsBulk:input_type -> metrics.UpdateMetricsBulkRequest
+	5,  // 10: metrics.Metrics.StreamMetrics:input_type -> metrics.MetricsBatch
+	7,  // 11: metrics.Metrics.GetMetric:input_type -> metrics.GetMetricRequest
+	8,  // 12: metrics.Metrics.ListMetrics:input_type -> metrics.ListMetricsRequest
+	10, // 13: metrics.Metrics.WatchMetrics:input_type -> metrics.WatchMetricsRequest
+	4,  // 14: metrics.Metrics.UpdateMetricsBulk:output_type -> metrics.Ack
+	6,  // 15: metrics.Metrics.StreamMetrics:output_type -> metrics.BatchAck
+	2,  // 16: metrics.Metrics.GetMetric:output_type -> metrics.Metric
+	9,  // 17: metrics.Metrics.ListMetrics:output_type -> metrics.ListMetricsResponse
+	2,  // 18: metrics.Metrics.WatchMetrics:output_type -> metrics.Metric
+	14, // [14:19] is the sub-list for method output_type
+	9,  // [9:14] is the sub-list for method input_type
+	9,  // [9:9] is the sub-list for extension type_name
+	9,  // [9:9] is the sub-list for extension extendee
+	0,  // [0:9] is the sub-list for field type_name
 }
 
 func init() { file_pkg_proto_metrics_metrics_proto_init() }
@@ -329,14 +827,16 @@ func file_pkg_proto_metrics_metrics_proto_init() {
 		(*Metric_Delta)(nil),
 		(*Metric_Value)(nil),
 	}
-	file_pkg_proto_metrics_metrics_proto_msgTypes[2].OneofWrappers = []any{}
+	file_pkg_proto_metrics_metrics_proto_msgTypes[3].OneofWrappers = []any{}
+	file_pkg_proto_metrics_metrics_proto_msgTypes[4].OneofWrappers = []any{}
+	file_pkg_proto_metrics_metrics_proto_msgTypes[5].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_pkg_proto_metrics_metrics_proto_rawDesc), len(file_pkg_proto_metrics_metrics_proto_rawDesc)),
 			NumEnums:      1,
-			NumMessages:   3,
+			NumMessages:   10,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -42,52 +42,676 @@ const docTemplate = `{
                 }
             }
         },
-        "/metrics/{type}/{name}": {
+        "/api/v1/export": {
+            "get": {
+                "description": "Streams all metrics as CSV with id, type and value columns, as newline delimited domain.Metrics JSON\nor as JSON array of domain.Metrics which is accepted by /api/v1/updates.\nNaN and Inf gauges are written to CSV only, JSON formats skip them.\nConnection is aborted if storage fails in the middle of export.",
+                "produces": [
+                    "text/csv",
+                    "application/x-ndjson",
+                    "application/json"
+                ],
+                "tags": [
+                    "metrics"
+                ],
+                "summary": "Export metrics",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "json",
+                        "description": "Export format: csv, ndjson or json",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Type of the metrics (gauge or counter)",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name prefix",
+                        "name": "prefix",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "name",
+                        "description": "Sort order: name, -name, type or -type",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Metrics in requested format",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.Metrics"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid query",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/import": {
+            "post": {
+                "description": "Accepts JSON array of domain.Metrics as written by snapshots and export, or NDJSON with one metric per line.\nGauges are overwritten, counters are added to existing values or overwritten depending on counters parameter.",
+                "consumes": [
+                    "application/json",
+                    "application/x-ndjson"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "metrics"
+                ],
+                "summary": "Import metrics",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "add",
+                        "description": "Counter mode: add or overwrite",
+                        "name": "counters",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Metrics dump",
+                        "name": "metrics",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.Metrics"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Import result, at least one metric imported",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ImportResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid dump or all metrics rejected, details are ImportResult",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Error"
+                        }
+                    },
+                    "415": {
+                        "description": "Body is neither JSON nor NDJSON",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error, details are ImportResult",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/metrics": {
+            "get": {
+                "description": "Returns metrics filtered by type and name prefix in stable order, next page is requested with next_cursor of the previous one.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "metrics"
+                ],
+                "summary": "List metrics",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Type of the metrics (gauge or counter)",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name prefix",
+                        "name": "prefix",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, 100 by default, 1000 at most",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "name",
+                        "description": "Sort order: name, -name, type or -type",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Page of metrics",
+                        "schema": {
+                            "$ref": "#/definitions/domain.MetricsPage"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid query",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/ping": {
+            "get": {
+                "tags": [
+                    "Health"
+                ],
+                "summary": "Request for API health check",
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    },
+                    "503": {
+                        "description": "Storage unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/prom/write": {
+            "post": {
+                "description": "Accepts snappy compressed WriteRequest of Prometheus remote write protocol 1.0.\nMetric name is __name__ label with values of labels listed in ingest rules tags.\nCounters are recognized by ingest rules, metadata and _total suffix and converted to deltas\nstarting from the second sample of every series, all the other series are gauges.\nBodies are neither decrypted nor signature checked, so with KEY or CRYPTO_KEY set the route is forbidden\nunless UNSIGNED_INGEST is enabled.",
+                "consumes": [
+                    "application/x-protobuf"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "metrics"
+                ],
+                "summary": "Receive Prometheus remote write",
+                "parameters": [
+                    {
+                        "description": "Snappy compressed WriteRequest",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "All series saved"
+                    },
+                    "400": {
+                        "description": "Request can't be decoded or some series rejected, details are RemoteWriteErrors",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Error"
+                        }
+                    },
+                    "403": {
+                        "description": "Unsigned writes are disabled while KEY or CRYPTO_KEY is set",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Error"
+                        }
+                    },
+                    "413": {
+                        "description": "Request is too large",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Error"
+                        }
+                    },
+                    "415": {
+                        "description": "Request is not snappy compressed protobuf",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/stream": {
+            "get": {
+                "description": "Pushes every accepted update as \"metric\" server-sent event with domain.Metrics JSON data.\nUpdates that don't fit into subscriber buffer are dropped, client is told about it by \"dropped\" event with total number of dropped updates.",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "metrics"
+                ],
+                "summary": "Stream metric updates",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Type of the metrics (gauge or counter)",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Name prefix, may be repeated to watch several prefixes",
+                        "name": "prefix",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Stream of metric events",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Metrics"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid metric type",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Error"
+                        }
+                    },
+                    "406": {
+                        "description": "Event stream is not acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Error"
+                        }
+                    },
+                    "503": {
+                        "description": "Stream is not enabled",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/update": {
+            "post": {
+                "description": "Updates a metric with the provided JSON payload.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "metrics"
+                ],
+                "summary": "Update a metric",
+                "parameters": [
+                    {
+                        "description": "Metric data to update",
+                        "name": "metrics",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.Metrics"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated metric",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Metrics"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload, metric type or value",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Error"
+                        }
+                    },
+                    "415": {
+                        "description": "Body is not JSON",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/update/{type}/{name}/{value}": {
+            "post": {
+                "description": "Updates a metric with the provided type, name, and value in the URL path.\nVersioned route responds with updated metric as JSON.",
+                "consumes": [
+                    "text/plain"
+                ],
+                "produces": [
+                    "text/plain",
+                    "application/json"
+                ],
+                "tags": [
+                    "metrics"
+                ],
+                "summary": "Update a metric",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Type of the metric (gauge or counter)",
+                        "name": "type",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of the metric",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Value of the metric",
+                        "name": "value",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Metric updated successfully",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid metric type or value",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/updates": {
+            "post": {
+                "description": "Updates multiple metrics with the provided JSON payload and reports accepted and rejected metrics.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "metrics"
+                ],
+                "summary": "Update multiple metrics",
+                "parameters": [
+                    {
+                        "description": "List of metrics to update",
+                        "name": "metrics",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.Metrics"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Per-metric result, at least one metric accepted",
+                        "schema": {
+                            "$ref": "#/definitions/domain.UpdateResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload or all metrics rejected, versioned route wraps result into apierror.Error details",
+                        "schema": {
+                            "$ref": "#/definitions/domain.UpdateResult"
+                        }
+                    },
+                    "415": {
+                        "description": "Body is not JSON",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error, versioned route wraps result into apierror.Error details",
+                        "schema": {
+                            "$ref": "#/definitions/domain.UpdateResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/value": {
+            "post": {
+                "description": "Retrieves a metric with the provided JSON payload.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "metrics"
+                ],
+                "summary": "Get a metric",
+                "parameters": [
+                    {
+                        "description": "Metric data to retrieve",
+                        "name": "metrics",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.Metrics"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Requested metric",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Metrics"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload or metric type",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Metric not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Error"
+                        }
+                    },
+                    "415": {
+                        "description": "Body is not JSON",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/value/{type}/{name}": {
             "get": {
                 "description": "Fetches the value of a metric based on the provided type and name.",
                 "consumes": [
                     "text/plain"
                 ],
                 "produces": [
-                    "text/plain"
+                    "text/plain",
+                    "application/json"
+                ],
+                "tags": [
+                    "Metrics"
+                ],
+                "summary": "Get a metric by type and name",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Type of the metric",
+                        "name": "type",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of the metric",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The value of the metric",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Metric not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Error"
+                        }
+                    },
+                    "406": {
+                        "description": "Neither JSON nor plain text is acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/write": {
+            "post": {
+                "description": "Saves metrics sent in InfluxDB line protocol, compatible with Telegraf InfluxDB outputs.\nLines which fail to parse or to validate are reported, all the other lines are saved.\nBodies are neither decrypted nor signature checked, so with KEY or CRYPTO_KEY set the route is forbidden\nunless UNSIGNED_INGEST is enabled.",
+                "consumes": [
+                    "text/plain"
+                ],
+                "produces": [
+                    "application/json"
                 ],
                 "tags": [
-                    "Metrics"
+                    "metrics"
                 ],
-                "summary": "Get a metric by type and name",
+                "summary": "Write metrics in line protocol",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Type of the metric",
-                        "name": "type",
-                        "in": "path",
-                        "required": true
+                        "description": "Timestamp precision (ns, us, ms, s), timestamps are not stored",
+                        "name": "precision",
+                        "in": "query"
                     },
                     {
-                        "type": "string",
-                        "description": "Name of the metric",
-                        "name": "name",
-                        "in": "path",
-                        "required": true
+                        "description": "Metrics in line protocol",
+                        "name": "lines",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "The value of the metric",
+                    "204": {
+                        "description": "All lines saved"
+                    },
+                    "400": {
+                        "description": "Unknown precision or some lines rejected, details are WriteErrors",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/apierror.Error"
                         }
                     },
-                    "400": {
-                        "description": "Invalid request",
+                    "403": {
+                        "description": "Unsigned writes are disabled while KEY or CRYPTO_KEY is set",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/apierror.Error"
                         }
                     },
-                    "404": {
-                        "description": "Metric not found",
+                    "500": {
+                        "description": "Internal server error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/apierror.Error"
                         }
                     }
                 }
@@ -95,12 +719,6 @@ const docTemplate = `{
         },
         "/ping": {
             "get": {
-                "consumes": [
-                    "text/plain"
-                ],
-                "produces": [
-                    "text/plain"
-                ],
                 "tags": [
                     "Health"
                 ],
@@ -111,6 +729,12 @@ const docTemplate = `{
                     },
                     "500": {
                         "description": "Internal Server Error"
+                    },
+                    "503": {
+                        "description": "Storage unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Error"
+                        }
                     }
                 }
             }
@@ -147,23 +771,88 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Invalid request payload or metric type",
+                        "description": "Invalid request payload, metric type or value",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/apierror.Error"
+                        }
+                    },
+                    "415": {
+                        "description": "Body is not JSON",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Error"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/update/{type}/{name}/{value}": {
+            "post": {
+                "description": "Updates a metric with the provided type, name, and value in the URL path.\nVersioned route responds with updated metric as JSON.",
+                "consumes": [
+                    "text/plain"
+                ],
+                "produces": [
+                    "text/plain",
+                    "application/json"
+                ],
+                "tags": [
+                    "metrics"
+                ],
+                "summary": "Update a metric",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Type of the metric (gauge or counter)",
+                        "name": "type",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of the metric",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Value of the metric",
+                        "name": "value",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Metric updated successfully",
                         "schema": {
                             "type": "string"
                         }
+                    },
+                    "400": {
+                        "description": "Invalid metric type or value",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Error"
+                        }
                     }
                 }
             }
         },
         "/updates": {
             "post": {
-                "description": "Updates multiple metrics with the provided JSON payload.",
+                "description": "Updates multiple metrics with the provided JSON payload and reports accepted and rejected metrics.",
                 "consumes": [
                     "application/json"
                 ],
@@ -190,13 +879,85 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Metrics updated successfully",
+                        "description": "Per-metric result, at least one metric accepted",
+                        "schema": {
+                            "$ref": "#/definitions/domain.UpdateResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload or all metrics rejected, versioned route wraps result into apierror.Error details",
+                        "schema": {
+                            "$ref": "#/definitions/domain.UpdateResult"
+                        }
+                    },
+                    "415": {
+                        "description": "Body is not JSON",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error, versioned route wraps result into apierror.Error details",
+                        "schema": {
+                            "$ref": "#/definitions/domain.UpdateResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/metrics": {
+            "post": {
+                "description": "Accepts OTLP/HTTP ExportMetricsServiceRequest in binary protobuf or JSON encoding.\nGauges and non-monotonic cumulative sums become gauges, monotonic and delta sums become counters,\ncumulative values are converted to deltas starting from the second point of every series.\nHistograms become name_count counter and name_mean, name_min and name_max gauges.\nBodies are neither decrypted nor signature checked, so with KEY or CRYPTO_KEY set the route is forbidden\nunless UNSIGNED_INGEST is enabled.",
+                "consumes": [
+                    "application/x-protobuf",
+                    "application/json"
+                ],
+                "produces": [
+                    "application/x-protobuf",
+                    "application/json"
+                ],
+                "tags": [
+                    "metrics"
+                ],
+                "summary": "Receive OpenTelemetry metrics",
+                "parameters": [
+                    {
+                        "description": "ExportMetricsServiceRequest",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ExportMetricsServiceResponse, partial_success is set if some data points are rejected",
                         "schema": {
                             "type": "string"
                         }
                     },
                     "400": {
-                        "description": "Invalid request payload",
+                        "description": "Request can't be decoded",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Unsigned writes are disabled while KEY or CRYPTO_KEY is set",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Error"
+                        }
+                    },
+                    "413": {
+                        "description": "Request is too large",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported content type",
                         "schema": {
                             "type": "string"
                         }
@@ -244,26 +1005,134 @@ const docTemplate = `{
                     "400": {
                         "description": "Invalid request payload or metric type",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/apierror.Error"
                         }
                     },
                     "404": {
                         "description": "Metric not found",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/apierror.Error"
+                        }
+                    },
+                    "415": {
+                        "description": "Body is not JSON",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Error"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/value/{type}/{name}": {
+            "get": {
+                "description": "Fetches the value of a metric based on the provided type and name.",
+                "consumes": [
+                    "text/plain"
+                ],
+                "produces": [
+                    "text/plain",
+                    "application/json"
+                ],
+                "tags": [
+                    "Metrics"
+                ],
+                "summary": "Get a metric by type and name",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Type of the metric",
+                        "name": "type",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of the metric",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The value of the metric",
                         "schema": {
                             "type": "string"
                         }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Metric not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Error"
+                        }
+                    },
+                    "406": {
+                        "description": "Neither JSON nor plain text is acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Error"
+                        }
                     }
                 }
             }
         }
     },
     "definitions": {
+        "apierror.Error": {
+            "description": "API error.",
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "Code is a stable machine readable error code.\nExample: \"validation_failed\"",
+                    "type": "string"
+                },
+                "details": {
+                    "description": "Details holds optional structured information about the error."
+                },
+                "message": {
+                    "description": "Message is a human readable description.\nExample: \"invalid metric name\"",
+                    "type": "string"
+                }
+            }
+        },
+        "domain.MetricStatus": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "description": "ID is the name of the metric.",
+                    "type": "string"
+                },
+                "index": {
+                    "description": "Index is the position of the metric in the request.",
+                    "type": "integer"
+                },
+                "reason": {
+                    "description": "Reason explains why metric was rejected.",
+                    "type": "string"
+                },
+                "type": {
+                    "description": "MType is the type of the metric.",
+                    "type": "string"
+                }
+            }
+        },
         "domain.Metrics": {
             "description": "Metrics request payload for metrics data.",
             "type": "object",
@@ -285,6 +1154,62 @@ const docTemplate = `{
                     "type": "number"
                 }
             }
+        },
+        "domain.MetricsPage": {
+            "description": "Page of metrics listing.",
+            "type": "object",
+            "properties": {
+                "metrics": {
+                    "description": "Metrics of the page in requested order.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.Metrics"
+                    }
+                },
+                "next_cursor": {
+                    "description": "NextCursor is passed as cursor to get the next page, empty on the last page.",
+                    "type": "string"
+                }
+            }
+        },
+        "domain.UpdateResult": {
+            "description": "Per-metric result of bulk update.",
+            "type": "object",
+            "properties": {
+                "accepted": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.MetricStatus"
+                    }
+                },
+                "rejected": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.MetricStatus"
+                    }
+                }
+            }
+        },
+        "handlers.ImportResult": {
+            "description": "Result of import, metrics before failed one are saved even if import fails.",
+            "type": "object",
+            "properties": {
+                "errors": {
+                    "description": "Errors lists the first rejected metrics, index is the position of metric in the dump.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.MetricStatus"
+                    }
+                },
+                "imported": {
+                    "description": "Imported is the number of saved metrics.",
+                    "type": "integer"
+                },
+                "rejected": {
+                    "description": "Rejected is the number of metrics failed validation.",
+                    "type": "integer"
+                }
+            }
         }
     },
     "tags": [